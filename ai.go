@@ -2,22 +2,79 @@ package ai
 
 import (
 	"context"
+	"slices"
 
 	"go.jetify.com/ai/api"
 )
 
+// EmbedMany embeds values using the given embedding model.
+//
+// Values are split into chunks that respect the model's MaxEmbeddingsPerCall
+// limit. When the model supports parallel calls, the chunks are sent
+// concurrently (see [WithTransportMaxParallelCalls]); otherwise they are sent
+// one after the other. Embeddings are returned in input order and usage is
+// summed across chunks. If a chunk fails, or ctx is cancelled before every
+// chunk has been embedded, the returned error is an [api.EmbeddingBatchError]
+// describing the range of inputs that failed.
 func EmbedMany[T api.EmbeddingInput, E api.EmbeddingVector](
 	ctx context.Context, model api.EmbeddingModel[T, E], values []T, opts ...TransportOption,
 ) (api.EmbeddingResponse[E], error) {
 	config := buildTransportConfig(opts)
-	return model.DoEmbed(ctx, values, config)
+	ctx, span := startSpan(ctx, config.TracerProvider, "ai.EmbedMany", operationEmbeddings, model)
+	span.recordChunks(len(chunkRanges(len(values), model.MaxEmbeddingsPerCall())))
+	resp, err := embedMany(ctx, model, values, config)
+	if err == nil && resp.Usage != nil {
+		span.recordUsage(int(resp.Usage.PromptTokens), 0)
+	}
+	span.end(err)
+	return resp, err
 }
 
+// RankMany ranks texts by their relevance to query using the given ranking
+// model.
+//
+// The response has one score per text in input order, and its Results hold
+// the texts sorted by descending score. Use [WithTopN] to keep only the most
+// relevant results and [WithReturnDocuments] to include the text of each
+// result.
 func RankMany(
 	ctx context.Context, model api.RankingModel, query string, texts []string, opts ...TransportOption,
 ) (api.RankingResponse, error) {
 	config := buildTransportConfig(opts)
-	return model.DoRank(ctx, query, texts, config)
+	ctx, span := startSpan(ctx, config.TracerProvider, "ai.RankMany", operationRerank, model)
+	resp, err := retry(ctx, config.RetryPolicy, func() (api.RankingResponse, error) {
+		return model.DoRank(ctx, query, texts, config)
+	})
+	if err == nil && resp.Usage != nil {
+		span.recordUsage(int(resp.Usage.TotalTokens), 0)
+	}
+	span.end(err)
+	if err != nil {
+		return resp, err
+	}
+	return rankingResults(resp, texts, config), nil
+}
+
+// rankingResults fills in the Results of resp for models that only return
+// scores, then applies the TopN and ReturnDocuments options for models that
+// do not support them natively.
+func rankingResults(resp api.RankingResponse, texts []string, config api.TransportOptions) api.RankingResponse {
+	if resp.Results == nil {
+		resp.Results = api.RankingResultsFromScores(resp.Scores)
+	} else {
+		resp.Results = slices.Clone(resp.Results)
+	}
+	if topN := config.TopN; topN > 0 && len(resp.Results) > topN {
+		resp.Results = resp.Results[:topN]
+	}
+	if config.ReturnDocuments {
+		for i, result := range resp.Results {
+			if result.Document == "" && result.Index >= 0 && result.Index < len(texts) {
+				resp.Results[i].Document = texts[result.Index]
+			}
+		}
+	}
+	return resp
 }
 
 // SegmentMany provides a Segmenter-style API that mirrors chunking for now.
@@ -25,7 +82,12 @@ func SegmentMany(
 	ctx context.Context, model api.SegmentingModel, texts []string, opts ...TransportOption,
 ) (api.SegmentingResponse, error) {
 	config := buildTransportConfig(opts)
-	return model.DoSegment(ctx, texts, config)
+	ctx, span := startSpan(ctx, config.TracerProvider, "ai.SegmentMany", operationSegment, model)
+	resp, err := retry(ctx, config.RetryPolicy, func() (api.SegmentingResponse, error) {
+		return model.DoSegment(ctx, texts, config)
+	})
+	span.end(err)
+	return resp, err
 }
 
 // TODO: do we want to rename from GenerateText to Generate and from StreamText to Stream?
@@ -56,9 +118,20 @@ func SegmentMany(
 // The last argument can optionally be a series of [GenerateOption] arguments:
 //
 //	GenerateText(ctx, messages, WithMaxTokens(100))
+//
+// Tools registered with [WithExecutableTools] are executed automatically when
+// the model calls them. Combined with [WithMaxSteps], GenerateText keeps
+// calling the model with the tool results until it stops requesting tools;
+// every intermediate call is available in [api.Response.Steps].
 func GenerateText(ctx context.Context, prompt []api.Message, opts ...GenerateOption) (*api.Response, error) {
 	config := buildGenerateConfig(opts)
-	return generate(ctx, prompt, config)
+	ctx, span := startSpan(ctx, config.TracerProvider, "ai.GenerateText", operationChat, config.Model)
+	resp, err := generate(ctx, prompt, config)
+	if err == nil {
+		span.recordResponse(resp)
+	}
+	span.end(err)
+	return resp, err
 }
 
 // GenerateTextStr uses a language model to generate a text response from a given string prompt.
@@ -83,7 +156,18 @@ func GenerateTextStr(ctx context.Context, prompt string, opts ...GenerateOption)
 }
 
 func generate(ctx context.Context, prompt []api.Message, opts GenerateOptions) (*api.Response, error) {
-	return opts.Model.Generate(ctx, prompt, opts.CallOptions)
+	if len(opts.ToolExecutors) > 0 {
+		return generateSteps(ctx, prompt, opts)
+	}
+	return generateOnce(ctx, prompt, opts)
+}
+
+// generateOnce makes a single model call, retrying it according to the
+// configured retry policy.
+func generateOnce(ctx context.Context, prompt []api.Message, opts GenerateOptions) (*api.Response, error) {
+	return retry(ctx, opts.RetryPolicy, func() (*api.Response, error) {
+		return opts.Model.Generate(ctx, prompt, opts.CallOptions)
+	})
 }
 
 // StreamText uses a language model to generate a streaming text response from a given prompt.
@@ -114,7 +198,13 @@ func generate(ctx context.Context, prompt []api.Message, opts GenerateOptions) (
 //	StreamText(ctx, messages, WithMaxTokens(100))
 func StreamText(ctx context.Context, prompt []api.Message, opts ...GenerateOption) (*api.StreamResponse, error) {
 	config := buildGenerateConfig(opts)
-	return stream(ctx, prompt, config)
+	ctx, span := startSpan(ctx, config.TracerProvider, "ai.StreamText", operationChat, config.Model)
+	resp, err := stream(ctx, prompt, config)
+	if err != nil || resp == nil {
+		span.end(err)
+		return resp, err
+	}
+	return span.traceStream(resp), nil
 }
 
 // StreamTextStr uses a language model to generate a streaming text response from a given string prompt.
@@ -139,5 +229,7 @@ func StreamTextStr(ctx context.Context, prompt string, opts ...GenerateOption) (
 }
 
 func stream(ctx context.Context, prompt []api.Message, opts GenerateOptions) (*api.StreamResponse, error) {
-	return opts.Model.Stream(ctx, prompt, opts.CallOptions)
+	return retryStream(ctx, opts.RetryPolicy, func() (*api.StreamResponse, error) {
+		return opts.Model.Stream(ctx, prompt, opts.CallOptions)
+	})
 }
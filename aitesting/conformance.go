@@ -0,0 +1,359 @@
+package aitesting
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+)
+
+// The conformance suites check that a model implementation honours the
+// contract of its api interface. Each suite runs against a model backed by a
+// local stub (an httptest server, a [Recorder] cassette, ...) so that
+// third-party providers can prove they conform without network access:
+//
+//	func TestConformance(t *testing.T) {
+//		server := httptest.NewServer(stubHandler)
+//		defer server.Close()
+//
+//		aitesting.EmbeddingModelSuite[string, api.Embedding]{
+//			Model:  newModel(server.URL),
+//			Inputs: []string{"first", "second", "third"},
+//		}.Run(t)
+//	}
+//
+// The stubs must be deterministic: sending the same input twice must produce
+// the same output.
+
+// EmbeddingModelSuite checks the contract of an [api.EmbeddingModel].
+type EmbeddingModelSuite[T api.EmbeddingInput, E api.EmbeddingVector] struct {
+	// Model is the model under test.
+	Model api.EmbeddingModel[T, E]
+
+	// Inputs are at least two distinct values to embed.
+	Inputs []T
+
+	// FailingModel, if set, is a model whose backend fails every request with
+	// an HTTP error status. It is used to check that such failures are
+	// reported as an [api.APICallError].
+	FailingModel api.EmbeddingModel[T, E]
+}
+
+// Run runs the suite as subtests of t.
+func (s EmbeddingModelSuite[T, E]) Run(t *testing.T) {
+	require.GreaterOrEqual(t, len(s.Inputs), 2, "EmbeddingModelSuite needs at least two inputs")
+
+	t.Run("metadata", func(t *testing.T) {
+		checkMetadata(t, s.Model.SpecificationVersion(), s.Model.ProviderName(), s.Model.ModelID())
+		if limit := s.Model.MaxEmbeddingsPerCall(); limit != nil {
+			assert.Positive(t, *limit, "MaxEmbeddingsPerCall must be nil or positive")
+		}
+	})
+
+	t.Run("preserves input order", func(t *testing.T) {
+		resp, err := s.Model.DoEmbed(t.Context(), s.Inputs, api.TransportOptions{})
+		require.NoError(t, err)
+		require.Len(t, resp.Embeddings, len(s.Inputs), "one embedding per input")
+		for i, embedding := range resp.Embeddings {
+			assert.NotEmpty(t, embedding, "embedding %d is empty", i)
+		}
+
+		reversed, err := s.Model.DoEmbed(t.Context(), reverse(s.Inputs), api.TransportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, reverse(resp.Embeddings), reversed.Embeddings,
+			"reversing the inputs must reverse the embeddings")
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		resp, err := s.Model.DoEmbed(t.Context(), []T{}, api.TransportOptions{})
+		if err == nil {
+			assert.Empty(t, resp.Embeddings, "embedding no values must return no embeddings")
+		}
+	})
+
+	t.Run("context cancellation", func(t *testing.T) {
+		_, err := s.Model.DoEmbed(cancelledContext(t), s.Inputs, api.TransportOptions{})
+		checkCancelled(t, err)
+	})
+
+	if s.FailingModel != nil {
+		t.Run("error typing", func(t *testing.T) {
+			_, err := s.FailingModel.DoEmbed(t.Context(), s.Inputs, api.TransportOptions{})
+			checkAPICallError(t, err)
+		})
+	}
+}
+
+// RankingModelSuite checks the contract of an [api.RankingModel].
+type RankingModelSuite struct {
+	// Model is the model under test.
+	Model api.RankingModel
+
+	// Query is the query to rank Texts against.
+	Query string
+
+	// Texts are at least two distinct texts to rank.
+	Texts []string
+
+	// FailingModel, if set, is a model whose backend fails every request with
+	// an HTTP error status. It is used to check that such failures are
+	// reported as an [api.APICallError].
+	FailingModel api.RankingModel
+}
+
+// Run runs the suite as subtests of t.
+func (s RankingModelSuite) Run(t *testing.T) {
+	require.GreaterOrEqual(t, len(s.Texts), 2, "RankingModelSuite needs at least two texts")
+
+	t.Run("metadata", func(t *testing.T) {
+		checkMetadata(t, s.Model.SpecificationVersion(), s.Model.ProviderName(), s.Model.ModelID())
+	})
+
+	t.Run("preserves input order", func(t *testing.T) {
+		resp, err := s.Model.DoRank(t.Context(), s.Query, s.Texts, api.TransportOptions{})
+		require.NoError(t, err)
+		require.Len(t, resp.Scores, len(s.Texts), "one score per text")
+
+		reversed, err := s.Model.DoRank(t.Context(), s.Query, reverse(s.Texts), api.TransportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, reverse(resp.Scores), reversed.Scores,
+			"reversing the texts must reverse the scores")
+	})
+
+	t.Run("results match scores", func(t *testing.T) {
+		resp, err := s.Model.DoRank(t.Context(), s.Query, s.Texts, api.TransportOptions{})
+		require.NoError(t, err)
+		if resp.Results == nil {
+			return
+		}
+		assert.Len(t, resp.Results, len(s.Texts), "one result per text")
+		for i, result := range resp.Results {
+			require.True(t, result.Index >= 0 && result.Index < len(s.Texts),
+				"result %d has index %d out of range", i, result.Index)
+			assert.Equal(t, resp.Scores[result.Index], result.Score,
+				"result %d does not match the score of text %d", i, result.Index)
+			if i > 0 {
+				assert.GreaterOrEqual(t, resp.Results[i-1].Score, result.Score,
+					"results must be sorted by descending score")
+			}
+		}
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		resp, err := s.Model.DoRank(t.Context(), s.Query, []string{}, api.TransportOptions{})
+		if err == nil {
+			assert.Empty(t, resp.Scores, "ranking no texts must return no scores")
+		}
+	})
+
+	t.Run("context cancellation", func(t *testing.T) {
+		_, err := s.Model.DoRank(cancelledContext(t), s.Query, s.Texts, api.TransportOptions{})
+		checkCancelled(t, err)
+	})
+
+	if s.FailingModel != nil {
+		t.Run("error typing", func(t *testing.T) {
+			_, err := s.FailingModel.DoRank(t.Context(), s.Query, s.Texts, api.TransportOptions{})
+			checkAPICallError(t, err)
+		})
+	}
+}
+
+// SegmentingModelSuite checks the contract of an [api.SegmentingModel].
+type SegmentingModelSuite struct {
+	// Model is the model under test.
+	Model api.SegmentingModel
+
+	// Texts are at least two distinct texts to segment.
+	Texts []string
+
+	// FailingModel, if set, is a model whose backend fails every request with
+	// an HTTP error status. It is used to check that such failures are
+	// reported as an [api.APICallError].
+	FailingModel api.SegmentingModel
+}
+
+// Run runs the suite as subtests of t.
+func (s SegmentingModelSuite) Run(t *testing.T) {
+	require.GreaterOrEqual(t, len(s.Texts), 2, "SegmentingModelSuite needs at least two texts")
+
+	t.Run("metadata", func(t *testing.T) {
+		checkMetadata(t, s.Model.SpecificationVersion(), s.Model.ProviderName(), s.Model.ModelID())
+	})
+
+	t.Run("preserves input order", func(t *testing.T) {
+		resp, err := s.Model.DoSegment(t.Context(), s.Texts, api.TransportOptions{})
+		require.NoError(t, err)
+		require.Len(t, resp.Segments, len(s.Texts), "one list of segments per text")
+		for i, segments := range resp.Segments {
+			assert.NotEmpty(t, segments, "text %d has no segments", i)
+		}
+
+		reversed, err := s.Model.DoSegment(t.Context(), reverse(s.Texts), api.TransportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, reverse(resp.Segments), reversed.Segments,
+			"reversing the texts must reverse the segments")
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		resp, err := s.Model.DoSegment(t.Context(), []string{}, api.TransportOptions{})
+		if err == nil {
+			assert.Empty(t, resp.Segments, "segmenting no texts must return no segments")
+		}
+	})
+
+	t.Run("context cancellation", func(t *testing.T) {
+		_, err := s.Model.DoSegment(cancelledContext(t), s.Texts, api.TransportOptions{})
+		checkCancelled(t, err)
+	})
+
+	if s.FailingModel != nil {
+		t.Run("error typing", func(t *testing.T) {
+			_, err := s.FailingModel.DoSegment(t.Context(), s.Texts, api.TransportOptions{})
+			checkAPICallError(t, err)
+		})
+	}
+}
+
+// LanguageModelSuite checks the contract of an [api.LanguageModel].
+type LanguageModelSuite struct {
+	// Model is the model under test. Its backend must answer every prompt
+	// with a non-empty text response, both for Generate and Stream.
+	Model api.LanguageModel
+
+	// Prompt is the prompt sent to the model. It defaults to a single user
+	// message.
+	Prompt []api.Message
+
+	// FailingModel, if set, is a model whose backend fails every request with
+	// an HTTP error status. It is used to check that such failures are
+	// reported as an [api.APICallError].
+	FailingModel api.LanguageModel
+}
+
+// Run runs the suite as subtests of t.
+func (s LanguageModelSuite) Run(t *testing.T) {
+	prompt := s.Prompt
+	if len(prompt) == 0 {
+		prompt = []api.Message{
+			&api.UserMessage{Content: []api.ContentBlock{&api.TextBlock{Text: "Hello"}}},
+		}
+	}
+
+	t.Run("metadata", func(t *testing.T) {
+		assert.NotEmpty(t, s.Model.ProviderName(), "ProviderName must not be empty")
+		assert.NotEmpty(t, s.Model.ModelID(), "ModelID must not be empty")
+	})
+
+	t.Run("generate", func(t *testing.T) {
+		resp, err := s.Model.Generate(t.Context(), prompt, api.CallOptions{})
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		assert.NotEmpty(t, resp.Content, "the response must have content")
+		assert.NotEmpty(t, resp.FinishReason, "the response must have a finish reason")
+	})
+
+	t.Run("stream terminates with one finish event", func(t *testing.T) {
+		resp, err := s.Model.Stream(t.Context(), prompt, api.CallOptions{})
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+
+		var events []api.StreamEvent
+		for event := range resp.Stream {
+			events = append(events, event)
+		}
+		require.NotEmpty(t, events, "the stream must yield events")
+
+		finishes := 0
+		for _, event := range events {
+			switch event := event.(type) {
+			case *api.FinishEvent:
+				finishes++
+			case *api.ErrorEvent:
+				t.Errorf("unexpected error event: %v", event.Err)
+			}
+		}
+		assert.Equal(t, 1, finishes, "the stream must yield exactly one FinishEvent")
+		assert.IsType(t, &api.FinishEvent{}, events[len(events)-1], "the FinishEvent must be the last event")
+	})
+
+	t.Run("stream stops when the consumer stops", func(t *testing.T) {
+		resp, err := s.Model.Stream(t.Context(), prompt, api.CallOptions{})
+		require.NoError(t, err)
+		// Yielding again after the consumer returned false panics, which
+		// fails the test.
+		for range resp.Stream {
+			break
+		}
+	})
+
+	t.Run("context cancellation", func(t *testing.T) {
+		ctx := cancelledContext(t)
+		_, err := s.Model.Generate(ctx, prompt, api.CallOptions{})
+		checkCancelled(t, err)
+
+		resp, err := s.Model.Stream(ctx, prompt, api.CallOptions{})
+		checkCancelled(t, streamError(resp, err))
+	})
+
+	if s.FailingModel != nil {
+		t.Run("error typing", func(t *testing.T) {
+			_, err := s.FailingModel.Generate(t.Context(), prompt, api.CallOptions{})
+			checkAPICallError(t, err)
+
+			resp, err := s.FailingModel.Stream(t.Context(), prompt, api.CallOptions{})
+			checkAPICallError(t, streamError(resp, err))
+		})
+	}
+}
+
+// streamError returns err if it is set, and otherwise the first error of the
+// stream of resp.
+func streamError(resp *api.StreamResponse, err error) error {
+	if err != nil || resp == nil {
+		return err
+	}
+	for _, err := range resp.Events() {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkMetadata(t *testing.T, specificationVersion, providerName, modelID string) {
+	t.Helper()
+	assert.NotEmpty(t, specificationVersion, "SpecificationVersion must not be empty")
+	assert.NotEmpty(t, providerName, "ProviderName must not be empty")
+	assert.NotEmpty(t, modelID, "ModelID must not be empty")
+}
+
+func cancelledContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	return ctx
+}
+
+func checkCancelled(t *testing.T, err error) {
+	t.Helper()
+	require.Error(t, err, "a call with a cancelled context must fail")
+	assert.ErrorIs(t, err, context.Canceled, "the error must wrap the context error")
+}
+
+func checkAPICallError(t *testing.T, err error) {
+	t.Helper()
+	require.Error(t, err, "a failed request must return an error")
+	var apiErr *api.APICallError
+	if assert.ErrorAs(t, err, &apiErr, "the error must be an *api.APICallError") {
+		assert.GreaterOrEqual(t, apiErr.StatusCode, 400, "the APICallError must carry the HTTP status")
+	}
+}
+
+func reverse[S ~[]E, E any](s S) S {
+	r := slices.Clone(s)
+	slices.Reverse(r)
+	return r
+}
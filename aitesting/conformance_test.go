@@ -0,0 +1,242 @@
+package aitesting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+	"go.jetify.com/ai/provider/jina"
+	jinaclient "go.jetify.com/ai/provider/jina/client"
+	jinaoption "go.jetify.com/ai/provider/jina/client/option"
+	"go.jetify.com/ai/provider/mock"
+	"go.jetify.com/ai/provider/openrouter"
+	"go.jetify.com/ai/provider/tei"
+	teiclient "go.jetify.com/ai/provider/tei/client"
+	teioption "go.jetify.com/ai/provider/tei/client/option"
+)
+
+func TestLanguageModelSuite_OpenRouter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Stream bool `json:"stream"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		if !req.Stream {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{
+				"id": "gen-1",
+				"choices": [{"message": {"role": "assistant", "content": "Hello"}, "finish_reason": "stop"}],
+				"usage": {"prompt_tokens": 1, "completion_tokens": 1}
+			}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range []string{
+			`{"id":"gen-1","choices":[{"delta":{"role":"assistant","content":"Hel"}}]}`,
+			`{"id":"gen-1","choices":[{"delta":{"content":"lo"}}]}`,
+			`{"id":"gen-1","choices":[{"delta":{},"finish_reason":"stop"}]}`,
+			`{"id":"gen-1","choices":[],"usage":{"prompt_tokens":1,"completion_tokens":1}}`,
+			`[DONE]`,
+		} {
+			_, _ = w.Write([]byte("data: " + chunk + "\n\n"))
+		}
+	}))
+	defer server.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"error": {"code": 500, "message": "internal error"}}`))
+	}))
+	defer failing.Close()
+
+	newModel := func(baseURL string) api.LanguageModel {
+		model, err := openrouter.NewProvider(openrouter.WithBaseURL(baseURL)).LanguageModel("openai/gpt-4o")
+		require.NoError(t, err)
+		return model
+	}
+
+	LanguageModelSuite{
+		Model:        newModel(server.URL),
+		FailingModel: newModel(failing.URL),
+	}.Run(t)
+}
+
+// teiHandler stubs the TEI embed and rerank endpoints with deterministic
+// results derived from the input texts.
+func teiHandler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /embed", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Inputs []string `json:"inputs"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		embeddings := make([]api.Embedding, len(req.Inputs))
+		for i, input := range req.Inputs {
+			embeddings[i] = mock.HashEmbedding(input, 4)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(embeddings)
+	})
+	mux.HandleFunc("POST /rerank", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string   `json:"query"`
+			Texts []string `json:"texts"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		type result struct {
+			Index int     `json:"index"`
+			Score float64 `json:"score"`
+		}
+		results := make([]result, len(req.Texts))
+		for i, text := range req.Texts {
+			// TEI sorts results by descending score, so return them reversed
+			// to check that the model restores the input order.
+			results[len(req.Texts)-1-i] = result{Index: i, Score: float64(len(text)) / 100}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(results)
+	})
+	return mux
+}
+
+// teiFailingHandler answers every request with a TEI error body.
+func teiFailingHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"error": "model is overloaded", "error_type": "Overloaded"}`))
+	})
+}
+
+func newTEIProvider(baseURL string) *tei.Provider {
+	return tei.NewProvider(tei.WithClient(teiclient.NewClient(teioption.WithBaseURL(baseURL))))
+}
+
+func TestEmbeddingModelSuite_TEI(t *testing.T) {
+	server := httptest.NewServer(teiHandler(t))
+	defer server.Close()
+	failing := httptest.NewServer(teiFailingHandler())
+	defer failing.Close()
+
+	model, err := newTEIProvider(server.URL).TextEmbeddingModel("bge-small")
+	require.NoError(t, err)
+	failingModel, err := newTEIProvider(failing.URL).TextEmbeddingModel("bge-small")
+	require.NoError(t, err)
+
+	EmbeddingModelSuite[string, api.Embedding]{
+		Model:        model,
+		Inputs:       []string{"first", "second", "third"},
+		FailingModel: failingModel,
+	}.Run(t)
+}
+
+func TestRankingModelSuite_TEI(t *testing.T) {
+	server := httptest.NewServer(teiHandler(t))
+	defer server.Close()
+	failing := httptest.NewServer(teiFailingHandler())
+	defer failing.Close()
+
+	model, err := newTEIProvider(server.URL).RankingModel("bge-reranker")
+	require.NoError(t, err)
+	failingModel, err := newTEIProvider(failing.URL).RankingModel("bge-reranker")
+	require.NoError(t, err)
+
+	RankingModelSuite{
+		Model:        model,
+		Query:        "query",
+		Texts:        []string{"a", "bb", "ccc"},
+		FailingModel: failingModel,
+	}.Run(t)
+}
+
+func TestRankingModelSuite_Jina(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Documents []string `json:"documents"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		type result struct {
+			Index          int     `json:"index"`
+			RelevanceScore float64 `json:"relevance_score"`
+		}
+		results := make([]result, len(req.Documents))
+		for i, doc := range req.Documents {
+			results[len(req.Documents)-1-i] = result{Index: i, RelevanceScore: float64(len(doc)) / 100}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"results": results})
+	}))
+	defer server.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"detail": "rate limit exceeded"}`))
+	}))
+	defer failing.Close()
+
+	newModel := func(baseURL string) api.RankingModel {
+		client := jinaclient.NewClient(jinaoption.WithBaseURL(baseURL))
+		model, err := jina.NewProvider(jina.WithClient(client)).RankingModel(jinaclient.RankingModelJinaRerankerV2BaseMultilingual)
+		require.NoError(t, err)
+		return model
+	}
+
+	RankingModelSuite{
+		Model:        newModel(server.URL),
+		Query:        "query",
+		Texts:        []string{"a", "bb", "ccc"},
+		FailingModel: newModel(failing.URL),
+	}.Run(t)
+}
+
+// wordSegmenter is a segmenting model that splits every text on whitespace.
+type wordSegmenter struct {
+	err error
+}
+
+var _ api.SegmentingModel = (*wordSegmenter)(nil)
+
+func (m *wordSegmenter) SpecificationVersion() string { return "v1" }
+
+func (m *wordSegmenter) ProviderName() string { return "words" }
+
+func (m *wordSegmenter) ModelID() string { return "whitespace" }
+
+func (m *wordSegmenter) SupportsParallelCalls() bool { return true }
+
+func (m *wordSegmenter) DoSegment(
+	ctx context.Context, texts []string, opts api.TransportOptions,
+) (api.SegmentingResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return api.SegmentingResponse{}, err
+	}
+	if m.err != nil {
+		return api.SegmentingResponse{}, m.err
+	}
+	segments := make([][]api.Segment, len(texts))
+	for i, text := range texts {
+		for _, word := range strings.Fields(text) {
+			segments[i] = append(segments[i], api.Segment{Text: word})
+		}
+	}
+	return api.SegmentingResponse{Segments: segments}, nil
+}
+
+func TestSegmentingModelSuite(t *testing.T) {
+	SegmentingModelSuite{
+		Model: &wordSegmenter{},
+		Texts: []string{"one two", "three four five"},
+		FailingModel: &wordSegmenter{err: &api.APICallError{
+			AISDKError: api.NewAISDKError("AI_APICallError", "Service Unavailable", nil),
+			StatusCode: http.StatusServiceUnavailable,
+		}},
+	}.Run(t)
+}
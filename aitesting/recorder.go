@@ -0,0 +1,206 @@
+package aitesting
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/dnaeon/go-vcr.v4/pkg/cassette"
+	"gopkg.in/dnaeon/go-vcr.v4/pkg/recorder"
+)
+
+// RecordEnvVar is the environment variable that makes a [Recorder] record
+// new cassettes when no record mode is set, e.g. AI_RECORD=1 go test ./...
+const RecordEnvVar = "AI_RECORD"
+
+// RecordMode controls whether a [Recorder] talks to the real API.
+type RecordMode int
+
+const (
+	// RecordModeReplay only replays the cassette. Requests that do not match
+	// a recorded interaction fail, and so does a missing cassette. This is
+	// the default unless the AI_RECORD environment variable is set.
+	RecordModeReplay RecordMode = iota
+
+	// RecordModeRecord sends every request to the real API and overwrites
+	// the cassette. This is the default when the AI_RECORD environment
+	// variable is set.
+	RecordModeRecord
+
+	// RecordModeAuto replays the cassette if it exists and records a new one
+	// otherwise.
+	RecordModeAuto
+)
+
+// DefaultRedactedHeaders are the request and response headers whose values
+// are never written to a cassette.
+var DefaultRedactedHeaders = []string{
+	"Api-Key",
+	"Authorization",
+	"Cookie",
+	"Openai-Organization",
+	"Openai-Project",
+	"Proxy-Authorization",
+	"Set-Cookie",
+	"X-Api-Key",
+	"X-Goog-Api-Key",
+}
+
+// redactedValue replaces the value of redacted headers in cassettes.
+const redactedValue = "REDACTED"
+
+// Recorder is an [http.RoundTripper] that records HTTP interactions to a
+// cassette file and replays them in later runs, so that provider tests can
+// run offline and deterministically.
+//
+// Recorded requests are matched by method, URL and body; each recorded
+// interaction is replayed at most once, in the order it was recorded.
+//
+// A Recorder plugs into every HTTP-based provider through its HTTP client:
+//
+//	rec := aitesting.NewRecorder(t, "testdata/chat")
+//
+//	openai.NewClient(option.WithHTTPClient(rec.Client()))    // openai-go
+//	anthropic.NewClient(option.WithHTTPClient(rec.Client())) // anthropic-sdk-go
+//	openrouter.NewProvider(openrouter.WithClient(rec.Client()))
+//	tei.NewClient(teioption.WithHTTPClient(rec.Client()))
+type Recorder struct {
+	rec *recorder.Recorder
+}
+
+var _ http.RoundTripper = (*Recorder)(nil)
+
+type recorderConfig struct {
+	mode            RecordMode
+	redactedHeaders []string
+	transport       http.RoundTripper
+}
+
+// RecorderOption configures a [Recorder].
+type RecorderOption func(*recorderConfig)
+
+// WithRecordMode sets the record mode. It takes precedence over the AI_RECORD
+// environment variable. The default is [RecordModeReplay], or
+// [RecordModeRecord] when AI_RECORD is set.
+func WithRecordMode(mode RecordMode) RecorderOption {
+	return func(c *recorderConfig) {
+		c.mode = mode
+	}
+}
+
+// WithRedactedHeaders redacts the given headers in addition to
+// [DefaultRedactedHeaders]. Header names are case-insensitive.
+func WithRedactedHeaders(headers ...string) RecorderOption {
+	return func(c *recorderConfig) {
+		c.redactedHeaders = append(c.redactedHeaders, headers...)
+	}
+}
+
+// WithRealTransport sets the transport used to reach the real API when
+// recording. It defaults to [http.DefaultTransport].
+func WithRealTransport(transport http.RoundTripper) RecorderOption {
+	return func(c *recorderConfig) {
+		c.transport = transport
+	}
+}
+
+// NewRecorder creates a Recorder for the cassette stored at path, with a
+// ".yaml" extension added. The cassette is saved when the test finishes.
+func NewRecorder(t testing.TB, path string, opts ...RecorderOption) *Recorder {
+	t.Helper()
+
+	config := recorderConfig{
+		mode:            defaultRecordMode(),
+		redactedHeaders: append([]string{}, DefaultRedactedHeaders...),
+		transport:       http.DefaultTransport,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	rec, err := recorder.New(path,
+		recorder.WithMode(vcrMode(config.mode)),
+		recorder.WithRealTransport(config.transport),
+		recorder.WithSkipRequestLatency(true),
+		recorder.WithMatcher(matchRequest),
+		recorder.WithHook(redactHeaders(config.redactedHeaders), recorder.BeforeSaveHook),
+	)
+	require.NoError(t, err, "failed to create recorder for cassette %s (set %s=1 to record it)", path, RecordEnvVar)
+
+	t.Cleanup(func() {
+		require.NoError(t, rec.Stop(), "failed to save cassette %s", path)
+	})
+	return &Recorder{rec: rec}
+}
+
+// RoundTrip records or replays a single HTTP interaction.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	return r.rec.RoundTrip(req)
+}
+
+// Client returns an HTTP client that sends its requests through the recorder.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// IsRecording reports whether requests are sent to the real API.
+func (r *Recorder) IsRecording() bool {
+	return r.rec.IsRecording()
+}
+
+// defaultRecordMode returns the record mode used when none is set: recording
+// has to be asked for explicitly, so that tests never reach the real API by
+// accident.
+func defaultRecordMode() RecordMode {
+	if os.Getenv(RecordEnvVar) != "" {
+		return RecordModeRecord
+	}
+	return RecordModeReplay
+}
+
+func vcrMode(mode RecordMode) recorder.Mode {
+	switch mode {
+	case RecordModeRecord:
+		return recorder.ModeRecordOnly
+	case RecordModeAuto:
+		return recorder.ModeRecordOnce
+	}
+	return recorder.ModeReplayOnly
+}
+
+// matchRequest matches requests by method, URL and body. Headers are ignored
+// because SDKs add volatile headers (user agents, retry counts) and because
+// credentials are redacted in the cassette.
+func matchRequest(r *http.Request, i cassette.Request) bool {
+	if r.Method != i.Method || r.URL.String() != i.URL {
+		return false
+	}
+	if r.Body == nil || r.Body == http.NoBody {
+		return i.Body == ""
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return false
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return string(body) == i.Body
+}
+
+// redactHeaders returns a hook that replaces the value of the given headers
+// in captured requests and responses before they are saved.
+func redactHeaders(headers []string) recorder.HookFunc {
+	return func(i *cassette.Interaction) error {
+		for _, name := range headers {
+			for _, header := range []http.Header{i.Request.Headers, i.Response.Headers} {
+				if header.Get(name) != "" {
+					header.Set(name, redactedValue)
+				}
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,150 @@
+package aitesting
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai"
+	"go.jetify.com/ai/api"
+	"go.jetify.com/ai/provider/openrouter"
+)
+
+func post(t *testing.T, client *http.Client, url, body string) string {
+	t.Helper()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret-key")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=abc")
+		_, _ = w.Write([]byte("echo " + string(body)))
+	}))
+	defer server.Close()
+
+	cassette := t.TempDir() + "/echo"
+
+	t.Run("record", func(t *testing.T) {
+		rec := NewRecorder(t, cassette, WithRecordMode(RecordModeRecord))
+		assert.True(t, rec.IsRecording())
+		assert.Equal(t, "echo one", post(t, rec.Client(), server.URL, "one"))
+		assert.Equal(t, "echo two", post(t, rec.Client(), server.URL, "two"))
+	})
+	require.Equal(t, 2, calls)
+
+	data, err := os.ReadFile(cassette + ".yaml")
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret-key")
+	assert.NotContains(t, string(data), "session=abc")
+	assert.Contains(t, string(data), "REDACTED")
+
+	t.Run("replay", func(t *testing.T) {
+		rec := NewRecorder(t, cassette, WithRecordMode(RecordModeReplay))
+		assert.False(t, rec.IsRecording())
+		// Requests are matched by body, not only by order.
+		assert.Equal(t, "echo two", post(t, rec.Client(), server.URL, "two"))
+		assert.Equal(t, "echo one", post(t, rec.Client(), server.URL, "one"))
+
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, server.URL, strings.NewReader("three"))
+		require.NoError(t, err)
+		_, err = rec.Client().Do(req)
+		assert.Error(t, err, "unrecorded requests should fail in replay mode")
+	})
+	assert.Equal(t, 2, calls, "replay must not reach the server")
+}
+
+func TestRecorder_AutoMode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	cassette := t.TempDir() + "/auto"
+
+	t.Run("records missing cassette", func(t *testing.T) {
+		rec := NewRecorder(t, cassette, WithRecordMode(RecordModeAuto))
+		assert.True(t, rec.IsRecording())
+		assert.Equal(t, "ok", post(t, rec.Client(), server.URL, ""))
+	})
+	server.Close()
+
+	t.Run("replays existing cassette", func(t *testing.T) {
+		rec := NewRecorder(t, cassette, WithRecordMode(RecordModeAuto))
+		assert.False(t, rec.IsRecording())
+		assert.Equal(t, "ok", post(t, rec.Client(), server.URL, ""))
+	})
+}
+
+func TestRecorder_DefaultMode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	cassette := t.TempDir() + "/default"
+
+	t.Run("records when AI_RECORD is set", func(t *testing.T) {
+		t.Setenv(RecordEnvVar, "1")
+		rec := NewRecorder(t, cassette)
+		assert.True(t, rec.IsRecording())
+		assert.Equal(t, "ok", post(t, rec.Client(), server.URL, ""))
+	})
+
+	t.Run("replays otherwise", func(t *testing.T) {
+		t.Setenv(RecordEnvVar, "")
+		rec := NewRecorder(t, cassette)
+		assert.False(t, rec.IsRecording())
+		assert.Equal(t, "ok", post(t, rec.Client(), server.URL, ""))
+	})
+
+	t.Run("explicit mode wins over AI_RECORD", func(t *testing.T) {
+		t.Setenv(RecordEnvVar, "1")
+		rec := NewRecorder(t, cassette, WithRecordMode(RecordModeReplay))
+		assert.False(t, rec.IsRecording())
+	})
+}
+
+func TestRecorder_Provider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "gen-1",
+			"model": "openai/gpt-4o",
+			"choices": [{"message": {"role": "assistant", "content": "Hello"}, "finish_reason": "stop"}],
+			"usage": {"prompt_tokens": 1, "completion_tokens": 1, "total_tokens": 2}
+		}`))
+	}))
+	cassette := t.TempDir() + "/openrouter"
+
+	generate := func(t *testing.T, mode RecordMode) {
+		rec := NewRecorder(t, cassette, WithRecordMode(mode))
+		provider := openrouter.NewProvider(
+			openrouter.WithClient(rec.Client()),
+			openrouter.WithBaseURL(server.URL),
+			openrouter.WithAPIKey("secret-key"),
+		)
+		model, err := provider.LanguageModel("openai/gpt-4o")
+		require.NoError(t, err)
+
+		resp, err := ai.GenerateTextStr(t.Context(), "Hi", ai.WithModel(model))
+		require.NoError(t, err)
+		assert.Equal(t, []api.ContentBlock{&api.TextBlock{Text: "Hello"}}, resp.Content)
+	}
+
+	t.Run("record", func(t *testing.T) { generate(t, RecordModeRecord) })
+	server.Close()
+	t.Run("replay", func(t *testing.T) { generate(t, RecordModeReplay) })
+}
@@ -17,6 +17,12 @@ func (e *AISDKError) Error() string {
 	return e.Message
 }
 
+// aiSDKError returns the AISDKError itself. It is promoted to every error type
+// that embeds an *AISDKError, so that their name can be found with errors.As.
+func (e *AISDKError) aiSDKError() *AISDKError {
+	return e
+}
+
 // NewAISDKError creates an AI SDK Error.
 // Parameters:
 //   - name: The name of the error.
@@ -21,6 +21,12 @@ type APICallError struct {
 	// Response contains the original HTTP response
 	Response *http.Response
 
+	// ResponseBody contains the raw body of the response, if it was read
+	ResponseBody []byte
+
+	// RequestBody contains the raw body of the request, if any
+	RequestBody []byte
+
 	// Data contains additional error data, if any
 	Data any
 }
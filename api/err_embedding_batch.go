@@ -0,0 +1,40 @@
+package api
+
+import "fmt"
+
+// EmbeddingBatchError indicates that one chunk of a batched embedding call failed.
+// Start and End describe the half-open range [Start, End) of input values that
+// were part of the failed chunk.
+type EmbeddingBatchError struct {
+	*AISDKError
+
+	// Start is the index of the first input value in the failed chunk
+	Start int
+
+	// End is the index one past the last input value in the failed chunk
+	End int
+
+	// Err is the error returned by the provider for the chunk
+	Err error
+}
+
+// NewEmbeddingBatchError creates a new EmbeddingBatchError instance
+// Parameters:
+//   - start: The index of the first input value in the failed chunk
+//   - end: The index one past the last input value in the failed chunk
+//   - err: The error returned by the provider for the chunk
+func NewEmbeddingBatchError(start, end int, err error) *EmbeddingBatchError {
+	message := fmt.Sprintf("Embedding failed for input values [%d, %d): %v", start, end, err)
+	return &EmbeddingBatchError{
+		AISDKError: NewAISDKError("AI_EmbeddingBatchError", message, err),
+		Start:      start,
+		End:        end,
+		Err:        err,
+	}
+}
+
+// Unwrap returns the underlying provider error so it can be inspected
+// with errors.Is and errors.As.
+func (e *EmbeddingBatchError) Unwrap() error {
+	return e.Err
+}
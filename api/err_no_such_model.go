@@ -12,6 +12,14 @@ const (
 	LanguageModelType ModelType = "languageModel"
 	// TextEmbeddingModelType represents a text embedding model type
 	TextEmbeddingModelType ModelType = "textEmbeddingModel"
+	// MultimodalEmbeddingModelType represents a multimodal embedding model type
+	MultimodalEmbeddingModelType ModelType = "multimodalEmbeddingModel"
+	// SparseEmbeddingModelType represents a sparse embedding model type
+	SparseEmbeddingModelType ModelType = "sparseEmbeddingModel"
+	// RankingModelType represents a ranking model type
+	RankingModelType ModelType = "rankingModel"
+	// SegmentingModelType represents a segmenting model type
+	SegmentingModelType ModelType = "segmentingModel"
 	// ImageModelType represents an image model type
 	ImageModelType ModelType = "imageModel"
 )
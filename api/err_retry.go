@@ -0,0 +1,54 @@
+package api
+
+import "fmt"
+
+// RetryReason explains why a retried call gave up.
+type RetryReason string
+
+const (
+	// RetryReasonMaxRetriesExceeded indicates that every allowed attempt failed
+	// with a retryable error.
+	RetryReasonMaxRetriesExceeded RetryReason = "maxRetriesExceeded"
+
+	// RetryReasonErrorNotRetryable indicates that a retry attempt failed with an
+	// error that cannot be retried.
+	RetryReasonErrorNotRetryable RetryReason = "errorNotRetryable"
+)
+
+// RetryError is returned when a call still fails after being retried.
+type RetryError struct {
+	*AISDKError
+
+	// Reason explains why no further attempts were made
+	Reason RetryReason
+
+	// Errors contains the error of every attempt, in order
+	Errors []error
+
+	// LastError is the error of the final attempt
+	LastError error
+}
+
+// NewRetryError creates a new RetryError instance
+// Parameters:
+//   - reason: Why no further attempts were made
+//   - errs: The error of every attempt, in order. Must not be empty.
+func NewRetryError(reason RetryReason, errs []error) *RetryError {
+	lastErr := errs[len(errs)-1]
+	message := fmt.Sprintf("Failed after %d attempts. Last error: %v", len(errs), lastErr)
+	if reason == RetryReasonErrorNotRetryable {
+		message = fmt.Sprintf("Failed after %d attempts with non-retryable error: %v", len(errs), lastErr)
+	}
+	return &RetryError{
+		AISDKError: NewAISDKError("AI_RetryError", message, lastErr),
+		Reason:     reason,
+		Errors:     errs,
+		LastError:  lastErr,
+	}
+}
+
+// Unwrap returns the error of the final attempt so it can be inspected
+// with errors.Is and errors.As.
+func (e *RetryError) Unwrap() error {
+	return e.LastError
+}
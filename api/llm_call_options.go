@@ -51,8 +51,6 @@ type CallOptions struct {
 	// Only applicable for HTTP-based providers.
 	Headers http.Header `json:"headers,omitempty"`
 
-	// TODO: Add maxRetries at the ai package level but not provider level.
-
 	// =====
 	// Tool-related, might consider moving to a separate struct.
 	// =====
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 )
@@ -16,12 +17,27 @@ import (
 type EventType string
 
 const (
+	// EventStreamStart is the first part of the stream, carrying the warnings for the call.
+	EventStreamStart EventType = "stream-start"
+
+	// EventTextStart marks the start of a text block.
+	EventTextStart EventType = "text-start"
+
 	// EventTextDelta represents an incremental text response from the model.
 	EventTextDelta EventType = "text-delta"
 
+	// EventTextEnd marks the end of a text block.
+	EventTextEnd EventType = "text-end"
+
+	// EventReasoningStart marks the start of a reasoning block.
+	EventReasoningStart EventType = "reasoning-start"
+
 	// EventReasoning is an optional reasoning or intermediate explanation generated by the model.
 	EventReasoning EventType = "reasoning"
 
+	// EventReasoningEnd marks the end of a reasoning block.
+	EventReasoningEnd EventType = "reasoning-end"
+
 	// EventReasoningSignature represents a signature that verifies reasoning content.
 	EventReasoningSignature EventType = "reasoning-signature"
 
@@ -31,12 +47,18 @@ const (
 	// EventFile represents a file generated by the model.
 	EventFile EventType = "file"
 
-	// EventToolCall represents a completed tool call with all arguments provided.
-	EventToolCall EventType = "tool-call"
+	// EventToolInputStart marks the start of the arguments of a tool call.
+	EventToolInputStart EventType = "tool-input-start"
 
 	// EventToolCallDelta is an incremental update for tool call arguments.
 	EventToolCallDelta EventType = "tool-call-delta"
 
+	// EventToolInputEnd marks the end of the arguments of a tool call.
+	EventToolInputEnd EventType = "tool-input-end"
+
+	// EventToolCall represents a completed tool call with all arguments provided.
+	EventToolCall EventType = "tool-call"
+
 	// EventResponseMetadata contains additional response metadata, such as timestamps or provider details.
 	EventResponseMetadata EventType = "response-metadata"
 
@@ -55,6 +77,37 @@ type StreamEvent interface {
 	Type() EventType
 }
 
+// StreamStartEvent is the first event of a stream.
+//
+// Used to add the warnings of the call to the response.
+type StreamStartEvent struct {
+	// Warnings contains warnings about the call settings, e.g. unsupported
+	// settings that were ignored by the provider.
+	Warnings []CallWarning `json:"warnings"`
+}
+
+func (b *StreamStartEvent) Type() EventType { return EventStreamStart }
+
+// TextStartEvent marks the start of a text block.
+//
+// The TextDeltaEvents that follow, up to the matching TextEndEvent, make up
+// the text of a single TextBlock.
+type TextStartEvent struct {
+	// ID identifies the text block within the stream.
+	ID string `json:"id"`
+}
+
+func (b *TextStartEvent) Type() EventType { return EventTextStart }
+
+// TextEndEvent marks the end of the text block started by the TextStartEvent
+// with the same ID.
+type TextEndEvent struct {
+	// ID identifies the text block within the stream.
+	ID string `json:"id"`
+}
+
+func (b *TextEndEvent) Type() EventType { return EventTextEnd }
+
 // TextDeltaEvent represents an incremental text response from the model
 //
 // Used to update a TextBlock incrementally.
@@ -75,6 +128,26 @@ type ReasoningEvent struct {
 
 func (b *ReasoningEvent) Type() EventType { return EventReasoning }
 
+// ReasoningStartEvent marks the start of a reasoning block.
+//
+// The ReasoningEvents that follow, up to the matching ReasoningEndEvent, make
+// up the text of a single ReasoningBlock.
+type ReasoningStartEvent struct {
+	// ID identifies the reasoning block within the stream.
+	ID string `json:"id"`
+}
+
+func (b *ReasoningStartEvent) Type() EventType { return EventReasoningStart }
+
+// ReasoningEndEvent marks the end of the reasoning block started by the
+// ReasoningStartEvent with the same ID.
+type ReasoningEndEvent struct {
+	// ID identifies the reasoning block within the stream.
+	ID string `json:"id"`
+}
+
+func (b *ReasoningEndEvent) Type() EventType { return EventReasoningEnd }
+
 // ReasoningSignatureEvent represents an incremental signature update for reasoning text.
 //
 // Used to update the signature field of a ReasoningBlock.
@@ -144,7 +217,29 @@ type ToolCallDeltaEvent struct {
 
 func (b *ToolCallDeltaEvent) Type() EventType { return EventToolCallDelta }
 
-// TODOTODO Stream Start.
+// ToolInputStartEvent marks the start of the arguments of a tool call.
+//
+// It is followed by the ToolCallDeltaEvents of the tool call, a
+// ToolInputEndEvent once all arguments have been streamed, and finally the
+// ToolCallEvent with the complete arguments.
+type ToolInputStartEvent struct {
+	// ToolCallID is the ID of the tool call
+	ToolCallID string `json:"tool_call_id"`
+
+	// ToolName is the name of the tool being invoked
+	ToolName string `json:"tool_name"`
+}
+
+func (b *ToolInputStartEvent) Type() EventType { return EventToolInputStart }
+
+// ToolInputEndEvent marks the end of the arguments of the tool call started by
+// the ToolInputStartEvent with the same ToolCallID.
+type ToolInputEndEvent struct {
+	// ToolCallID is the ID of the tool call
+	ToolCallID string `json:"tool_call_id"`
+}
+
+func (b *ToolInputEndEvent) Type() EventType { return EventToolInputEnd }
 
 // ResponseMetadataEvent contains additional response metadata.
 //
@@ -183,13 +278,54 @@ func (b *FinishEvent) GetProviderMetadata() *ProviderMetadata { return b.Provide
 
 // ErrorEvent represents an error that occurred during streaming.
 type ErrorEvent struct {
-	// Err contains any error messages or error objects encountered during the stream
-	Err any `json:"error"`
-
-	// TODO: We might want to make sure that the error field is always serializable as JSON,
-	// or we might force it to be an error type defined by our AI SDK so that the shape is
-	// known if transmitted over the network.
+	// Err is the error encountered during the stream. Transport and provider
+	// failures are reported with the AI SDK error types, e.g. *APICallError,
+	// so they can be inspected with errors.As.
+	Err error `json:"error"`
 }
 
 func (b *ErrorEvent) Type() EventType { return EventError }
 func (b *ErrorEvent) Error() string   { return fmt.Sprintf("%v", b.Err) }
+
+// Unwrap returns the underlying error, so that an ErrorEvent used as an error
+// can be inspected with errors.Is and errors.As.
+func (b *ErrorEvent) Unwrap() error { return b.Err }
+
+// streamError is the JSON representation of the error of an ErrorEvent.
+type streamError struct {
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message"`
+}
+
+// MarshalJSON encodes the error as an object with the AI SDK error name, if
+// any, and the error message, since most errors have no JSON representation
+// of their own.
+func (b *ErrorEvent) MarshalJSON() ([]byte, error) {
+	var encoded *streamError
+	if b.Err != nil {
+		encoded = &streamError{Message: b.Err.Error()}
+		var sdkErr interface{ aiSDKError() *AISDKError }
+		if errors.As(b.Err, &sdkErr) && sdkErr.aiSDKError() != nil {
+			encoded.Name = sdkErr.aiSDKError().Name
+		}
+	}
+	return json.Marshal(struct {
+		Err *streamError `json:"error"`
+	}{Err: encoded})
+}
+
+// UnmarshalJSON decodes an ErrorEvent encoded by MarshalJSON. The error is
+// restored as an *AISDKError with the original name and message.
+func (b *ErrorEvent) UnmarshalJSON(data []byte) error {
+	var decoded struct {
+		Err *streamError `json:"error"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	b.Err = nil
+	if decoded.Err != nil {
+		b.Err = NewAISDKError(decoded.Err.Name, decoded.Err.Message, nil)
+	}
+	return nil
+}
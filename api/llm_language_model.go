@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"iter"
 	"net/http"
 	"time"
@@ -84,6 +85,12 @@ type Response struct {
 	// e.g. unsupported settings.
 	Warnings []CallWarning `json:"warnings,omitempty"`
 
+	// Steps contains every intermediate model call when the response was
+	// produced by a multi-step generation with executable tools. The
+	// top-level fields mirror the last step, except Usage which is summed
+	// across all steps. Empty for single-call generations.
+	Steps []Step `json:"steps,omitempty"`
+
 	// TODO:
 	// - Consider promoting "response id" (like in OpenAI's responses API) to a top-level
 	//  field.
@@ -119,13 +126,25 @@ func (r *Response) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Step contains the result of a single model call made as part of a
+// multi-step generation.
+type Step struct {
+	// Response is the model response for this step, including its own usage
+	// and finish reason.
+	Response *Response `json:"response"`
+
+	// ToolResults contains the results of the tools that were executed for the
+	// tool calls requested in this step.
+	ToolResults []ToolResultBlock `json:"tool_results,omitempty"`
+}
+
 // StreamResponse represents the result of a streaming language model call.
 type StreamResponse struct {
 	// Stream is the sequence of events received from the model.
 	// Iterating over events might block if we're waiting for the LLM to respond.
+	// Errors are yielded as an ErrorEvent; use Events to receive them as
+	// error values instead.
 	Stream iter.Seq[StreamEvent]
-	// TODO: For now we're always encoding errors as ErrorEvent. Is that the right
-	// behavior or should we consider iter.Seq2[StreamEvent, error]?
 
 	// RequestInfo is optional request information for telemetry and debugging purposes.
 	RequestInfo *RequestInfo `json:"request,omitzero"`
@@ -134,6 +153,41 @@ type StreamResponse struct {
 	ResponseInfo *ResponseInfo `json:"response,omitzero"`
 }
 
+// Events returns the events of the stream, with errors separated from the
+// other events. Every ErrorEvent is yielded as a nil event and its error, so
+// that transport and provider failures can be handled with errors.As:
+//
+//	for event, err := range resp.Events() {
+//		if err != nil {
+//			var apiErr *api.APICallError
+//			if errors.As(err, &apiErr) { ... }
+//			return err
+//		}
+//		...
+//	}
+func (r *StreamResponse) Events() iter.Seq2[StreamEvent, error] {
+	return func(yield func(StreamEvent, error) bool) {
+		if r == nil || r.Stream == nil {
+			return
+		}
+		for event := range r.Stream {
+			if errEvent, ok := event.(*ErrorEvent); ok {
+				err := errEvent.Err
+				if err == nil {
+					err = errors.New("stream error")
+				}
+				if !yield(nil, err) {
+					return
+				}
+				continue
+			}
+			if !yield(event, nil) {
+				return
+			}
+		}
+	}
+}
+
 // Usage represents token usage statistics for a model call.
 //
 // If a provider returns additional usage information besides the ones below,
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -350,3 +351,97 @@ func TestResponse_JSON(t *testing.T) {
 		})
 	}
 }
+
+func TestStreamResponse_Events(t *testing.T) {
+	apiErr := &APICallError{
+		AISDKError: NewAISDKError("AI_APICallError", "Service Unavailable", nil),
+		StatusCode: 503,
+	}
+	resp := &StreamResponse{
+		Stream: func(yield func(StreamEvent) bool) {
+			_ = yield(&TextDeltaEvent{TextDelta: "Hel"}) &&
+				yield(&ErrorEvent{Err: apiErr}) &&
+				yield(&ErrorEvent{}) &&
+				yield(&FinishEvent{FinishReason: FinishReasonError})
+		},
+	}
+
+	var events []StreamEvent
+	var errs []error
+	for event, err := range resp.Events() {
+		if err != nil {
+			assert.Nil(t, event)
+			errs = append(errs, err)
+			continue
+		}
+		events = append(events, event)
+	}
+
+	assert.Equal(t, []StreamEvent{
+		&TextDeltaEvent{TextDelta: "Hel"},
+		&FinishEvent{FinishReason: FinishReasonError},
+	}, events)
+	require.Len(t, errs, 2)
+	var target *APICallError
+	require.ErrorAs(t, errs[0], &target)
+	assert.Equal(t, 503, target.StatusCode)
+	assert.EqualError(t, errs[1], "stream error")
+
+	t.Run("stops early", func(t *testing.T) {
+		count := 0
+		for range resp.Events() {
+			count++
+			break
+		}
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("nil stream", func(t *testing.T) {
+		for range (&StreamResponse{}).Events() {
+			t.Fatal("no events expected")
+		}
+	})
+}
+
+func TestErrorEvent_JSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    *ErrorEvent
+		jsonStr  string
+		expected *ErrorEvent
+	}{
+		{
+			name: "api_call_error",
+			event: &ErrorEvent{Err: &APICallError{
+				AISDKError: NewAISDKError("AI_APICallError", "rate limited", nil),
+				StatusCode: 429,
+			}},
+			jsonStr:  `{"error": {"name": "AI_APICallError", "message": "rate limited"}}`,
+			expected: &ErrorEvent{Err: NewAISDKError("AI_APICallError", "rate limited", nil)},
+		},
+		{
+			name:     "plain_error",
+			event:    &ErrorEvent{Err: errors.New("connection reset")},
+			jsonStr:  `{"error": {"message": "connection reset"}}`,
+			expected: &ErrorEvent{Err: NewAISDKError("", "connection reset", nil)},
+		},
+		{
+			name:     "nil_error",
+			event:    &ErrorEvent{},
+			jsonStr:  `{"error": null}`,
+			expected: &ErrorEvent{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.event)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.jsonStr, string(data))
+
+			var decoded ErrorEvent
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, tt.expected, &decoded)
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package api
 
-import "context"
+import (
+	"cmp"
+	"context"
+	"slices"
+)
 
 // RankingModel represents a model capable of ranking a query against a set of texts.
 type RankingModel interface {
@@ -24,8 +28,61 @@ type RankingModel interface {
 // RankingResponse represents the response from a ranking request.
 type RankingResponse struct {
 	// Scores contains one score per text in the same order as the input.
+	// Texts that the provider did not score, for example because of a
+	// provider-specific top-N option, have a score of zero.
 	Scores []float64
 
+	// Results contains the ranked texts sorted by descending score. It may
+	// hold fewer entries than the input when the results were truncated to
+	// the top N.
+	Results []RankingResult
+
+	// Usage contains usage information, if the provider reports it.
+	Usage *RankingUsage
+
 	// RequestID is an optional identifier for tracing.
 	RequestID string
 }
+
+// RankingResult is a single text of a ranking response.
+type RankingResult struct {
+	// Index is the position of the text in the input.
+	Index int
+
+	// Score is the relevance score of the text; higher is more relevant.
+	Score float64
+
+	// Document is the text itself. It is only set when the provider echoes
+	// the input or when documents were requested.
+	Document string
+}
+
+// RankingUsage represents usage information for a ranking request.
+type RankingUsage struct {
+	// TotalTokens is the number of tokens processed by the request.
+	TotalTokens int64
+
+	// SearchUnits is the number of search units billed, for providers that
+	// bill ranking requests by search.
+	SearchUnits int64
+}
+
+// RankingResultsFromScores returns one RankingResult per score, sorted by
+// descending score. Ties keep their input order. It is meant for providers
+// that only return scores in input order.
+func RankingResultsFromScores(scores []float64) []RankingResult {
+	results := make([]RankingResult, len(scores))
+	for i, score := range scores {
+		results[i] = RankingResult{Index: i, Score: score}
+	}
+	SortRankingResults(results)
+	return results
+}
+
+// SortRankingResults sorts results by descending score. Ties keep their
+// relative order.
+func SortRankingResults(results []RankingResult) {
+	slices.SortStableFunc(results, func(a, b RankingResult) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+}
@@ -0,0 +1,28 @@
+package api
+
+import "time"
+
+// RetryPolicy controls how calls that fail with a retryable [APICallError]
+// are retried.
+//
+// Retries are applied by the ai package, not by providers. Zero-valued fields
+// (other than MaxRetries) take their value from ai.DefaultRetryPolicy.
+type RetryPolicy struct {
+	// MaxRetries is the number of times a failed call is retried.
+	// Zero disables retries.
+	MaxRetries int
+
+	// InitialDelay is the delay before the first retry.
+	InitialDelay time.Duration
+
+	// MaxDelay caps the delay between two attempts, including delays requested
+	// by the provider through a Retry-After header.
+	MaxDelay time.Duration
+
+	// BackoffFactor multiplies the delay after every attempt.
+	BackoffFactor float64
+
+	// Jitter randomizes each delay by up to the given fraction (0.2 means ±20%).
+	// Use a negative value to disable jitter.
+	Jitter float64
+}
@@ -1,6 +1,11 @@
 package api
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
 
 // TransportOption represents an option function for transport configuration.
 type TransportOption func(*TransportOptions)
@@ -25,6 +30,34 @@ type TransportOptions struct {
 	// Only applicable for HTTP-based providers that support it.
 	UseRawBaseURL bool
 
+	// Timeout, if positive, bounds the duration of this call. When it expires
+	// only the call is cancelled, not the context it was made with.
+	// Only applicable for HTTP-based providers that support it.
+	Timeout time.Duration
+
+	// TopN, if positive, limits the results of a ranking call to the N most
+	// relevant texts. Providers whose API supports it only return those
+	// results. Only applicable to ranking calls; other calls ignore it.
+	TopN int
+
+	// ReturnDocuments asks the provider to include the ranked text in each
+	// result. Only applicable to ranking calls; other calls ignore it.
+	ReturnDocuments bool
+
+	// MaxParallelCalls caps the number of concurrent provider calls issued by
+	// ai.EmbedMany when the input is split into several chunks.
+	// Zero means no limit. Providers ignore it.
+	MaxParallelCalls int
+
+	// RetryPolicy controls how failed calls are retried by the ai package.
+	// When ai.EmbedMany splits its input into chunks, each chunk is retried
+	// independently. Providers ignore it.
+	RetryPolicy RetryPolicy
+
+	// TracerProvider, if set, records a span for every call to ai.EmbedMany,
+	// ai.RankMany and ai.SegmentMany. Providers ignore it.
+	TracerProvider trace.TracerProvider
+
 	// ProviderMetadata contains additional provider-specific metadata.
 	// The metadata is passed through to the provider from the AI SDK and enables
 	// provider-specific functionality that can be fully encapsulated in the provider.
@@ -9,6 +9,13 @@ import (
 
 type responseStateEnum int
 
+// blockKey identifies a text or reasoning block by the ID of its start event.
+// Text and reasoning blocks have separate IDs.
+type blockKey struct {
+	kind string
+	id   string
+}
+
 const (
 	noState responseStateEnum = iota
 	textState
@@ -26,12 +33,20 @@ type ResponseBuilder struct {
 	resp api.Response
 	// Map of tool call ID to index in Content array (for parallel tool calls)
 	toolCallIndices map[string]int
+	// Set of tool call IDs for which the complete tool call has been received
+	completedToolCalls map[string]bool
 	// Error encountered during event processing
 	err error
 
 	// State tracking
 	currentState responseStateEnum
 	responseID   string
+	// Whether the next text or reasoning delta starts a new block, because a
+	// TextStartEvent or ReasoningStartEvent was received
+	startBlock bool
+	// Text and reasoning blocks that were started, mapped to whether they are
+	// still open
+	blocks map[blockKey]bool
 
 	// Usage tracking
 	usage api.Usage
@@ -46,9 +61,11 @@ func NewResponseBuilder() *ResponseBuilder {
 			Warnings:     []api.CallWarning{},
 			FinishReason: api.FinishReason(""),
 		},
-		toolCallIndices: make(map[string]int),
-		currentState:    noState,
-		err:             nil,
+		toolCallIndices:    make(map[string]int),
+		completedToolCalls: make(map[string]bool),
+		blocks:             make(map[blockKey]bool),
+		currentState:       noState,
+		err:                nil,
 	}
 }
 
@@ -70,16 +87,30 @@ func (b *ResponseBuilder) AddEvent(event api.StreamEvent) error {
 
 	switch evt := event.(type) {
 	// Handle pointer types
+	case *api.StreamStartEvent:
+		return b.addStreamStart(evt)
+	case *api.TextStartEvent:
+		return b.addTextStart(evt)
 	case *api.TextDeltaEvent:
 		return b.addTextDelta(evt)
+	case *api.TextEndEvent:
+		return b.addTextEnd(evt)
+	case *api.ReasoningStartEvent:
+		return b.addReasoningStart(evt)
 	case *api.ReasoningEvent:
 		return b.addReasoning(evt)
+	case *api.ReasoningEndEvent:
+		return b.addReasoningEnd(evt)
 	case *api.ReasoningSignatureEvent:
 		return b.addReasoningSignature(evt)
 	case *api.ToolCallEvent:
 		return b.addToolCall(evt)
+	case *api.ToolInputStartEvent:
+		return b.addToolInputStart(evt)
 	case *api.ToolCallDeltaEvent:
 		return b.addToolCallDelta(evt)
+	case *api.ToolInputEndEvent:
+		return b.addToolInputEnd(evt)
 	case *api.SourceEvent:
 		return b.addSource(evt)
 	case *api.FileEvent:
@@ -96,10 +127,58 @@ func (b *ResponseBuilder) AddEvent(event api.StreamEvent) error {
 	}
 }
 
+// addStreamStart adds the warnings of a stream start event to the response.
+func (b *ResponseBuilder) addStreamStart(e *api.StreamStartEvent) error {
+	b.resp.Warnings = append(b.resp.Warnings, e.Warnings...)
+	return nil
+}
+
+// addTextStart starts a new text block with the next text delta.
+func (b *ResponseBuilder) addTextStart(e *api.TextStartEvent) error {
+	return b.startContentBlock(blockKey{"text", e.ID})
+}
+
+// addTextEnd ends the text block started with the same ID.
+func (b *ResponseBuilder) addTextEnd(e *api.TextEndEvent) error {
+	return b.endContentBlock(blockKey{"text", e.ID})
+}
+
+// addReasoningStart starts a new reasoning block with the next reasoning delta.
+func (b *ResponseBuilder) addReasoningStart(e *api.ReasoningStartEvent) error {
+	return b.startContentBlock(blockKey{"reasoning", e.ID})
+}
+
+// addReasoningEnd ends the reasoning block started with the same ID.
+func (b *ResponseBuilder) addReasoningEnd(e *api.ReasoningEndEvent) error {
+	return b.endContentBlock(blockKey{"reasoning", e.ID})
+}
+
+// startContentBlock records that the block identified by key is open. Block
+// IDs must be unique within a stream.
+func (b *ResponseBuilder) startContentBlock(key blockKey) error {
+	if _, exists := b.blocks[key]; exists {
+		return fmt.Errorf("duplicate %s start for ID: %s", key.kind, key.id)
+	}
+	b.blocks[key] = true
+	b.startBlock = true
+	return nil
+}
+
+// endContentBlock records that the open block identified by key has ended.
+func (b *ResponseBuilder) endContentBlock(key blockKey) error {
+	if !b.blocks[key] {
+		return fmt.Errorf("%s end for unknown or ended ID: %s", key.kind, key.id)
+	}
+	b.blocks[key] = false
+	b.startBlock = false
+	return nil
+}
+
 // addTextDelta adds a text delta event to the response.
 func (b *ResponseBuilder) addTextDelta(e *api.TextDeltaEvent) error {
 	// Only concatenate with last block if the last content block is a TextBlock
-	if len(b.resp.Content) > 0 {
+	// and no new text block was started
+	if len(b.resp.Content) > 0 && !b.startBlock {
 		if lastBlock, ok := b.resp.Content[len(b.resp.Content)-1].(*api.TextBlock); ok {
 			// Append to existing text block
 			lastBlock.Text += e.TextDelta
@@ -109,6 +188,7 @@ func (b *ResponseBuilder) addTextDelta(e *api.TextDeltaEvent) error {
 
 	// Create new text block
 	b.currentState = textState
+	b.startBlock = false
 	b.resp.Content = append(b.resp.Content, &api.TextBlock{
 		Text: e.TextDelta,
 	})
@@ -118,7 +198,8 @@ func (b *ResponseBuilder) addTextDelta(e *api.TextDeltaEvent) error {
 // addReasoning adds a reasoning event to the response.
 func (b *ResponseBuilder) addReasoning(e *api.ReasoningEvent) error {
 	// Only concatenate with last block if the last content block is a ReasoningBlock
-	if len(b.resp.Content) > 0 {
+	// and no new reasoning block was started
+	if len(b.resp.Content) > 0 && !b.startBlock {
 		if lastBlock, ok := b.resp.Content[len(b.resp.Content)-1].(*api.ReasoningBlock); ok {
 			// Append to existing reasoning block
 			lastBlock.Text += e.TextDelta
@@ -133,6 +214,7 @@ func (b *ResponseBuilder) addReasoning(e *api.ReasoningEvent) error {
 
 	// Create new reasoning block
 	b.currentState = reasoningState
+	b.startBlock = false
 	b.resp.Content = append(b.resp.Content, &api.ReasoningBlock{
 		Text: e.TextDelta,
 	})
@@ -153,14 +235,25 @@ func (b *ResponseBuilder) addReasoningSignature(e *api.ReasoningSignatureEvent)
 }
 
 // addToolCall adds a tool call event to the response.
+// If the tool call was started by earlier tool input or delta events, its
+// arguments are replaced by the complete arguments of the event.
 func (b *ResponseBuilder) addToolCall(e *api.ToolCallEvent) error {
 	// Check for duplicate tool call ID
-	if _, exists := b.toolCallIndices[e.ToolCallID]; exists {
+	if b.completedToolCalls[e.ToolCallID] {
 		return fmt.Errorf("duplicate tool call ID: %s", e.ToolCallID)
 	}
+	b.completedToolCalls[e.ToolCallID] = true
 
 	b.currentState = toolCallState
 
+	if idx, exists := b.toolCallIndices[e.ToolCallID]; exists {
+		if toolCall, ok := b.resp.Content[idx].(*api.ToolCallBlock); ok {
+			toolCall.ToolName = e.ToolName
+			toolCall.Args = slices.Clone(e.Args)
+		}
+		return nil
+	}
+
 	// Create new tool call block
 	toolCall := &api.ToolCallBlock{
 		ToolCallID: e.ToolCallID,
@@ -174,6 +267,25 @@ func (b *ResponseBuilder) addToolCall(e *api.ToolCallEvent) error {
 	return nil
 }
 
+// addToolInputStart adds an empty tool call block to the response.
+func (b *ResponseBuilder) addToolInputStart(e *api.ToolInputStartEvent) error {
+	if _, exists := b.toolCallIndices[e.ToolCallID]; exists {
+		return fmt.Errorf("duplicate tool input start for tool call ID: %s", e.ToolCallID)
+	}
+
+	b.currentState = toolCallState
+	b.startToolCall(e.ToolCallID, e.ToolName)
+	return nil
+}
+
+// addToolInputEnd ends the arguments of a tool call.
+func (b *ResponseBuilder) addToolInputEnd(e *api.ToolInputEndEvent) error {
+	if _, exists := b.toolCallIndices[e.ToolCallID]; !exists {
+		return fmt.Errorf("tool input end for unknown tool call ID: %s", e.ToolCallID)
+	}
+	return nil
+}
+
 // addToolCallDelta adds a tool call delta event to the response.
 func (b *ResponseBuilder) addToolCallDelta(e *api.ToolCallDeltaEvent) error {
 	b.currentState = toolCallState
@@ -181,15 +293,7 @@ func (b *ResponseBuilder) addToolCallDelta(e *api.ToolCallDeltaEvent) error {
 	// Get or create the tool call block
 	idx, exists := b.toolCallIndices[e.ToolCallID]
 	if !exists {
-		// Create new tool call block if it doesn't exist
-		toolCall := &api.ToolCallBlock{
-			ToolCallID: e.ToolCallID,
-			ToolName:   e.ToolName,
-			Args:       make([]byte, 0),
-		}
-		b.resp.Content = append(b.resp.Content, toolCall)
-		idx = len(b.resp.Content) - 1
-		b.toolCallIndices[e.ToolCallID] = idx
+		idx = b.startToolCall(e.ToolCallID, e.ToolName)
 	}
 
 	// Append the new args to the existing args
@@ -200,6 +304,20 @@ func (b *ResponseBuilder) addToolCallDelta(e *api.ToolCallDeltaEvent) error {
 	return nil
 }
 
+// startToolCall adds a tool call block without arguments to the response and
+// returns its index in the Content array.
+func (b *ResponseBuilder) startToolCall(toolCallID, toolName string) int {
+	toolCall := &api.ToolCallBlock{
+		ToolCallID: toolCallID,
+		ToolName:   toolName,
+		Args:       make([]byte, 0),
+	}
+	b.resp.Content = append(b.resp.Content, toolCall)
+	idx := len(b.resp.Content) - 1
+	b.toolCallIndices[toolCallID] = idx
+	return idx
+}
+
 // addSource adds a source event to the response.
 func (b *ResponseBuilder) addSource(e *api.SourceEvent) error {
 	b.currentState = sourceState
@@ -261,8 +379,11 @@ func (b *ResponseBuilder) addFinish(e *api.FinishEvent) error {
 
 // addError adds an error event to the response.
 func (b *ResponseBuilder) addError(e *api.ErrorEvent) error {
-	// Store the error to be returned by Build
-	b.err = e
+	// Store the error to be returned by Build. The first error wins: later
+	// errors are usually a consequence of it.
+	if b.err == nil {
+		b.err = e
+	}
 	return nil
 }
 
@@ -2,10 +2,12 @@ package builder
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.jetify.com/ai/aitesting"
 	"go.jetify.com/ai/api"
 )
@@ -189,7 +191,7 @@ func TestResponseBuilder(t *testing.T) {
 			name: "error event",
 			events: []api.StreamEvent{
 				&api.ErrorEvent{
-					Err: "test error",
+					Err: errors.New("test error"),
 				},
 			},
 			expected: &api.Response{},
@@ -447,7 +449,7 @@ func TestResponseBuilder_ValueTypes(t *testing.T) {
 		{
 			name: "error event value type",
 			events: []api.StreamEvent{
-				&api.ErrorEvent{Err: "test error"}, // Value type
+				&api.ErrorEvent{Err: errors.New("test error")}, // Value type
 			},
 			expected: &api.Response{},
 		},
@@ -477,3 +479,113 @@ func TestResponseBuilder_ValueTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestResponseBuilder_LifecycleEvents(t *testing.T) {
+	warning := api.CallWarning{Type: "unsupported-setting", Setting: "TopK"}
+	events := []api.StreamEvent{
+		&api.StreamStartEvent{Warnings: []api.CallWarning{warning}},
+		&api.ReasoningStartEvent{ID: "reasoning-0"},
+		&api.ReasoningEvent{TextDelta: "Thinking"},
+		&api.ReasoningEndEvent{ID: "reasoning-0"},
+		&api.TextStartEvent{ID: "text-1"},
+		&api.TextDeltaEvent{TextDelta: "First "},
+		&api.TextDeltaEvent{TextDelta: "block"},
+		&api.TextEndEvent{ID: "text-1"},
+		&api.TextStartEvent{ID: "text-2"},
+		&api.TextDeltaEvent{TextDelta: "Second block"},
+		&api.TextEndEvent{ID: "text-2"},
+		&api.ToolInputStartEvent{ToolCallID: "call_1", ToolName: "weather"},
+		&api.ToolCallDeltaEvent{ToolCallID: "call_1", ToolName: "weather", ArgsDelta: []byte(`{"city":`)},
+		&api.ToolCallDeltaEvent{ToolCallID: "call_1", ToolName: "weather", ArgsDelta: []byte(`"Paris"}`)},
+		&api.ToolInputEndEvent{ToolCallID: "call_1"},
+		&api.ToolCallEvent{ToolCallID: "call_1", ToolName: "weather", Args: json.RawMessage(`{"city": "Paris"}`)},
+		&api.FinishEvent{FinishReason: api.FinishReasonToolCalls},
+	}
+
+	builder := NewResponseBuilder()
+	for _, event := range events {
+		require.NoError(t, builder.AddEvent(event))
+	}
+	resp, err := builder.Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, []api.CallWarning{warning}, resp.Warnings)
+	assert.Equal(t, []api.ContentBlock{
+		&api.ReasoningBlock{Text: "Thinking"},
+		&api.TextBlock{Text: "First block"},
+		&api.TextBlock{Text: "Second block"},
+		&api.ToolCallBlock{ToolCallID: "call_1", ToolName: "weather", Args: json.RawMessage(`{"city": "Paris"}`)},
+	}, resp.Content)
+	assert.Equal(t, api.FinishReasonToolCalls, resp.FinishReason)
+}
+
+func TestResponseBuilder_LifecycleErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []api.StreamEvent
+		err    string
+	}{
+		{
+			name: "duplicate tool call",
+			events: []api.StreamEvent{
+				&api.ToolCallEvent{ToolCallID: "call_1", ToolName: "weather", Args: json.RawMessage(`{}`)},
+				&api.ToolCallEvent{ToolCallID: "call_1", ToolName: "weather", Args: json.RawMessage(`{}`)},
+			},
+			err: "duplicate tool call ID: call_1",
+		},
+		{
+			name: "duplicate tool input start",
+			events: []api.StreamEvent{
+				&api.ToolInputStartEvent{ToolCallID: "call_1", ToolName: "weather"},
+				&api.ToolInputStartEvent{ToolCallID: "call_1", ToolName: "weather"},
+			},
+			err: "duplicate tool input start for tool call ID: call_1",
+		},
+		{
+			name: "tool input end without start",
+			events: []api.StreamEvent{
+				&api.ToolInputEndEvent{ToolCallID: "call_1"},
+			},
+			err: "tool input end for unknown tool call ID: call_1",
+		},
+		{
+			name: "duplicate text start",
+			events: []api.StreamEvent{
+				&api.TextStartEvent{ID: "text-0"},
+				&api.TextEndEvent{ID: "text-0"},
+				&api.TextStartEvent{ID: "text-0"},
+			},
+			err: "duplicate text start for ID: text-0",
+		},
+		{
+			name: "reasoning end without start",
+			events: []api.StreamEvent{
+				&api.ReasoningStartEvent{ID: "reasoning-0"},
+				&api.ReasoningEndEvent{ID: "reasoning-1"},
+			},
+			err: "reasoning end for unknown or ended ID: reasoning-1",
+		},
+		{
+			name: "text end after end",
+			events: []api.StreamEvent{
+				&api.TextStartEvent{ID: "text-0"},
+				&api.TextEndEvent{ID: "text-0"},
+				&api.TextEndEvent{ID: "text-0"},
+			},
+			err: "text end for unknown or ended ID: text-0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewResponseBuilder()
+			var err error
+			for _, event := range tt.events {
+				if err = builder.AddEvent(event); err != nil {
+					break
+				}
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
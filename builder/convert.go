@@ -2,6 +2,11 @@ package builder
 
 import "go.jetify.com/ai/api"
 
+// StreamToResponse consumes every event of stream and returns the Response
+// they describe. If the stream contains an ErrorEvent, the response built so
+// far is returned together with the error. If the builder rejects an event,
+// the stream is not read any further and the response built from the events
+// before it is returned together with the builder's error.
 func StreamToResponse(stream *api.StreamResponse) (*api.Response, error) {
 	if stream == nil {
 		return nil, nil
@@ -11,21 +16,63 @@ func StreamToResponse(stream *api.StreamResponse) (*api.Response, error) {
 
 	// Add any metadata from the stream response
 	if err := builder.AddMetadata(stream); err != nil {
-		return nil, err
+		resp, _ := builder.Build()
+		return resp, err
 	}
 
 	// Process each event in the stream
 	for event := range stream.Stream {
 		if err := builder.AddEvent(event); err != nil {
-			return nil, err
+			resp, _ := builder.Build()
+			return resp, err
 		}
 	}
 
 	// Build the final response
-	resp, err := builder.Build()
-	if err != nil {
-		return nil, err
+	return builder.Build()
+}
+
+// TeeStream returns a StreamResponse that yields the events of stream
+// unchanged while folding them into a Response, so that events can be
+// forwarded to a client while the final result is kept for logging or
+// persistence.
+//
+// Once iteration of the returned stream ends, either because the stream is
+// exhausted or because the consumer stopped early, onDone is called with the
+// response built from the events yielded so far. The error is the first
+// ErrorEvent of the stream or the first event the builder rejected; rejected
+// events are still forwarded. onDone is not called if the stream is never
+// iterated.
+func TeeStream(
+	stream *api.StreamResponse, onDone func(*api.Response, error),
+) *api.StreamResponse {
+	if stream == nil {
+		return nil
 	}
 
-	return resp, nil
+	tee := *stream
+	tee.Stream = func(yield func(api.StreamEvent) bool) {
+		builder := NewResponseBuilder()
+		var addErr error
+		defer func() {
+			resp, err := builder.Build()
+			if addErr != nil {
+				err = addErr
+			}
+			onDone(resp, err)
+		}()
+
+		if err := builder.AddMetadata(stream); err != nil {
+			addErr = err
+		}
+		for event := range stream.Stream {
+			if err := builder.AddEvent(event); err != nil && addErr == nil {
+				addErr = err
+			}
+			if !yield(event) {
+				return
+			}
+		}
+	}
+	return &tee
 }
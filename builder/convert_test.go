@@ -0,0 +1,170 @@
+package builder
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+)
+
+func streamOf(events ...api.StreamEvent) *api.StreamResponse {
+	return &api.StreamResponse{
+		Stream:       slices.Values(events),
+		ResponseInfo: &api.ResponseInfo{ID: "resp-1"},
+	}
+}
+
+func TestStreamToResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		stream   *api.StreamResponse
+		expected *api.Response
+		err      string
+	}{
+		{
+			name:     "nil stream",
+			stream:   nil,
+			expected: nil,
+		},
+		{
+			name: "text and finish",
+			stream: streamOf(
+				&api.TextDeltaEvent{TextDelta: "Hello "},
+				&api.TextDeltaEvent{TextDelta: "World"},
+				&api.FinishEvent{FinishReason: api.FinishReasonStop, Usage: api.Usage{InputTokens: 1, OutputTokens: 2}},
+			),
+			expected: &api.Response{
+				Content:      []api.ContentBlock{&api.TextBlock{Text: "Hello World"}},
+				Warnings:     []api.CallWarning{},
+				FinishReason: api.FinishReasonStop,
+				Usage:        api.Usage{InputTokens: 1, OutputTokens: 2},
+				ResponseInfo: &api.ResponseInfo{ID: "resp-1"},
+			},
+		},
+		{
+			name: "error event",
+			stream: streamOf(
+				&api.TextDeltaEvent{TextDelta: "Hello"},
+				&api.ErrorEvent{Err: errors.New("connection reset")},
+			),
+			expected: &api.Response{
+				Content:      []api.ContentBlock{&api.TextBlock{Text: "Hello"}},
+				Warnings:     []api.CallWarning{},
+				ResponseInfo: &api.ResponseInfo{ID: "resp-1"},
+			},
+			err: "connection reset",
+		},
+		{
+			name: "first error event wins",
+			stream: streamOf(
+				&api.TextDeltaEvent{TextDelta: "Hello"},
+				&api.ErrorEvent{Err: errors.New("connection reset")},
+				&api.ErrorEvent{Err: errors.New("stream closed")},
+			),
+			expected: &api.Response{
+				Content:      []api.ContentBlock{&api.TextBlock{Text: "Hello"}},
+				Warnings:     []api.CallWarning{},
+				ResponseInfo: &api.ResponseInfo{ID: "resp-1"},
+			},
+			err: "connection reset",
+		},
+		{
+			name: "rejected event",
+			stream: streamOf(
+				&api.TextDeltaEvent{TextDelta: "Hello"},
+				&api.FinishEvent{FinishReason: api.FinishReasonStop},
+				&api.TextDeltaEvent{TextDelta: " again"},
+			),
+			expected: &api.Response{
+				Content:      []api.ContentBlock{&api.TextBlock{Text: "Hello"}},
+				Warnings:     []api.CallWarning{},
+				FinishReason: api.FinishReasonStop,
+				ResponseInfo: &api.ResponseInfo{ID: "resp-1"},
+			},
+			err: "cannot add events after finish event",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := StreamToResponse(tt.stream)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, resp)
+		})
+	}
+}
+
+func TestTeeStream(t *testing.T) {
+	events := []api.StreamEvent{
+		&api.TextDeltaEvent{TextDelta: "Hello "},
+		&api.TextDeltaEvent{TextDelta: "World"},
+		&api.FinishEvent{FinishReason: api.FinishReasonStop},
+	}
+
+	t.Run("forwards events and builds the response", func(t *testing.T) {
+		var final *api.Response
+		calls := 0
+		tee := TeeStream(streamOf(events...), func(resp *api.Response, err error) {
+			calls++
+			final = resp
+			assert.NoError(t, err)
+		})
+		assert.Equal(t, &api.ResponseInfo{ID: "resp-1"}, tee.ResponseInfo)
+
+		var forwarded []api.StreamEvent
+		for event := range tee.Stream {
+			forwarded = append(forwarded, event)
+			assert.Zero(t, calls, "onDone must run after the last event")
+		}
+
+		assert.Equal(t, events, forwarded)
+		require.Equal(t, 1, calls)
+		assert.Equal(t, []api.ContentBlock{&api.TextBlock{Text: "Hello World"}}, final.Content)
+		assert.Equal(t, api.FinishReasonStop, final.FinishReason)
+	})
+
+	t.Run("consumer stops early", func(t *testing.T) {
+		var final *api.Response
+		tee := TeeStream(streamOf(events...), func(resp *api.Response, err error) {
+			final = resp
+			assert.NoError(t, err)
+		})
+		for range tee.Stream {
+			break
+		}
+		require.NotNil(t, final)
+		assert.Equal(t, []api.ContentBlock{&api.TextBlock{Text: "Hello "}}, final.Content)
+		assert.Empty(t, final.FinishReason)
+	})
+
+	t.Run("reports stream errors", func(t *testing.T) {
+		streamErr := errors.New("connection reset")
+		var gotErr error
+		tee := TeeStream(streamOf(
+			&api.TextDeltaEvent{TextDelta: "Hello"},
+			&api.ErrorEvent{Err: streamErr},
+		), func(resp *api.Response, err error) {
+			gotErr = err
+		})
+
+		count := 0
+		for range tee.Stream {
+			count++
+		}
+		assert.Equal(t, 2, count, "error events are forwarded")
+		assert.EqualError(t, gotErr, "connection reset")
+	})
+
+	t.Run("nil stream", func(t *testing.T) {
+		assert.Nil(t, TeeStream(nil, func(*api.Response, error) {
+			t.Fatal("onDone must not be called")
+		}))
+	})
+}
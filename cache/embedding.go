@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"go.jetify.com/ai/api"
+)
+
+// WrapEmbeddingModel returns an embedding model that caches the embedding of
+// every input value in store.
+//
+// Each value is looked up by the provider name, model ID and the value itself,
+// together with the provider metadata of the call, since it can change the
+// embeddings a provider returns. Only the values that are not cached are sent
+// to model, in a single DoEmbed call, and their embeddings are stored before
+// the results are merged back in input order. The cache is best effort: a
+// value that store fails to return is treated as a miss, and if store fails to
+// save an embedding, the embedding is still returned and will be requested
+// from model again on the next call.
+//
+// Usage and RawResponse describe the call made for the cache misses; both are
+// nil when every value was found in the cache.
+func WrapEmbeddingModel[T api.EmbeddingInput, E api.EmbeddingVector](
+	model api.EmbeddingModel[T, E], store Store,
+) api.EmbeddingModel[T, E] {
+	return &embeddingModel[T, E]{model: model, store: store}
+}
+
+type embeddingModel[T api.EmbeddingInput, E api.EmbeddingVector] struct {
+	model api.EmbeddingModel[T, E]
+	store Store
+}
+
+func (m *embeddingModel[T, E]) SpecificationVersion() string { return m.model.SpecificationVersion() }
+
+func (m *embeddingModel[T, E]) ProviderName() string { return m.model.ProviderName() }
+
+func (m *embeddingModel[T, E]) ModelID() string { return m.model.ModelID() }
+
+func (m *embeddingModel[T, E]) MaxEmbeddingsPerCall() *int { return m.model.MaxEmbeddingsPerCall() }
+
+func (m *embeddingModel[T, E]) SupportsParallelCalls() bool { return m.model.SupportsParallelCalls() }
+
+func (m *embeddingModel[T, E]) DoEmbed(
+	ctx context.Context, values []T, opts api.TransportOptions,
+) (api.EmbeddingResponse[E], error) {
+	embeddings := make([]E, len(values))
+
+	// misses maps the key of every value that is not cached to the positions
+	// of that value in the input, so duplicates are only embedded once.
+	misses := map[string][]int{}
+	var missKeys []string
+	var missValues []T
+	for i, value := range values {
+		key, err := m.key(value, opts)
+		if err != nil {
+			return api.EmbeddingResponse[E]{}, err
+		}
+
+		if _, seen := misses[key]; !seen {
+			if embedding, ok := m.lookup(ctx, key); ok {
+				embeddings[i] = embedding
+				continue
+			}
+			missKeys = append(missKeys, key)
+			missValues = append(missValues, value)
+		}
+		misses[key] = append(misses[key], i)
+	}
+
+	if len(missValues) == 0 {
+		return api.EmbeddingResponse[E]{Embeddings: embeddings}, nil
+	}
+
+	resp, err := m.model.DoEmbed(ctx, missValues, opts)
+	if err != nil {
+		return api.EmbeddingResponse[E]{}, err
+	}
+	if len(resp.Embeddings) != len(missValues) {
+		return api.EmbeddingResponse[E]{}, api.NewInvalidResponseDataError(resp.Embeddings,
+			fmt.Sprintf("expected %d embeddings, got %d", len(missValues), len(resp.Embeddings)))
+	}
+
+	for j, key := range missKeys {
+		embedding := resp.Embeddings[j]
+		m.save(ctx, key, embedding)
+		for _, i := range misses[key] {
+			embeddings[i] = embedding
+		}
+	}
+
+	resp.Embeddings = embeddings
+	return resp, nil
+}
+
+// lookup returns the cached embedding stored under key. Entries that cannot
+// be read or decoded are treated as missing, so that they are embedded again
+// and overwritten.
+func (m *embeddingModel[T, E]) lookup(ctx context.Context, key string) (E, bool) {
+	var embedding E
+	data, ok, err := m.store.Get(ctx, key)
+	if err != nil || !ok {
+		return embedding, false
+	}
+	if err := json.Unmarshal(data, &embedding); err != nil {
+		return embedding, false
+	}
+	return embedding, true
+}
+
+// save stores embedding under key. Errors are ignored: the embedding was
+// already paid for, and failing the call would discard it.
+func (m *embeddingModel[T, E]) save(ctx context.Context, key string, embedding E) {
+	data, err := json.Marshal(embedding)
+	if err != nil {
+		return
+	}
+	_ = m.store.Set(ctx, key, data)
+}
+
+// cacheKey holds everything that determines the embedding of a value.
+type cacheKey[T api.EmbeddingInput] struct {
+	Provider string                `json:"provider"`
+	Model    string                `json:"model"`
+	Input    T                     `json:"input"`
+	Metadata *api.ProviderMetadata `json:"metadata,omitempty"`
+}
+
+// key returns the hex-encoded SHA-256 hash of the cache key for value.
+func (m *embeddingModel[T, E]) key(value T, opts api.TransportOptions) (string, error) {
+	data, err := json.Marshal(cacheKey[T]{
+		Provider: m.model.ProviderName(),
+		Model:    m.model.ModelID(),
+		Input:    value,
+		Metadata: opts.ProviderMetadata,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai"
+	"go.jetify.com/ai/api"
+)
+
+// countingEmbeddingModel embeds every value with embed and records the values
+// of every DoEmbed call.
+type countingEmbeddingModel[T api.EmbeddingInput, E api.EmbeddingVector] struct {
+	modelID string
+	embed   func(T) E
+	calls   [][]T
+}
+
+func (m *countingEmbeddingModel[T, E]) SpecificationVersion() string { return "v1" }
+func (m *countingEmbeddingModel[T, E]) ProviderName() string         { return "fake" }
+func (m *countingEmbeddingModel[T, E]) ModelID() string              { return m.modelID }
+func (m *countingEmbeddingModel[T, E]) MaxEmbeddingsPerCall() *int   { return nil }
+func (m *countingEmbeddingModel[T, E]) SupportsParallelCalls() bool  { return true }
+
+func (m *countingEmbeddingModel[T, E]) DoEmbed(
+	ctx context.Context, values []T, opts api.TransportOptions,
+) (api.EmbeddingResponse[E], error) {
+	m.calls = append(m.calls, values)
+	embeddings := make([]E, len(values))
+	for i, v := range values {
+		embeddings[i] = m.embed(v)
+	}
+	return api.EmbeddingResponse[E]{
+		Embeddings: embeddings,
+		Usage:      &api.EmbeddingUsage{PromptTokens: int64(len(values)), TotalTokens: int64(len(values))},
+	}, nil
+}
+
+func denseModel(modelID string) *countingEmbeddingModel[string, api.Embedding] {
+	return &countingEmbeddingModel[string, api.Embedding]{
+		modelID: modelID,
+		embed:   func(v string) api.Embedding { return api.Embedding{float64(len(v))} },
+	}
+}
+
+func TestWrapEmbeddingModel_Dense(t *testing.T) {
+	model := denseModel("dense")
+	cached := WrapEmbeddingModel(model, NewMemoryStore(0))
+
+	resp, err := ai.EmbedMany(t.Context(), cached, []string{"a", "bb"})
+	require.NoError(t, err)
+	assert.Equal(t, []api.Embedding{{1}, {2}}, resp.Embeddings)
+
+	resp, err = ai.EmbedMany(t.Context(), cached, []string{"ccc", "a", "ccc", "bb"})
+	require.NoError(t, err)
+	assert.Equal(t, []api.Embedding{{3}, {1}, {3}, {2}}, resp.Embeddings)
+	assert.Equal(t, &api.EmbeddingUsage{PromptTokens: 1, TotalTokens: 1}, resp.Usage)
+
+	resp, err = ai.EmbedMany(t.Context(), cached, []string{"bb", "ccc"})
+	require.NoError(t, err)
+	assert.Equal(t, []api.Embedding{{2}, {3}}, resp.Embeddings)
+	assert.Nil(t, resp.Usage)
+
+	assert.Equal(t, [][]string{{"a", "bb"}, {"ccc"}}, model.calls)
+}
+
+func TestWrapEmbeddingModel_KeyedByModelAndMetadata(t *testing.T) {
+	store := NewMemoryStore(0)
+	first := denseModel("first")
+	second := denseModel("second")
+
+	_, err := WrapEmbeddingModel(first, store).DoEmbed(t.Context(), []string{"a"}, api.TransportOptions{})
+	require.NoError(t, err)
+	_, err = WrapEmbeddingModel(second, store).DoEmbed(t.Context(), []string{"a"}, api.TransportOptions{})
+	require.NoError(t, err)
+	assert.Len(t, second.calls, 1, "a different model must not share cache entries")
+
+	metadata := api.NewProviderMetadata(map[string]any{"fake": map[string]any{"input_type": "query"}})
+	_, err = WrapEmbeddingModel(first, store).DoEmbed(t.Context(), []string{"a"},
+		api.TransportOptions{ProviderMetadata: metadata})
+	require.NoError(t, err)
+	assert.Len(t, first.calls, 2, "different provider metadata must not share cache entries")
+}
+
+func TestWrapEmbeddingModel_Sparse(t *testing.T) {
+	model := &countingEmbeddingModel[string, api.SparseEmbedding]{
+		modelID: "sparse",
+		embed:   func(v string) api.SparseEmbedding { return api.SparseEmbedding{v: 1} },
+	}
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	cached := WrapEmbeddingModel(model, store)
+
+	for range 2 {
+		resp, err := cached.DoEmbed(t.Context(), []string{"a", "b"}, api.TransportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []api.SparseEmbedding{{"a": 1}, {"b": 1}}, resp.Embeddings)
+	}
+	assert.Len(t, model.calls, 1)
+}
+
+func TestWrapEmbeddingModel_Multimodal(t *testing.T) {
+	text, image := "a cat", "https://example.com/cat.png"
+	model := &countingEmbeddingModel[api.MultimodalEmbeddingInput, api.Embedding]{
+		modelID: "multimodal",
+		embed: func(v api.MultimodalEmbeddingInput) api.Embedding {
+			if v.Image != nil {
+				return api.Embedding{1}
+			}
+			return api.Embedding{0}
+		},
+	}
+	cached := WrapEmbeddingModel(model, NewMemoryStore(10))
+
+	inputs := []api.MultimodalEmbeddingInput{{Text: &text}, {Image: &image}}
+	for range 2 {
+		resp, err := cached.DoEmbed(t.Context(), inputs, api.TransportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []api.Embedding{{0}, {1}}, resp.Embeddings)
+	}
+	assert.Len(t, model.calls, 1)
+}
+
+func TestWrapEmbeddingModel_CorruptEntry(t *testing.T) {
+	store := NewMemoryStore(0)
+	model := denseModel("dense")
+	cached := WrapEmbeddingModel(model, store)
+
+	_, err := cached.DoEmbed(t.Context(), []string{"a"}, api.TransportOptions{})
+	require.NoError(t, err)
+	for e := store.order.Front(); e != nil; e = e.Next() {
+		e.Value.(*memoryEntry).value = []byte("not json")
+	}
+
+	resp, err := cached.DoEmbed(t.Context(), []string{"a"}, api.TransportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []api.Embedding{{1}}, resp.Embeddings)
+	assert.Len(t, model.calls, 2)
+}
+
+// failingStore is a Store whose writes always fail.
+type failingStore struct{ *MemoryStore }
+
+func (s failingStore) Set(ctx context.Context, key string, value []byte) error {
+	return errors.New("disk full")
+}
+
+func TestWrapEmbeddingModel_SetFailure(t *testing.T) {
+	model := denseModel("dense")
+	cached := WrapEmbeddingModel(model, failingStore{NewMemoryStore(0)})
+
+	resp, err := cached.DoEmbed(t.Context(), []string{"a", "bb"}, api.TransportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []api.Embedding{{1}, {2}}, resp.Embeddings)
+}
+
+// unreadableStore is a Store whose reads always fail.
+type unreadableStore struct{ *MemoryStore }
+
+func (s unreadableStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, errors.New("connection refused")
+}
+
+func TestWrapEmbeddingModel_GetFailure(t *testing.T) {
+	model := denseModel("dense")
+	cached := WrapEmbeddingModel(model, unreadableStore{NewMemoryStore(0)})
+
+	for range 2 {
+		resp, err := cached.DoEmbed(t.Context(), []string{"a", "bb"}, api.TransportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []api.Embedding{{1}, {2}}, resp.Embeddings)
+	}
+	assert.Len(t, model.calls, 2)
+}
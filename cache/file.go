@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// FileStore is a [Store] that keeps each entry in its own file under a
+// directory, so that cached results survive process restarts and can be
+// shared between processes.
+//
+// Keys are used as file names and must therefore be valid file names; the
+// keys produced by this package always are.
+type FileStore struct {
+	dir string
+}
+
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore creates a store that keeps its entries in dir, creating the
+// directory if it does not exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Get reads the entry stored under key.
+func (s *FileStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// Set writes the entry stored under key. The file is written to a temporary
+// location first and then renamed, so concurrent readers never observe a
+// partially written entry.
+func (s *FileStore) Set(ctx context.Context, key string, value []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once the rename succeeded
+
+	if _, err := tmp.Write(value); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// path returns the file for key. Entries are spread over subdirectories named
+// after the first two characters of the key to keep directories small.
+func (s *FileStore) path(key string) string {
+	if len(key) > 2 {
+		return filepath.Join(s.dir, key[:2], key+".json")
+	}
+	return filepath.Join(s.dir, key+".json")
+}
@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir() + "/cache"
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+	ctx := t.Context()
+
+	_, ok, err := store.Get(ctx, "abcdef")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, store.Set(ctx, "abcdef", []byte("[1,2]")))
+	require.NoError(t, store.Set(ctx, "abcdef", []byte("[3,4]")))
+	require.NoError(t, store.Set(ctx, "x", []byte("[5]")))
+
+	// A second store over the same directory sees the same entries.
+	reopened, err := NewFileStore(dir)
+	require.NoError(t, err)
+
+	value, ok, err := reopened.Get(ctx, "abcdef")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("[3,4]"), value)
+
+	value, ok, err = reopened.Get(ctx, "x")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("[5]"), value)
+}
+
+func TestFileStore_Cancelled(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	require.ErrorIs(t, store.Set(ctx, "abcdef", []byte("[1]")), context.Canceled)
+	_, _, err = store.Get(ctx, "abcdef")
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// MemoryStore is an in-memory [Store] that evicts the least recently used
+// entry once it holds more than its capacity.
+type MemoryStore struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // front is the most recently used entry
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+type memoryEntry struct {
+	key   string
+	value []byte
+}
+
+// NewMemoryStore creates an LRU store holding at most capacity entries.
+// A capacity of zero or less means the store is unbounded.
+func NewMemoryStore(capacity int) *MemoryStore {
+	return &MemoryStore{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the value stored under key and marks it as recently used.
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	s.order.MoveToFront(elem)
+	return elem.Value.(*memoryEntry).value, true, nil
+}
+
+// Set stores value under key, evicting the least recently used entry if the
+// store is full.
+func (s *MemoryStore) Set(ctx context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.entries[key]; ok {
+		elem.Value.(*memoryEntry).value = value
+		s.order.MoveToFront(elem)
+		return nil
+	}
+
+	s.entries[key] = s.order.PushFront(&memoryEntry{key: key, value: value})
+	if s.capacity > 0 && s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryEntry).key)
+	}
+	return nil
+}
+
+// Len returns the number of entries in the store.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
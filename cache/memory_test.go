@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore(2)
+	ctx := t.Context()
+
+	_, ok, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, store.Set(ctx, "a", []byte("1")))
+	require.NoError(t, store.Set(ctx, "b", []byte("2")))
+
+	// Reading "a" makes "b" the least recently used entry.
+	value, ok, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	require.NoError(t, store.Set(ctx, "c", []byte("3")))
+	assert.Equal(t, 2, store.Len())
+
+	_, ok, _ = store.Get(ctx, "b")
+	assert.False(t, ok, "b should have been evicted")
+	_, ok, _ = store.Get(ctx, "a")
+	assert.True(t, ok)
+	_, ok, _ = store.Get(ctx, "c")
+	assert.True(t, ok)
+
+	require.NoError(t, store.Set(ctx, "c", []byte("4")))
+	value, _, _ = store.Get(ctx, "c")
+	assert.Equal(t, []byte("4"), value)
+	assert.Equal(t, 2, store.Len())
+}
+
+func TestMemoryStore_Unbounded(t *testing.T) {
+	store := NewMemoryStore(0)
+	for _, key := range []string{"a", "b", "c", "d"} {
+		require.NoError(t, store.Set(t.Context(), key, []byte(key)))
+	}
+	assert.Equal(t, 4, store.Len())
+}
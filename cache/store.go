@@ -0,0 +1,19 @@
+// Package cache provides caching wrappers for models and the stores that back
+// them.
+package cache
+
+import "context"
+
+// Store is a key-value store for cached model results.
+//
+// Keys are opaque strings produced by the caching wrappers; values are the
+// JSON encoding of the cached result. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	// Get returns the value stored under key. The boolean result reports
+	// whether the key was found.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores value under key, replacing any previous value.
+	Set(ctx context.Context, key string, value []byte) error
+}
@@ -0,0 +1,153 @@
+package ai
+
+import (
+	"context"
+	"sync"
+
+	"go.jetify.com/ai/api"
+)
+
+// embedMany splits values into chunks that respect the model's
+// MaxEmbeddingsPerCall limit, embeds each chunk and reassembles the results in
+// input order. Chunks are dispatched concurrently when the model supports
+// parallel calls.
+func embedMany[T api.EmbeddingInput, E api.EmbeddingVector](
+	ctx context.Context, model api.EmbeddingModel[T, E], values []T, config api.TransportOptions,
+) (api.EmbeddingResponse[E], error) {
+	doEmbed := func(ctx context.Context, values []T) (api.EmbeddingResponse[E], error) {
+		return retry(ctx, config.RetryPolicy, func() (api.EmbeddingResponse[E], error) {
+			return model.DoEmbed(ctx, values, config)
+		})
+	}
+
+	chunks := chunkRanges(len(values), model.MaxEmbeddingsPerCall())
+	if len(chunks) <= 1 {
+		return doEmbed(ctx, values)
+	}
+
+	responses := make([]api.EmbeddingResponse[E], len(chunks))
+	embedChunk := func(ctx context.Context, i int) error {
+		c := chunks[i]
+		if err := ctx.Err(); err != nil {
+			return api.NewEmbeddingBatchError(c.start, c.end, err)
+		}
+		resp, err := doEmbed(ctx, values[c.start:c.end])
+		if err != nil {
+			return api.NewEmbeddingBatchError(c.start, c.end, err)
+		}
+		responses[i] = resp
+		return nil
+	}
+
+	var err error
+	if model.SupportsParallelCalls() {
+		err = runParallel(ctx, len(chunks), config.MaxParallelCalls, embedChunk)
+	} else {
+		for i := range chunks {
+			if err = embedChunk(ctx, i); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return api.EmbeddingResponse[E]{}, err
+	}
+
+	return mergeEmbeddingResponses(len(values), responses), nil
+}
+
+type chunkRange struct {
+	start, end int
+}
+
+// chunkRanges splits n values into consecutive ranges of at most maxPerCall
+// values. A nil or non-positive limit yields a single range.
+func chunkRanges(n int, maxPerCall *int) []chunkRange {
+	if maxPerCall == nil || *maxPerCall <= 0 || n <= *maxPerCall {
+		return []chunkRange{{start: 0, end: n}}
+	}
+	size := *maxPerCall
+	chunks := make([]chunkRange, 0, (n+size-1)/size)
+	for start := 0; start < n; start += size {
+		chunks = append(chunks, chunkRange{start: start, end: min(start+size, n)})
+	}
+	return chunks
+}
+
+// runParallel calls fn for every index in [0, n) using at most limit
+// concurrent goroutines (no limit when limit <= 0). The context passed to fn
+// is cancelled as soon as any call fails, and the first error is returned.
+//
+// If ctx is cancelled before every index has been dispatched, fn is called
+// once more with the cancelled context for the first index that was not
+// dispatched, so that it can report the cancellation in its own terms. Once
+// every call has succeeded, nil is returned even if ctx has been cancelled
+// since.
+func runParallel(ctx context.Context, n, limit int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if limit <= 0 || limit > n {
+		limit = n
+	}
+	sem := make(chan struct{}, limit)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			if err := fn(ctx, i); err != nil {
+				fail(err)
+			}
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(ctx, i); err != nil {
+				fail(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// mergeEmbeddingResponses concatenates chunk responses in order and sums their
+// usage. Usage is left nil if no chunk reported it.
+func mergeEmbeddingResponses[E api.EmbeddingVector](
+	n int, responses []api.EmbeddingResponse[E],
+) api.EmbeddingResponse[E] {
+	merged := api.EmbeddingResponse[E]{
+		Embeddings: make([]E, 0, n),
+	}
+	for _, resp := range responses {
+		merged.Embeddings = append(merged.Embeddings, resp.Embeddings...)
+		if resp.Usage != nil {
+			if merged.Usage == nil {
+				merged.Usage = &api.EmbeddingUsage{}
+			}
+			merged.Usage.PromptTokens += resp.Usage.PromptTokens
+			merged.Usage.TotalTokens += resp.Usage.TotalTokens
+		}
+		if resp.RawResponse != nil {
+			merged.RawResponse = resp.RawResponse
+		}
+	}
+	return merged
+}
@@ -0,0 +1,184 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+)
+
+type fakeEmbeddingModel struct {
+	maxPerCall *int
+	parallel   bool
+	failOn     string
+	cancelOn   string
+	cancel     context.CancelFunc
+
+	mu       sync.Mutex
+	calls    [][]string
+	inFlight atomic.Int32
+	peak     atomic.Int32
+}
+
+var _ api.EmbeddingModel[string, api.Embedding] = (*fakeEmbeddingModel)(nil)
+
+func (m *fakeEmbeddingModel) SpecificationVersion() string { return "v1" }
+func (m *fakeEmbeddingModel) ProviderName() string         { return "fake" }
+func (m *fakeEmbeddingModel) ModelID() string              { return "fake-embedding" }
+func (m *fakeEmbeddingModel) MaxEmbeddingsPerCall() *int   { return m.maxPerCall }
+func (m *fakeEmbeddingModel) SupportsParallelCalls() bool  { return m.parallel }
+
+func (m *fakeEmbeddingModel) DoEmbed(
+	ctx context.Context, values []string, opts api.TransportOptions,
+) (api.EmbeddingResponse[api.Embedding], error) {
+	n := m.inFlight.Add(1)
+	defer m.inFlight.Add(-1)
+	for {
+		peak := m.peak.Load()
+		if n <= peak || m.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+
+	m.mu.Lock()
+	m.calls = append(m.calls, values)
+	m.mu.Unlock()
+
+	embeddings := make([]api.Embedding, len(values))
+	for i, v := range values {
+		if v == m.failOn {
+			return api.EmbeddingResponse[api.Embedding]{}, errors.New("boom")
+		}
+		if v == m.cancelOn {
+			m.cancel()
+		}
+		embeddings[i] = api.Embedding{float64(len(v))}
+	}
+	return api.EmbeddingResponse[api.Embedding]{
+		Embeddings: embeddings,
+		Usage: &api.EmbeddingUsage{
+			PromptTokens: int64(len(values)),
+			TotalTokens:  int64(len(values)),
+		},
+	}, nil
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestEmbedMany(t *testing.T) {
+	values := []string{"a", "bb", "ccc", "dddd", "eeeee"}
+	expected := []api.Embedding{{1}, {2}, {3}, {4}, {5}}
+
+	tests := []struct {
+		name          string
+		model         *fakeEmbeddingModel
+		opts          []TransportOption
+		expectedCalls int
+	}{
+		{
+			name:          "no limit",
+			model:         &fakeEmbeddingModel{},
+			expectedCalls: 1,
+		},
+		{
+			name:          "within limit",
+			model:         &fakeEmbeddingModel{maxPerCall: intPtr(10)},
+			expectedCalls: 1,
+		},
+		{
+			name:          "sequential chunks",
+			model:         &fakeEmbeddingModel{maxPerCall: intPtr(2)},
+			expectedCalls: 3,
+		},
+		{
+			name:          "parallel chunks",
+			model:         &fakeEmbeddingModel{maxPerCall: intPtr(1), parallel: true},
+			opts:          []TransportOption{WithTransportMaxParallelCalls(2)},
+			expectedCalls: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := EmbedMany(t.Context(), tt.model, values, tt.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, expected, resp.Embeddings)
+			assert.Equal(t, &api.EmbeddingUsage{PromptTokens: 5, TotalTokens: 5}, resp.Usage)
+			assert.Len(t, tt.model.calls, tt.expectedCalls)
+		})
+	}
+}
+
+func TestEmbedMany_SequentialPreservesOrder(t *testing.T) {
+	model := &fakeEmbeddingModel{maxPerCall: intPtr(2)}
+	_, err := EmbedMany(t.Context(), model, []string{"a", "b", "c", "d", "e"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, model.calls)
+	assert.Equal(t, int32(1), model.peak.Load())
+}
+
+func TestEmbedMany_MaxParallelCalls(t *testing.T) {
+	values := make([]string, 50)
+	for i := range values {
+		values[i] = "x"
+	}
+	model := &fakeEmbeddingModel{maxPerCall: intPtr(1), parallel: true}
+	_, err := EmbedMany(t.Context(), model, values, WithTransportMaxParallelCalls(3))
+	require.NoError(t, err)
+	assert.LessOrEqual(t, model.peak.Load(), int32(3))
+}
+
+func TestEmbedMany_ChunkError(t *testing.T) {
+	tests := []struct {
+		name     string
+		parallel bool
+	}{
+		{name: "sequential"},
+		{name: "parallel", parallel: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := &fakeEmbeddingModel{maxPerCall: intPtr(2), parallel: tt.parallel, failOn: "c"}
+			_, err := EmbedMany(t.Context(), model, []string{"a", "b", "c", "d", "e"})
+			require.Error(t, err)
+
+			var batchErr *api.EmbeddingBatchError
+			require.ErrorAs(t, err, &batchErr)
+			assert.Equal(t, 2, batchErr.Start)
+			assert.Equal(t, 4, batchErr.End)
+			assert.EqualError(t, errors.Unwrap(batchErr), "boom")
+		})
+	}
+}
+
+func TestEmbedMany_CancelledAfterLastChunk(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		ctx, cancel := context.WithCancel(t.Context())
+		model := &fakeEmbeddingModel{maxPerCall: intPtr(1), parallel: parallel, cancelOn: "bb", cancel: cancel}
+		resp, err := EmbedMany(ctx, model, []string{"a", "bb"}, WithTransportMaxParallelCalls(1))
+		require.NoError(t, err)
+		assert.Equal(t, []api.Embedding{{1}, {2}}, resp.Embeddings)
+	}
+}
+
+func TestEmbedMany_Cancelled(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		model := &fakeEmbeddingModel{maxPerCall: intPtr(1), parallel: parallel}
+		_, err := EmbedMany(ctx, model, []string{"a", "b"})
+
+		var batchErr *api.EmbeddingBatchError
+		require.ErrorAs(t, err, &batchErr)
+		assert.Equal(t, 0, batchErr.Start)
+		assert.Equal(t, 1, batchErr.End)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, model.calls)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/k0kubun/pp/v3"
+	"go.jetify.com/ai"
+	"go.jetify.com/ai/api"
+	cohereprovider "go.jetify.com/ai/provider/cohere"
+	cohere "go.jetify.com/ai/provider/cohere/client"
+)
+
+func example() error {
+	// Initialize the Cohere provider (reads COHERE_API_KEY)
+	provider := cohereprovider.NewProvider()
+
+	// Create a model
+	model, _ := provider.TextEmbeddingModel(cohere.EmbeddingModelEmbedV4)
+
+	// Embed a search query as int8 embeddings
+	inputType := cohere.InputTypeSearchQuery
+	response, err := ai.EmbedMany(
+		context.Background(),
+		model,
+		[]string{
+			"What is artificial intelligence?",
+		},
+		ai.WithTransportProviderMetadata("cohere", &cohere.EmbeddingNewParams{
+			InputType:      &inputType,
+			EmbeddingTypes: []cohere.EmbeddingType{cohere.EmbeddingTypeInt8},
+		}),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Print the response:
+	printResponse(response)
+
+	return nil
+}
+
+func printResponse(response api.DenseEmbeddingResponse) {
+	printer := pp.New()
+	printer.SetOmitEmpty(true)
+	printer.Print(response)
+}
+
+func main() {
+	if err := example(); err != nil {
+		log.Fatal(err)
+	}
+}
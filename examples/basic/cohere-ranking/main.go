@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/k0kubun/pp/v3"
+	"go.jetify.com/ai"
+	"go.jetify.com/ai/api"
+	cohereprovider "go.jetify.com/ai/provider/cohere"
+	cohere "go.jetify.com/ai/provider/cohere/client"
+)
+
+func example() error {
+	// Initialize the Cohere provider (reads COHERE_API_KEY)
+	provider := cohereprovider.NewProvider()
+
+	// Create a model
+	model, _ := provider.RankingModel(cohere.RankingModelRerankV35)
+
+	// Keep the two most relevant documents
+	response, err := ai.RankMany(
+		context.Background(),
+		model,
+		"What is the capital of France?",
+		[]string{
+			"Berlin is the capital of Germany.",
+			"Paris is the capital and largest city of France.",
+			"France is a country in Western Europe.",
+		},
+		ai.WithTopN(2),
+		ai.WithReturnDocuments(),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Print the response:
+	printResponse(response)
+
+	return nil
+}
+
+func printResponse(response api.RankingResponse) {
+	printer := pp.New()
+	printer.SetOmitEmpty(true)
+	printer.Print(response)
+}
+
+func main() {
+	if err := example(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -91,4 +91,19 @@ func main() {
 	for i, score := range response2.Scores {
 		fmt.Printf("%-5d | %.4f\n", i, score)
 	}
+	fmt.Println()
+
+	// Example 3: Keeping only the most relevant documents
+	fmt.Println("=== Example 3: Top 3 Results with Documents ===")
+
+	response3, err := ai.RankMany(ctx, rankingModel, query, documents, ai.WithTopN(3), ai.WithReturnDocuments())
+	if err != nil {
+		log.Fatalf("Top-N ranking failed: %v", err)
+	}
+
+	fmt.Println("Rank | Index | Score  | Document")
+	fmt.Println("-----|-------|--------|---------")
+	for rank, result := range response3.Results {
+		fmt.Printf("%-4d | %-5d | %.4f | %s\n", rank+1, result.Index, result.Score, result.Document)
+	}
 }
@@ -0,0 +1,406 @@
+package ai
+
+import (
+	"context"
+
+	"go.jetify.com/ai/api"
+)
+
+// GenerateFunc is the signature of [api.LanguageModel.Generate].
+type GenerateFunc func(ctx context.Context, prompt []api.Message, opts api.CallOptions) (*api.Response, error)
+
+// StreamFunc is the signature of [api.LanguageModel.Stream].
+type StreamFunc func(ctx context.Context, prompt []api.Message, opts api.CallOptions) (*api.StreamResponse, error)
+
+// LanguageModelMiddleware intercepts the calls made to a language model.
+// All fields are optional; a nil hook leaves the corresponding value unchanged.
+//
+// Middleware is applied with [WrapLanguageModel].
+type LanguageModelMiddleware struct {
+	// TransformParams is called before both Generate and Stream and may modify
+	// the prompt and call options sent to the model. Returning an error aborts
+	// the call.
+	TransformParams func(
+		ctx context.Context, prompt []api.Message, opts api.CallOptions,
+	) ([]api.Message, api.CallOptions, error)
+
+	// WrapGenerate is called in place of Generate, after TransformParams.
+	// Calling next runs the rest of the chain, so the hook sees both the start
+	// and the end of the call, including its error. It may also return without
+	// calling next, e.g. to serve a cached response.
+	WrapGenerate func(
+		ctx context.Context, prompt []api.Message, opts api.CallOptions, next GenerateFunc,
+	) (*api.Response, error)
+
+	// WrapStream is called in place of Stream, after TransformParams, in the
+	// same way as WrapGenerate. To observe the end of the stream, wrap the
+	// Stream of the response returned by next.
+	WrapStream func(
+		ctx context.Context, prompt []api.Message, opts api.CallOptions, next StreamFunc,
+	) (*api.StreamResponse, error)
+
+	// TransformResponse is called with the response returned by Generate when
+	// it succeeds. Returning an error fails the call. Use WrapGenerate to
+	// observe failed calls.
+	TransformResponse func(ctx context.Context, resp *api.Response) (*api.Response, error)
+
+	// TransformStreamEvent is called for every event returned by Stream.
+	// Returning nil drops the event from the stream.
+	TransformStreamEvent func(ctx context.Context, event api.StreamEvent) api.StreamEvent
+}
+
+// WrapLanguageModel returns a language model that runs the given middleware
+// around every call to model.
+//
+// Middleware is applied in order: the first middleware sees the parameters
+// first and the response last, as if each middleware wrapped the ones after
+// it:
+//
+//	model := WrapLanguageModel(base, logging, redaction)
+//	// logging.TransformParams -> logging.WrapGenerate
+//	// -> redaction.TransformParams -> redaction.WrapGenerate -> base.Generate
+//	// -> redaction.TransformResponse -> logging.TransformResponse
+//
+// The wrapped model reports the same provider name and model ID as model.
+func WrapLanguageModel(model api.LanguageModel, middlewares ...LanguageModelMiddleware) api.LanguageModel {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		model = &wrappedLanguageModel{model: model, middleware: middlewares[i]}
+	}
+	return model
+}
+
+type wrappedLanguageModel struct {
+	model      api.LanguageModel
+	middleware LanguageModelMiddleware
+}
+
+var _ api.LanguageModel = (*wrappedLanguageModel)(nil)
+
+func (m *wrappedLanguageModel) ProviderName() string { return m.model.ProviderName() }
+
+func (m *wrappedLanguageModel) ModelID() string { return m.model.ModelID() }
+
+func (m *wrappedLanguageModel) SupportedUrls() []api.SupportedURL { return m.model.SupportedUrls() }
+
+// DefaultObjectGenerationMode reports the object generation mode of the
+// wrapped model so that GenerateObject behaves the same with or without
+// middleware.
+func (m *wrappedLanguageModel) DefaultObjectGenerationMode() api.ObjectGenerationMode {
+	return objectGenerationMode(m.model)
+}
+
+func (m *wrappedLanguageModel) Generate(
+	ctx context.Context, prompt []api.Message, opts api.CallOptions,
+) (*api.Response, error) {
+	prompt, opts, err := m.transformParams(ctx, prompt, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *api.Response
+	if m.middleware.WrapGenerate != nil {
+		resp, err = m.middleware.WrapGenerate(ctx, prompt, opts, m.model.Generate)
+	} else {
+		resp, err = m.model.Generate(ctx, prompt, opts)
+	}
+	if err != nil || m.middleware.TransformResponse == nil {
+		return resp, err
+	}
+	return m.middleware.TransformResponse(ctx, resp)
+}
+
+func (m *wrappedLanguageModel) Stream(
+	ctx context.Context, prompt []api.Message, opts api.CallOptions,
+) (*api.StreamResponse, error) {
+	prompt, opts, err := m.transformParams(ctx, prompt, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *api.StreamResponse
+	if m.middleware.WrapStream != nil {
+		resp, err = m.middleware.WrapStream(ctx, prompt, opts, m.model.Stream)
+	} else {
+		resp, err = m.model.Stream(ctx, prompt, opts)
+	}
+	if err != nil || resp == nil || m.middleware.TransformStreamEvent == nil {
+		return resp, err
+	}
+
+	transform := m.middleware.TransformStreamEvent
+	stream := resp.Stream
+	wrapped := *resp
+	wrapped.Stream = func(yield func(api.StreamEvent) bool) {
+		for event := range stream {
+			event = transform(ctx, event)
+			if event == nil {
+				continue
+			}
+			if !yield(event) {
+				return
+			}
+		}
+	}
+	return &wrapped, nil
+}
+
+func (m *wrappedLanguageModel) transformParams(
+	ctx context.Context, prompt []api.Message, opts api.CallOptions,
+) ([]api.Message, api.CallOptions, error) {
+	if m.middleware.TransformParams == nil {
+		return prompt, opts, nil
+	}
+	return m.middleware.TransformParams(ctx, prompt, opts)
+}
+
+// EmbeddingModelMiddleware intercepts the calls made to an embedding model.
+// All fields are optional; a nil hook leaves the corresponding value unchanged.
+//
+// Middleware is applied with [WrapEmbeddingModel].
+type EmbeddingModelMiddleware[T api.EmbeddingInput, E api.EmbeddingVector] struct {
+	// TransformParams is called before DoEmbed and may modify the values and
+	// transport options sent to the model. Returning an error aborts the call.
+	TransformParams func(
+		ctx context.Context, values []T, opts api.TransportOptions,
+	) ([]T, api.TransportOptions, error)
+
+	// WrapDoEmbed is called in place of DoEmbed, after TransformParams.
+	// Calling next runs the rest of the chain, so the hook sees both the start
+	// and the end of the call, including its error. It may also return without
+	// calling next, e.g. to serve cached embeddings.
+	WrapDoEmbed func(
+		ctx context.Context, values []T, opts api.TransportOptions, next DoEmbedFunc[T, E],
+	) (api.EmbeddingResponse[E], error)
+
+	// TransformResponse is called with the response returned by DoEmbed when
+	// it succeeds. Returning an error fails the call.
+	TransformResponse func(
+		ctx context.Context, resp api.EmbeddingResponse[E],
+	) (api.EmbeddingResponse[E], error)
+}
+
+// DoEmbedFunc is the signature of [api.EmbeddingModel.DoEmbed].
+type DoEmbedFunc[T api.EmbeddingInput, E api.EmbeddingVector] func(
+	ctx context.Context, values []T, opts api.TransportOptions,
+) (api.EmbeddingResponse[E], error)
+
+// WrapEmbeddingModel returns an embedding model that runs the given middleware
+// around every call to model. Middleware is applied in the same order as in
+// [WrapLanguageModel].
+//
+// The wrapped model reports the same provider name, model ID, batch limit and
+// parallelism as model.
+func WrapEmbeddingModel[T api.EmbeddingInput, E api.EmbeddingVector](
+	model api.EmbeddingModel[T, E], middlewares ...EmbeddingModelMiddleware[T, E],
+) api.EmbeddingModel[T, E] {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		model = &wrappedEmbeddingModel[T, E]{model: model, middleware: middlewares[i]}
+	}
+	return model
+}
+
+type wrappedEmbeddingModel[T api.EmbeddingInput, E api.EmbeddingVector] struct {
+	model      api.EmbeddingModel[T, E]
+	middleware EmbeddingModelMiddleware[T, E]
+}
+
+func (m *wrappedEmbeddingModel[T, E]) SpecificationVersion() string {
+	return m.model.SpecificationVersion()
+}
+
+func (m *wrappedEmbeddingModel[T, E]) ProviderName() string { return m.model.ProviderName() }
+
+func (m *wrappedEmbeddingModel[T, E]) ModelID() string { return m.model.ModelID() }
+
+func (m *wrappedEmbeddingModel[T, E]) MaxEmbeddingsPerCall() *int {
+	return m.model.MaxEmbeddingsPerCall()
+}
+
+func (m *wrappedEmbeddingModel[T, E]) SupportsParallelCalls() bool {
+	return m.model.SupportsParallelCalls()
+}
+
+func (m *wrappedEmbeddingModel[T, E]) DoEmbed(
+	ctx context.Context, values []T, opts api.TransportOptions,
+) (api.EmbeddingResponse[E], error) {
+	if m.middleware.TransformParams != nil {
+		var err error
+		values, opts, err = m.middleware.TransformParams(ctx, values, opts)
+		if err != nil {
+			return api.EmbeddingResponse[E]{}, err
+		}
+	}
+
+	var (
+		resp api.EmbeddingResponse[E]
+		err  error
+	)
+	if m.middleware.WrapDoEmbed != nil {
+		resp, err = m.middleware.WrapDoEmbed(ctx, values, opts, m.model.DoEmbed)
+	} else {
+		resp, err = m.model.DoEmbed(ctx, values, opts)
+	}
+	if err != nil || m.middleware.TransformResponse == nil {
+		return resp, err
+	}
+	return m.middleware.TransformResponse(ctx, resp)
+}
+
+// RankingModelMiddleware intercepts the calls made to a ranking model.
+// All fields are optional; a nil hook leaves the corresponding value unchanged.
+//
+// Middleware is applied with [WrapRankingModel].
+type RankingModelMiddleware struct {
+	// TransformParams is called before DoRank and may modify the query, texts
+	// and transport options sent to the model. Returning an error aborts the
+	// call.
+	TransformParams func(
+		ctx context.Context, query string, texts []string, opts api.TransportOptions,
+	) (string, []string, api.TransportOptions, error)
+
+	// WrapDoRank is called in place of DoRank, after TransformParams, in the
+	// same way as [EmbeddingModelMiddleware.WrapDoEmbed].
+	WrapDoRank func(
+		ctx context.Context, query string, texts []string, opts api.TransportOptions, next DoRankFunc,
+	) (api.RankingResponse, error)
+
+	// TransformResponse is called with the response returned by DoRank when
+	// it succeeds. Returning an error fails the call.
+	TransformResponse func(ctx context.Context, resp api.RankingResponse) (api.RankingResponse, error)
+}
+
+// DoRankFunc is the signature of [api.RankingModel.DoRank].
+type DoRankFunc func(
+	ctx context.Context, query string, texts []string, opts api.TransportOptions,
+) (api.RankingResponse, error)
+
+// WrapRankingModel returns a ranking model that runs the given middleware
+// around every call to model. Middleware is applied in the same order as in
+// [WrapLanguageModel].
+func WrapRankingModel(model api.RankingModel, middlewares ...RankingModelMiddleware) api.RankingModel {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		model = &wrappedRankingModel{model: model, middleware: middlewares[i]}
+	}
+	return model
+}
+
+type wrappedRankingModel struct {
+	model      api.RankingModel
+	middleware RankingModelMiddleware
+}
+
+var _ api.RankingModel = (*wrappedRankingModel)(nil)
+
+func (m *wrappedRankingModel) SpecificationVersion() string { return m.model.SpecificationVersion() }
+
+func (m *wrappedRankingModel) ProviderName() string { return m.model.ProviderName() }
+
+func (m *wrappedRankingModel) ModelID() string { return m.model.ModelID() }
+
+func (m *wrappedRankingModel) SupportsParallelCalls() bool { return m.model.SupportsParallelCalls() }
+
+func (m *wrappedRankingModel) DoRank(
+	ctx context.Context, query string, texts []string, opts api.TransportOptions,
+) (api.RankingResponse, error) {
+	if m.middleware.TransformParams != nil {
+		var err error
+		query, texts, opts, err = m.middleware.TransformParams(ctx, query, texts, opts)
+		if err != nil {
+			return api.RankingResponse{}, err
+		}
+	}
+
+	var (
+		resp api.RankingResponse
+		err  error
+	)
+	if m.middleware.WrapDoRank != nil {
+		resp, err = m.middleware.WrapDoRank(ctx, query, texts, opts, m.model.DoRank)
+	} else {
+		resp, err = m.model.DoRank(ctx, query, texts, opts)
+	}
+	if err != nil || m.middleware.TransformResponse == nil {
+		return resp, err
+	}
+	return m.middleware.TransformResponse(ctx, resp)
+}
+
+// SegmentingModelMiddleware intercepts the calls made to a segmenting model.
+// All fields are optional; a nil hook leaves the corresponding value unchanged.
+//
+// Middleware is applied with [WrapSegmentingModel].
+type SegmentingModelMiddleware struct {
+	// TransformParams is called before DoSegment and may modify the texts and
+	// transport options sent to the model. Returning an error aborts the call.
+	TransformParams func(
+		ctx context.Context, texts []string, opts api.TransportOptions,
+	) ([]string, api.TransportOptions, error)
+
+	// WrapDoSegment is called in place of DoSegment, after TransformParams, in
+	// the same way as [EmbeddingModelMiddleware.WrapDoEmbed].
+	WrapDoSegment func(
+		ctx context.Context, texts []string, opts api.TransportOptions, next DoSegmentFunc,
+	) (api.SegmentingResponse, error)
+
+	// TransformResponse is called with the response returned by DoSegment
+	// when it succeeds. Returning an error fails the call.
+	TransformResponse func(ctx context.Context, resp api.SegmentingResponse) (api.SegmentingResponse, error)
+}
+
+// DoSegmentFunc is the signature of [api.SegmentingModel.DoSegment].
+type DoSegmentFunc func(
+	ctx context.Context, texts []string, opts api.TransportOptions,
+) (api.SegmentingResponse, error)
+
+// WrapSegmentingModel returns a segmenting model that runs the given
+// middleware around every call to model. Middleware is applied in the same
+// order as in [WrapLanguageModel].
+func WrapSegmentingModel(model api.SegmentingModel, middlewares ...SegmentingModelMiddleware) api.SegmentingModel {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		model = &wrappedSegmentingModel{model: model, middleware: middlewares[i]}
+	}
+	return model
+}
+
+type wrappedSegmentingModel struct {
+	model      api.SegmentingModel
+	middleware SegmentingModelMiddleware
+}
+
+var _ api.SegmentingModel = (*wrappedSegmentingModel)(nil)
+
+func (m *wrappedSegmentingModel) SpecificationVersion() string { return m.model.SpecificationVersion() }
+
+func (m *wrappedSegmentingModel) ProviderName() string { return m.model.ProviderName() }
+
+func (m *wrappedSegmentingModel) ModelID() string { return m.model.ModelID() }
+
+func (m *wrappedSegmentingModel) SupportsParallelCalls() bool {
+	return m.model.SupportsParallelCalls()
+}
+
+func (m *wrappedSegmentingModel) DoSegment(
+	ctx context.Context, texts []string, opts api.TransportOptions,
+) (api.SegmentingResponse, error) {
+	if m.middleware.TransformParams != nil {
+		var err error
+		texts, opts, err = m.middleware.TransformParams(ctx, texts, opts)
+		if err != nil {
+			return api.SegmentingResponse{}, err
+		}
+	}
+
+	var (
+		resp api.SegmentingResponse
+		err  error
+	)
+	if m.middleware.WrapDoSegment != nil {
+		resp, err = m.middleware.WrapDoSegment(ctx, texts, opts, m.model.DoSegment)
+	} else {
+		resp, err = m.model.DoSegment(ctx, texts, opts)
+	}
+	if err != nil || m.middleware.TransformResponse == nil {
+		return resp, err
+	}
+	return m.middleware.TransformResponse(ctx, resp)
+}
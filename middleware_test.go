@@ -0,0 +1,420 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+)
+
+// systemPromptMiddleware prepends a system message to every prompt and
+// records name in trace when its hooks run.
+func systemPromptMiddleware(name string, trace *[]string) LanguageModelMiddleware {
+	return LanguageModelMiddleware{
+		TransformParams: func(
+			ctx context.Context, prompt []api.Message, opts api.CallOptions,
+		) ([]api.Message, api.CallOptions, error) {
+			*trace = append(*trace, name+".params")
+			prompt = append([]api.Message{&api.SystemMessage{Content: name}}, prompt...)
+			return prompt, opts, nil
+		},
+		TransformResponse: func(ctx context.Context, resp *api.Response) (*api.Response, error) {
+			*trace = append(*trace, name+".response")
+			return resp, nil
+		},
+	}
+}
+
+func TestWrapLanguageModel_Generate(t *testing.T) {
+	var trace []string
+	model := &scriptedLanguageModel{responses: []*api.Response{textResponse("Hello")}}
+	wrapped := WrapLanguageModel(model,
+		systemPromptMiddleware("outer", &trace),
+		systemPromptMiddleware("inner", &trace),
+	)
+
+	resp, err := GenerateTextStr(t.Context(), "Hi", WithModel(wrapped))
+	require.NoError(t, err)
+	assert.Equal(t, []api.ContentBlock{&api.TextBlock{Text: "Hello"}}, resp.Content)
+
+	assert.Equal(t, []string{"outer.params", "inner.params", "inner.response", "outer.response"}, trace)
+	require.Len(t, model.prompts, 1)
+	assert.Equal(t, []api.Message{
+		&api.SystemMessage{Content: "inner"},
+		&api.SystemMessage{Content: "outer"},
+		&api.UserMessage{Content: []api.ContentBlock{&api.TextBlock{Text: "Hi"}}},
+	}, model.prompts[0])
+}
+
+func TestWrapLanguageModel_TransformOptions(t *testing.T) {
+	model := &scriptedLanguageModel{responses: []*api.Response{textResponse("Hello")}}
+	wrapped := WrapLanguageModel(model, LanguageModelMiddleware{
+		TransformParams: func(
+			ctx context.Context, prompt []api.Message, opts api.CallOptions,
+		) ([]api.Message, api.CallOptions, error) {
+			opts.MaxOutputTokens = 100
+			return prompt, opts, nil
+		},
+	})
+
+	_, err := GenerateTextStr(t.Context(), "Hi", WithModel(wrapped))
+	require.NoError(t, err)
+	require.Len(t, model.options, 1)
+	assert.Equal(t, 100, model.options[0].MaxOutputTokens)
+}
+
+func TestWrapLanguageModel_Errors(t *testing.T) {
+	blocked := errors.New("blocked")
+
+	t.Run("params error skips the model", func(t *testing.T) {
+		model := &scriptedLanguageModel{responses: []*api.Response{textResponse("Hello")}}
+		wrapped := WrapLanguageModel(model, LanguageModelMiddleware{
+			TransformParams: func(
+				ctx context.Context, prompt []api.Message, opts api.CallOptions,
+			) ([]api.Message, api.CallOptions, error) {
+				return nil, opts, blocked
+			},
+		})
+
+		_, err := wrapped.Generate(t.Context(), nil, api.CallOptions{})
+		assert.ErrorIs(t, err, blocked)
+		assert.Empty(t, model.prompts)
+
+		_, err = wrapped.Stream(t.Context(), nil, api.CallOptions{})
+		assert.ErrorIs(t, err, blocked)
+	})
+
+	t.Run("response error", func(t *testing.T) {
+		model := &scriptedLanguageModel{responses: []*api.Response{textResponse("Hello")}}
+		wrapped := WrapLanguageModel(model, LanguageModelMiddleware{
+			TransformResponse: func(ctx context.Context, resp *api.Response) (*api.Response, error) {
+				return nil, blocked
+			},
+		})
+
+		_, err := wrapped.Generate(t.Context(), nil, api.CallOptions{})
+		assert.ErrorIs(t, err, blocked)
+	})
+}
+
+func TestWrapLanguageModel_Stream(t *testing.T) {
+	model := &streamingLanguageModel{streams: [][]api.StreamEvent{{
+		&api.TextDeltaEvent{TextDelta: "Hello"},
+		&api.TextDeltaEvent{TextDelta: " secret"},
+		&api.FinishEvent{FinishReason: api.FinishReasonStop},
+	}}}
+	wrapped := WrapLanguageModel(model,
+		LanguageModelMiddleware{
+			TransformStreamEvent: func(ctx context.Context, event api.StreamEvent) api.StreamEvent {
+				if delta, ok := event.(*api.TextDeltaEvent); ok {
+					return &api.TextDeltaEvent{TextDelta: delta.TextDelta + "!"}
+				}
+				return event
+			},
+		},
+		LanguageModelMiddleware{
+			TransformStreamEvent: func(ctx context.Context, event api.StreamEvent) api.StreamEvent {
+				if delta, ok := event.(*api.TextDeltaEvent); ok && delta.TextDelta == " secret" {
+					return nil
+				}
+				return event
+			},
+		},
+	)
+
+	resp, err := StreamTextStr(t.Context(), "Hi", WithModel(wrapped))
+	require.NoError(t, err)
+
+	var events []api.StreamEvent
+	for event := range resp.Stream {
+		events = append(events, event)
+	}
+	assert.Equal(t, []api.StreamEvent{
+		&api.TextDeltaEvent{TextDelta: "Hello!"},
+		&api.FinishEvent{FinishReason: api.FinishReasonStop},
+	}, events)
+}
+
+func TestWrapLanguageModel_WrapGenerate(t *testing.T) {
+	t.Run("sees errors", func(t *testing.T) {
+		var logged error
+		model := &scriptedLanguageModel{}
+		wrapped := WrapLanguageModel(model, LanguageModelMiddleware{
+			WrapGenerate: func(
+				ctx context.Context, prompt []api.Message, opts api.CallOptions, next GenerateFunc,
+			) (*api.Response, error) {
+				resp, err := next(ctx, prompt, opts)
+				logged = err
+				return resp, err
+			},
+		})
+
+		_, err := wrapped.Generate(t.Context(), nil, api.CallOptions{})
+		require.EqualError(t, err, "unexpected call")
+		assert.Equal(t, err, logged)
+	})
+
+	t.Run("skips the model", func(t *testing.T) {
+		cached := textResponse("cached")
+		model := &scriptedLanguageModel{}
+		wrapped := WrapLanguageModel(model, LanguageModelMiddleware{
+			WrapGenerate: func(
+				ctx context.Context, prompt []api.Message, opts api.CallOptions, next GenerateFunc,
+			) (*api.Response, error) {
+				return cached, nil
+			},
+		})
+
+		resp, err := wrapped.Generate(t.Context(), nil, api.CallOptions{})
+		require.NoError(t, err)
+		assert.Same(t, cached, resp)
+		assert.Empty(t, model.prompts)
+	})
+}
+
+func TestWrapLanguageModel_WrapStream(t *testing.T) {
+	streamErr := apiCallError(500)
+	model := &streamingLanguageModel{streams: [][]api.StreamEvent{{
+		&api.TextDeltaEvent{TextDelta: "Hel"},
+		&api.ErrorEvent{Err: streamErr},
+	}}}
+
+	var trace []string
+	wrapped := WrapLanguageModel(model, LanguageModelMiddleware{
+		WrapStream: func(
+			ctx context.Context, prompt []api.Message, opts api.CallOptions, next StreamFunc,
+		) (*api.StreamResponse, error) {
+			trace = append(trace, "start")
+			resp, err := next(ctx, prompt, opts)
+			if err != nil {
+				return nil, err
+			}
+			stream := resp.Stream
+			resp.Stream = func(yield func(api.StreamEvent) bool) {
+				defer func() { trace = append(trace, "end") }()
+				for event := range stream {
+					if errEvent, ok := event.(*api.ErrorEvent); ok {
+						trace = append(trace, "error: "+errEvent.Error())
+					}
+					if !yield(event) {
+						return
+					}
+				}
+			}
+			return resp, nil
+		},
+	})
+
+	resp, err := wrapped.Stream(t.Context(), nil, api.CallOptions{})
+	require.NoError(t, err)
+	for range resp.Stream {
+	}
+	assert.Equal(t, []string{"start", "error: Internal Server Error", "end"}, trace)
+}
+
+func TestWrapLanguageModel_Metadata(t *testing.T) {
+	model := &toolModeLanguageModel{}
+	model.name = "test-model"
+	wrapped := WrapLanguageModel(model, LanguageModelMiddleware{})
+
+	assert.Equal(t, "mock-provider", wrapped.ProviderName())
+	assert.Equal(t, "test-model", wrapped.ModelID())
+	assert.Equal(t, api.ObjectGenerationModeTool, objectGenerationMode(wrapped))
+	assert.Same(t, model, WrapLanguageModel(model))
+}
+
+func TestWrapEmbeddingModel(t *testing.T) {
+	model := &fakeEmbeddingModel{maxPerCall: intPtr(2), parallel: true}
+	wrapped := WrapEmbeddingModel(model,
+		EmbeddingModelMiddleware[string, api.Embedding]{
+			TransformParams: func(
+				ctx context.Context, values []string, opts api.TransportOptions,
+			) ([]string, api.TransportOptions, error) {
+				trimmed := make([]string, len(values))
+				for i, v := range values {
+					trimmed[i] = strings.TrimSpace(v)
+				}
+				return trimmed, opts, nil
+			},
+		},
+		EmbeddingModelMiddleware[string, api.Embedding]{
+			TransformResponse: func(
+				ctx context.Context, resp api.EmbeddingResponse[api.Embedding],
+			) (api.EmbeddingResponse[api.Embedding], error) {
+				for i, e := range resp.Embeddings {
+					resp.Embeddings[i] = append(e, 0)
+				}
+				return resp, nil
+			},
+		},
+	)
+
+	assert.Equal(t, "fake", wrapped.ProviderName())
+	assert.Equal(t, "fake-embedding", wrapped.ModelID())
+	assert.Equal(t, intPtr(2), wrapped.MaxEmbeddingsPerCall())
+	assert.True(t, wrapped.SupportsParallelCalls())
+
+	resp, err := EmbedMany(t.Context(), wrapped, []string{" a ", "bb ", " ccc"})
+	require.NoError(t, err)
+	assert.Equal(t, []api.Embedding{{1, 0}, {2, 0}, {3, 0}}, resp.Embeddings)
+	assert.ElementsMatch(t, [][]string{{"a", "bb"}, {"ccc"}}, model.calls)
+}
+
+func TestWrapEmbeddingModel_WrapDoEmbed(t *testing.T) {
+	cache := map[string]api.Embedding{"a": {42}}
+	model := &fakeEmbeddingModel{}
+	wrapped := WrapEmbeddingModel(model, EmbeddingModelMiddleware[string, api.Embedding]{
+		WrapDoEmbed: func(
+			ctx context.Context, values []string, opts api.TransportOptions,
+			next DoEmbedFunc[string, api.Embedding],
+		) (api.EmbeddingResponse[api.Embedding], error) {
+			embeddings := make([]api.Embedding, 0, len(values))
+			for _, v := range values {
+				embedding, ok := cache[v]
+				if !ok {
+					return next(ctx, values, opts)
+				}
+				embeddings = append(embeddings, embedding)
+			}
+			return api.EmbeddingResponse[api.Embedding]{Embeddings: embeddings}, nil
+		},
+	})
+
+	resp, err := wrapped.DoEmbed(t.Context(), []string{"a"}, api.TransportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []api.Embedding{{42}}, resp.Embeddings)
+	assert.Empty(t, model.calls)
+
+	resp, err = wrapped.DoEmbed(t.Context(), []string{"a", "bb"}, api.TransportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []api.Embedding{{1}, {2}}, resp.Embeddings)
+	assert.Len(t, model.calls, 1)
+}
+
+func TestWrapRankingModel_WrapDoRank(t *testing.T) {
+	var errs []error
+	wrapped := WrapRankingModel(&flakyRankingModel{failures: 1}, RankingModelMiddleware{
+		WrapDoRank: func(
+			ctx context.Context, query string, texts []string, opts api.TransportOptions, next DoRankFunc,
+		) (api.RankingResponse, error) {
+			resp, err := next(ctx, query, texts, opts)
+			errs = append(errs, err)
+			return resp, err
+		},
+	})
+
+	_, err := RankMany(t.Context(), wrapped, "query", []string{"a"}, WithTransportRetryPolicy(fastRetryPolicy))
+	require.NoError(t, err)
+	require.Len(t, errs, 2)
+	assert.Equal(t, apiCallError(500), errs[0])
+	assert.NoError(t, errs[1])
+}
+
+func TestWrapRankingModel(t *testing.T) {
+	model := &flakyRankingModel{}
+	var query string
+	wrapped := WrapRankingModel(model, RankingModelMiddleware{
+		TransformParams: func(
+			ctx context.Context, q string, texts []string, opts api.TransportOptions,
+		) (string, []string, api.TransportOptions, error) {
+			query = strings.ToLower(q)
+			return query, texts, opts, nil
+		},
+		TransformResponse: func(ctx context.Context, resp api.RankingResponse) (api.RankingResponse, error) {
+			resp.RequestID = "tagged"
+			return resp, nil
+		},
+	})
+
+	assert.Equal(t, "fake-ranker", wrapped.ModelID())
+	assert.False(t, wrapped.SupportsParallelCalls())
+
+	resp, err := RankMany(t.Context(), wrapped, "QUERY", []string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, "query", query)
+	assert.Equal(t, api.RankingResponse{
+		Scores:    []float64{1},
+		Results:   []api.RankingResult{{Index: 0, Score: 1}},
+		RequestID: "tagged",
+	}, resp)
+}
+
+// fakeSegmentingModel splits every text into one segment per word.
+type fakeSegmentingModel struct{}
+
+func (m *fakeSegmentingModel) SpecificationVersion() string { return "v1" }
+func (m *fakeSegmentingModel) ProviderName() string         { return "fake" }
+func (m *fakeSegmentingModel) ModelID() string              { return "fake-segmenter" }
+func (m *fakeSegmentingModel) SupportsParallelCalls() bool  { return true }
+
+func (m *fakeSegmentingModel) DoSegment(
+	ctx context.Context, texts []string, opts api.TransportOptions,
+) (api.SegmentingResponse, error) {
+	segments := make([][]api.Segment, len(texts))
+	for i, text := range texts {
+		for _, word := range strings.Fields(text) {
+			segments[i] = append(segments[i], api.Segment{Text: word})
+		}
+	}
+	return api.SegmentingResponse{Segments: segments}, nil
+}
+
+func TestWrapSegmentingModel(t *testing.T) {
+	blocked := errors.New("blocked")
+	wrapped := WrapSegmentingModel(&fakeSegmentingModel{}, SegmentingModelMiddleware{
+		TransformParams: func(
+			ctx context.Context, texts []string, opts api.TransportOptions,
+		) ([]string, api.TransportOptions, error) {
+			if len(texts) == 0 {
+				return nil, opts, blocked
+			}
+			return texts, opts, nil
+		},
+		TransformResponse: func(ctx context.Context, resp api.SegmentingResponse) (api.SegmentingResponse, error) {
+			for _, segments := range resp.Segments {
+				for i := range segments {
+					segments[i].TokenCount = 1
+				}
+			}
+			return resp, nil
+		},
+	})
+
+	assert.Equal(t, "fake-segmenter", wrapped.ModelID())
+	assert.True(t, wrapped.SupportsParallelCalls())
+
+	resp, err := wrapped.DoSegment(t.Context(), []string{"hello world"}, api.TransportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, [][]api.Segment{{{Text: "hello", TokenCount: 1}, {Text: "world", TokenCount: 1}}}, resp.Segments)
+
+	_, err = wrapped.DoSegment(t.Context(), nil, api.TransportOptions{})
+	assert.ErrorIs(t, err, blocked)
+}
+
+func TestWrapSegmentingModel_WrapDoSegment(t *testing.T) {
+	var calls int
+	wrapped := WrapSegmentingModel(&fakeSegmentingModel{}, SegmentingModelMiddleware{
+		WrapDoSegment: func(
+			ctx context.Context, texts []string, opts api.TransportOptions, next DoSegmentFunc,
+		) (api.SegmentingResponse, error) {
+			calls++
+			if len(texts) == 0 {
+				return api.SegmentingResponse{}, nil
+			}
+			return next(ctx, texts, opts)
+		},
+	})
+
+	resp, err := wrapped.DoSegment(t.Context(), nil, api.TransportOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Segments)
+
+	resp, err = wrapped.DoSegment(t.Context(), []string{"hello world"}, api.TransportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, [][]api.Segment{{{Text: "hello"}, {Text: "world"}}}, resp.Segments)
+	assert.Equal(t, 2, calls)
+}
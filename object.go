@@ -0,0 +1,153 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"go.jetify.com/ai/api"
+)
+
+// objectToolName is the name of the tool used to generate objects in tool mode.
+const objectToolName = "json"
+
+// objectGenerationModeProvider is implemented by language models that prefer a
+// specific mode for structured output generation.
+type objectGenerationModeProvider interface {
+	DefaultObjectGenerationMode() api.ObjectGenerationMode
+}
+
+// GenerateObject uses a language model to generate a structured object of
+// type T from a given prompt.
+//
+// A JSON schema is derived from T and sent to the model, either as the
+// response format (JSON mode) or as the input schema of a tool the model is
+// forced to call (tool mode). The mode is chosen from the model's
+// DefaultObjectGenerationMode, if it has one, and defaults to JSON mode.
+//
+// The generated JSON is validated against the schema and decoded into T:
+//
+//	type Recipe struct {
+//		Name        string   `json:"name"`
+//		Ingredients []string `json:"ingredients"`
+//	}
+//
+//	recipe, err := GenerateObject[Recipe](ctx, messages)
+//
+// If the output does not match the schema, the returned error is an
+// [api.TypeValidationError] whose Value holds the raw text generated by the
+// model.
+func GenerateObject[T any](ctx context.Context, prompt []api.Message, opts ...GenerateOption) (*T, error) {
+	config := buildGenerateConfig(opts)
+	schema, err := jsonschema.For[T](nil)
+	if err != nil {
+		return nil, api.NewInvalidArgumentError("cannot derive a JSON schema for the object type", "T", err)
+	}
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		return nil, api.NewInvalidArgumentError("cannot resolve the JSON schema for the object type", "T", err)
+	}
+
+	mode := objectGenerationMode(config.Model)
+	applyObjectMode(&config.CallOptions, mode, schema)
+
+	resp, err := generateOnce(ctx, prompt, config)
+	if err != nil {
+		return nil, err
+	}
+
+	text, ok := objectText(resp, mode)
+	if !ok {
+		return nil, api.NewNoContentGeneratedError("No object generated: the model did not return any output.")
+	}
+	return parseObject[T](text, resolved)
+}
+
+// GenerateObjectStr is a convenience wrapper around GenerateObject for simple
+// string-based prompts.
+func GenerateObjectStr[T any](ctx context.Context, prompt string, opts ...GenerateOption) (*T, error) {
+	msg := &api.UserMessage{
+		Content: []api.ContentBlock{&api.TextBlock{Text: prompt}},
+	}
+	return GenerateObject[T](ctx, []api.Message{msg}, opts...)
+}
+
+// objectGenerationMode returns the model's preferred object generation mode,
+// defaulting to JSON mode.
+func objectGenerationMode(model api.LanguageModel) api.ObjectGenerationMode {
+	if m, ok := model.(objectGenerationModeProvider); ok {
+		if mode := m.DefaultObjectGenerationMode(); mode != api.ObjectGenerationModeNone {
+			return mode
+		}
+	}
+	return api.ObjectGenerationModeJSON
+}
+
+// applyObjectMode configures the call options so that the model generates an
+// object matching schema using the given mode. The name and description of a
+// response format set by the caller are preserved.
+func applyObjectMode(opts *api.CallOptions, mode api.ObjectGenerationMode, schema *jsonschema.Schema) {
+	format := &api.ResponseFormat{Type: "json", Schema: schema, Name: "response"}
+	if opts.ResponseFormat != nil {
+		if opts.ResponseFormat.Name != "" {
+			format.Name = opts.ResponseFormat.Name
+		}
+		format.Description = opts.ResponseFormat.Description
+	}
+
+	if mode == api.ObjectGenerationModeTool {
+		description := format.Description
+		if description == "" {
+			description = "Respond with a JSON object."
+		}
+		opts.ResponseFormat = nil
+		opts.Tools = []api.ToolDefinition{&api.FunctionTool{
+			Name:        objectToolName,
+			Description: description,
+			InputSchema: schema,
+		}}
+		opts.ToolChoice = &api.ToolChoice{Type: "tool", ToolName: objectToolName}
+		return
+	}
+	opts.ResponseFormat = format
+}
+
+// objectText extracts the generated JSON from a response: the text content in
+// JSON mode, or the arguments of the object tool call in tool mode.
+func objectText(resp *api.Response, mode api.ObjectGenerationMode) (string, bool) {
+	if mode == api.ObjectGenerationModeTool {
+		for _, block := range resp.Content {
+			if call, ok := block.(*api.ToolCallBlock); ok && call.ToolName == objectToolName {
+				return string(call.Args), true
+			}
+		}
+		return "", false
+	}
+
+	var sb strings.Builder
+	for _, block := range resp.Content {
+		if text, ok := block.(*api.TextBlock); ok {
+			sb.WriteString(text.Text)
+		}
+	}
+	return sb.String(), sb.Len() > 0
+}
+
+// parseObject parses text as JSON, validates it against the schema and
+// decodes it into T.
+func parseObject[T any](text string, schema *jsonschema.Resolved) (*T, error) {
+	var instance any
+	if err := json.Unmarshal([]byte(text), &instance); err != nil {
+		return nil, api.NewTypeValidationError(text, api.NewJSONParseError(text, err))
+	}
+	if err := schema.Validate(instance); err != nil {
+		return nil, api.NewTypeValidationError(text, err)
+	}
+
+	var object T
+	if err := json.Unmarshal([]byte(text), &object); err != nil {
+		return nil, api.NewTypeValidationError(text, err)
+	}
+	return &object, nil
+}
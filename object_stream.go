@@ -0,0 +1,175 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"go.jetify.com/ai/api"
+)
+
+// ObjectStreamResponse represents the result of a streaming object generation.
+type ObjectStreamResponse[T any] struct {
+	// Partials yields progressively more complete snapshots of the object while
+	// the model is streaming. Snapshots are decoded from the JSON generated so
+	// far and are not validated against the schema, so fields may be missing
+	// or incomplete. A new snapshot is only yielded when the JSON changes.
+	//
+	// Partials can only be iterated once.
+	Partials iter.Seq[*T]
+
+	// RequestInfo is optional request information for telemetry and debugging purposes.
+	RequestInfo *api.RequestInfo
+
+	// ResponseInfo is optional response information for telemetry and debugging purposes.
+	ResponseInfo *api.ResponseInfo
+
+	stream   iter.Seq[api.StreamEvent]
+	mode     api.ObjectGenerationMode
+	schema   *jsonschema.Resolved
+	text     strings.Builder
+	consumed bool
+	err      error
+}
+
+// StreamObject uses a language model to generate a structured object of type
+// T, streaming partial snapshots of the object as they are generated.
+//
+// The schema and generation mode are chosen as in [GenerateObject]:
+//
+//	resp, err := StreamObject[Recipe](ctx, messages)
+//	if err != nil {
+//		return err
+//	}
+//	for partial := range resp.Partials {
+//		render(partial)
+//	}
+//	recipe, err := resp.Object()
+//
+// Once the stream has been consumed, [ObjectStreamResponse.Object] returns the
+// final object validated against the schema.
+func StreamObject[T any](
+	ctx context.Context, prompt []api.Message, opts ...GenerateOption,
+) (*ObjectStreamResponse[T], error) {
+	config := buildGenerateConfig(opts)
+	schema, err := jsonschema.For[T](nil)
+	if err != nil {
+		return nil, api.NewInvalidArgumentError("cannot derive a JSON schema for the object type", "T", err)
+	}
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		return nil, api.NewInvalidArgumentError("cannot resolve the JSON schema for the object type", "T", err)
+	}
+
+	mode := objectGenerationMode(config.Model)
+	applyObjectMode(&config.CallOptions, mode, schema)
+
+	resp, err := stream(ctx, prompt, config)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ObjectStreamResponse[T]{
+		RequestInfo:  resp.RequestInfo,
+		ResponseInfo: resp.ResponseInfo,
+		stream:       resp.Stream,
+		mode:         mode,
+		schema:       resolved,
+	}
+	result.Partials = result.partials
+	return result, nil
+}
+
+// StreamObjectStr is a convenience wrapper around StreamObject for simple
+// string-based prompts.
+func StreamObjectStr[T any](
+	ctx context.Context, prompt string, opts ...GenerateOption,
+) (*ObjectStreamResponse[T], error) {
+	msg := &api.UserMessage{
+		Content: []api.ContentBlock{&api.TextBlock{Text: prompt}},
+	}
+	return StreamObject[T](ctx, []api.Message{msg}, opts...)
+}
+
+// Object returns the final object, validated against the schema. If Partials
+// has not been consumed yet, Object consumes it first. If iteration over
+// Partials was stopped early, only the text received up to that point is
+// validated.
+//
+// The returned error is the stream error if the stream failed, or an
+// [api.TypeValidationError] if the generated JSON does not match the schema.
+func (r *ObjectStreamResponse[T]) Object() (*T, error) {
+	if !r.consumed {
+		for range r.Partials {
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.text.Len() == 0 {
+		return nil, api.NewNoContentGeneratedError("No object generated: the model did not return any output.")
+	}
+	return parseObject[T](r.text.String(), r.schema)
+}
+
+// Text returns the raw JSON text received so far.
+func (r *ObjectStreamResponse[T]) Text() string {
+	return r.text.String()
+}
+
+func (r *ObjectStreamResponse[T]) partials(yield func(*T) bool) {
+	if r.consumed {
+		return
+	}
+	r.consumed = true
+
+	var last string
+	for event := range r.stream {
+		switch event := event.(type) {
+		case *api.ErrorEvent:
+			r.err = streamError(event)
+			return
+		case *api.TextDeltaEvent:
+			if r.mode == api.ObjectGenerationModeTool {
+				continue
+			}
+			r.text.WriteString(event.TextDelta)
+		case *api.ToolCallDeltaEvent:
+			if r.mode != api.ObjectGenerationModeTool || event.ToolName != objectToolName {
+				continue
+			}
+			r.text.Write(event.ArgsDelta)
+		case *api.ToolCallEvent:
+			if r.mode != api.ObjectGenerationModeTool || event.ToolName != objectToolName {
+				continue
+			}
+			r.text.Reset()
+			r.text.Write(event.Args)
+		default:
+			continue
+		}
+
+		repaired, ok := repairPartialJSON(r.text.String())
+		if !ok || repaired == last {
+			continue
+		}
+		var partial T
+		if err := json.Unmarshal([]byte(repaired), &partial); err != nil {
+			continue
+		}
+		last = repaired
+		if !yield(&partial) {
+			return
+		}
+	}
+}
+
+// streamError returns the error of an error event.
+func streamError(event *api.ErrorEvent) error {
+	if event.Err != nil {
+		return event.Err
+	}
+	return event
+}
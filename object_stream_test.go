@@ -0,0 +1,120 @@
+package ai
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+)
+
+// toolModeStreamingLanguageModel is a streaming model that prefers tool mode
+// for object generation.
+type toolModeStreamingLanguageModel struct {
+	streamingLanguageModel
+}
+
+func (m *toolModeStreamingLanguageModel) DefaultObjectGenerationMode() api.ObjectGenerationMode {
+	return api.ObjectGenerationModeTool
+}
+
+func textDeltas(deltas ...string) []api.StreamEvent {
+	events := make([]api.StreamEvent, 0, len(deltas)+1)
+	for _, delta := range deltas {
+		events = append(events, &api.TextDeltaEvent{TextDelta: delta})
+	}
+	return append(events, &api.FinishEvent{FinishReason: api.FinishReasonStop})
+}
+
+func TestStreamObject_JSONMode(t *testing.T) {
+	model := &streamingLanguageModel{streams: [][]api.StreamEvent{
+		textDeltas(`{"name": "Pan`, `cakes", "ingre`, `dients": ["flour", `, `"eggs"]`, `}`),
+	}}
+
+	resp, err := StreamObjectStr[recipe](t.Context(), "Give me a recipe", WithModel(model))
+	require.NoError(t, err)
+
+	var partials []*recipe
+	for partial := range resp.Partials {
+		partials = append(partials, partial)
+	}
+	assert.Equal(t, []*recipe{
+		{Name: "Pan"},
+		{Name: "Pancakes"},
+		{Name: "Pancakes", Ingredients: []string{"flour"}},
+		{Name: "Pancakes", Ingredients: []string{"flour", "eggs"}},
+	}, partials)
+
+	object, err := resp.Object()
+	require.NoError(t, err)
+	assert.Equal(t, &recipe{Name: "Pancakes", Ingredients: []string{"flour", "eggs"}}, object)
+}
+
+func TestStreamObject_ToolMode(t *testing.T) {
+	model := &toolModeStreamingLanguageModel{streamingLanguageModel{streams: [][]api.StreamEvent{{
+		&api.ToolCallDeltaEvent{ToolCallID: "call-1", ToolName: "json", ArgsDelta: []byte(`{"name":"Sal`)},
+		&api.ToolCallDeltaEvent{ToolCallID: "call-1", ToolName: "json", ArgsDelta: []byte(`ad","ingredients":["lettuce"]}`)},
+		&api.ToolCallEvent{ToolCallID: "call-1", ToolName: "json", Args: []byte(`{"name":"Salad","ingredients":["lettuce"]}`)},
+		&api.FinishEvent{FinishReason: api.FinishReasonToolCalls},
+	}}}}
+
+	resp, err := StreamObjectStr[recipe](t.Context(), "Give me a recipe", WithModel(model))
+	require.NoError(t, err)
+
+	var partials []*recipe
+	for partial := range resp.Partials {
+		partials = append(partials, partial)
+	}
+	assert.Equal(t, []*recipe{
+		{Name: "Sal"},
+		{Name: "Salad", Ingredients: []string{"lettuce"}},
+	}, partials)
+
+	object, err := resp.Object()
+	require.NoError(t, err)
+	assert.Equal(t, &recipe{Name: "Salad", Ingredients: []string{"lettuce"}}, object)
+}
+
+func TestStreamObject_ObjectWithoutIterating(t *testing.T) {
+	model := &streamingLanguageModel{streams: [][]api.StreamEvent{
+		textDeltas(`{"name": "Toast", `, `"ingredients": ["bread"]}`),
+	}}
+
+	resp, err := StreamObjectStr[recipe](t.Context(), "Give me a recipe", WithModel(model))
+	require.NoError(t, err)
+
+	object, err := resp.Object()
+	require.NoError(t, err)
+	assert.Equal(t, &recipe{Name: "Toast", Ingredients: []string{"bread"}}, object)
+}
+
+func TestStreamObject_Errors(t *testing.T) {
+	t.Run("stream error", func(t *testing.T) {
+		model := &streamingLanguageModel{streams: [][]api.StreamEvent{{
+			&api.TextDeltaEvent{TextDelta: `{"name": "Pan`},
+			&api.ErrorEvent{Err: errors.New("connection reset")},
+		}}}
+
+		resp, err := StreamObjectStr[recipe](t.Context(), "Give me a recipe", WithModel(model))
+		require.NoError(t, err)
+
+		object, err := resp.Object()
+		assert.Nil(t, object)
+		assert.EqualError(t, err, "connection reset")
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		model := &streamingLanguageModel{streams: [][]api.StreamEvent{
+			textDeltas(`{"name": "Pancakes"}`),
+		}}
+
+		resp, err := StreamObjectStr[recipe](t.Context(), "Give me a recipe", WithModel(model))
+		require.NoError(t, err)
+
+		_, err = resp.Object()
+		var validationErr *api.TypeValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, `{"name": "Pancakes"}`, validationErr.Value)
+	})
+}
@@ -0,0 +1,118 @@
+package ai
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+)
+
+type recipe struct {
+	Name        string   `json:"name"`
+	Ingredients []string `json:"ingredients"`
+}
+
+// toolModeLanguageModel is a scripted model that prefers tool mode for
+// object generation.
+type toolModeLanguageModel struct {
+	scriptedLanguageModel
+}
+
+func (m *toolModeLanguageModel) DefaultObjectGenerationMode() api.ObjectGenerationMode {
+	return api.ObjectGenerationModeTool
+}
+
+func textResponse(text string) *api.Response {
+	return &api.Response{Content: []api.ContentBlock{&api.TextBlock{Text: text}}}
+}
+
+func TestGenerateObject_JSONMode(t *testing.T) {
+	model := &scriptedLanguageModel{responses: []*api.Response{
+		textResponse(`{"name":"Pancakes","ingredients":["flour","eggs","milk"]}`),
+	}}
+
+	result, err := GenerateObjectStr[recipe](t.Context(), "Give me a recipe", WithModel(model))
+	require.NoError(t, err)
+	assert.Equal(t, &recipe{Name: "Pancakes", Ingredients: []string{"flour", "eggs", "milk"}}, result)
+
+	require.Len(t, model.options, 1)
+	format := model.options[0].ResponseFormat
+	require.NotNil(t, format)
+	assert.Equal(t, "json", format.Type)
+	assert.Equal(t, "response", format.Name)
+	require.NotNil(t, format.Schema)
+	assert.Contains(t, format.Schema.Properties, "ingredients")
+	assert.Empty(t, model.options[0].Tools)
+}
+
+func TestGenerateObject_ToolMode(t *testing.T) {
+	model := &toolModeLanguageModel{scriptedLanguageModel{responses: []*api.Response{{
+		Content: []api.ContentBlock{&api.ToolCallBlock{
+			ToolCallID: "call-1",
+			ToolName:   "json",
+			Args:       json.RawMessage(`{"name":"Salad","ingredients":["lettuce"]}`),
+		}},
+	}}}}
+
+	result, err := GenerateObjectStr[recipe](t.Context(), "Give me a recipe", WithModel(model))
+	require.NoError(t, err)
+	assert.Equal(t, &recipe{Name: "Salad", Ingredients: []string{"lettuce"}}, result)
+
+	require.Len(t, model.options, 1)
+	opts := model.options[0]
+	assert.Nil(t, opts.ResponseFormat)
+	assert.Equal(t, &api.ToolChoice{Type: "tool", ToolName: "json"}, opts.ToolChoice)
+	require.Len(t, opts.Tools, 1)
+	tool, ok := opts.Tools[0].(*api.FunctionTool)
+	require.True(t, ok)
+	assert.Equal(t, "json", tool.Name)
+	assert.NotNil(t, tool.InputSchema)
+}
+
+func TestGenerateObject_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		response  *api.Response
+		isJSONErr bool
+	}{
+		{
+			name:      "invalid json",
+			response:  textResponse(`{"name": "Pancakes"`),
+			isJSONErr: true,
+		},
+		{
+			name:     "schema mismatch",
+			response: textResponse(`{"name": 42, "ingredients": []}`),
+		},
+		{
+			name:     "missing required property",
+			response: textResponse(`{"name": "Pancakes"}`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := &scriptedLanguageModel{responses: []*api.Response{tt.response}}
+			result, err := GenerateObjectStr[recipe](t.Context(), "Give me a recipe", WithModel(model))
+			require.Error(t, err)
+			assert.Nil(t, result)
+
+			var validationErr *api.TypeValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.response.Content[0].(*api.TextBlock).Text, validationErr.Value)
+
+			_, isJSONErr := validationErr.Cause.(*api.JSONParseError)
+			assert.Equal(t, tt.isJSONErr, isJSONErr)
+		})
+	}
+}
+
+func TestGenerateObject_NoContent(t *testing.T) {
+	model := &scriptedLanguageModel{responses: []*api.Response{{}}}
+	_, err := GenerateObjectStr[recipe](t.Context(), "Give me a recipe", WithModel(model))
+
+	var noContentErr *api.NoContentGeneratedError
+	require.ErrorAs(t, err, &noContentErr)
+}
@@ -2,13 +2,35 @@ package ai
 
 import (
 	"net/http"
+	"slices"
 
 	"go.jetify.com/ai/api"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type GenerateOptions struct {
 	CallOptions api.CallOptions
 	Model       api.LanguageModel
+
+	// MaxSteps is the maximum number of model calls GenerateText makes when
+	// executing tools. Zero and one both mean a single call.
+	MaxSteps int
+
+	// ToolExecutors maps tool names to the functions that execute them.
+	ToolExecutors map[string]ToolExecuteFunc
+
+	// executableTools holds the definitions registered with
+	// WithExecutableTools. They are appended to CallOptions.Tools once all
+	// options have been applied, so that WithTools and WithCallOptions do not
+	// discard them.
+	executableTools []api.ToolDefinition
+
+	// RetryPolicy controls how failed model calls are retried.
+	RetryPolicy RetryPolicy
+
+	// TracerProvider, if set, records a span for every GenerateText and
+	// StreamText call.
+	TracerProvider trace.TracerProvider
 }
 
 // GenerateOption is a function that modifies GenerateConfig.
@@ -115,6 +137,64 @@ func WithToolChoice(toolChoice *api.ToolChoice) GenerateOption {
 	}
 }
 
+// WithExecutableTools makes tools available to the model and registers the
+// functions that execute them. The tool definitions are appended to any tools
+// set with [WithTools], regardless of the order of the options.
+//
+// When the model calls one of these tools, GenerateText executes it and, if
+// [WithMaxSteps] allows, sends the results back to the model in a follow-up
+// call.
+func WithExecutableTools(tools ...ExecutableTool) GenerateOption {
+	return func(o *GenerateOptions) {
+		if o.ToolExecutors == nil {
+			o.ToolExecutors = make(map[string]ToolExecuteFunc, len(tools))
+		}
+		for _, tool := range tools {
+			o.executableTools = append(o.executableTools, tool.Definition)
+			o.ToolExecutors[tool.Definition.Name] = tool.Execute
+		}
+	}
+}
+
+// WithMaxSteps sets the maximum number of model calls GenerateText makes
+// while executing tools. Each step is one call to the model, so a value of 1
+// executes the requested tools without asking the model for a follow-up.
+func WithMaxSteps(maxSteps int) GenerateOption {
+	return func(o *GenerateOptions) {
+		o.MaxSteps = maxSteps
+	}
+}
+
+// WithMaxRetries sets the number of times a model call is retried when it
+// fails with a retryable [api.APICallError]. Retries use exponential backoff as
+// described by [DefaultRetryPolicy], unless a policy was set with
+// [WithRetryPolicy]. Streams are only retried until the first event arrives.
+func WithMaxRetries(maxRetries int) GenerateOption {
+	return func(o *GenerateOptions) {
+		if o.RetryPolicy == (RetryPolicy{}) {
+			o.RetryPolicy = DefaultRetryPolicy()
+		}
+		o.RetryPolicy.MaxRetries = maxRetries
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry failed model calls.
+func WithRetryPolicy(policy RetryPolicy) GenerateOption {
+	return func(o *GenerateOptions) {
+		o.RetryPolicy = policy
+	}
+}
+
+// WithTracerProvider makes GenerateText and StreamText record a span with tp
+// that covers every retry and tool step of the call, and records the finish
+// reason and the token usage summed across them. Models wrapped by the
+// telemetry package record the span of each model call as a child of it.
+func WithTracerProvider(tp trace.TracerProvider) GenerateOption {
+	return func(o *GenerateOptions) {
+		o.TracerProvider = tp
+	}
+}
+
 // WithProviderMetadata sets additional provider-specific metadata.
 // The metadata is passed through to the provider from the AI SDK and enables
 // provider-specific functionality that can be fully encapsulated in the provider.
@@ -146,5 +226,8 @@ func buildGenerateConfig(opts []GenerateOption) GenerateOptions {
 	for _, opt := range opts {
 		opt(&config)
 	}
+	if len(config.executableTools) > 0 {
+		config.CallOptions.Tools = slices.Concat(config.CallOptions.Tools, config.executableTools)
+	}
 	return config
 }
@@ -115,6 +115,26 @@ func TestCallOptionBuilders(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "WithMaxSteps",
+			option: WithMaxSteps(5),
+			expected: GenerateOptions{
+				MaxSteps: 5,
+			},
+		},
+		{
+			name:   "WithMaxRetries",
+			option: WithMaxRetries(3),
+			expected: GenerateOptions{
+				RetryPolicy: RetryPolicy{
+					MaxRetries:    3,
+					InitialDelay:  DefaultRetryPolicy().InitialDelay,
+					MaxDelay:      DefaultRetryPolicy().MaxDelay,
+					BackoffFactor: DefaultRetryPolicy().BackoffFactor,
+					Jitter:        DefaultRetryPolicy().Jitter,
+				},
+			},
+		},
 		{
 			name: "WithProviderMetadata_SingleProvider",
 			option: WithProviderMetadata("test-provider", map[string]any{
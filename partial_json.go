@@ -0,0 +1,152 @@
+package ai
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// repairPartialJSON turns a prefix of a JSON document into a valid JSON
+// document by closing unterminated strings, arrays and objects and by dropping
+// trailing tokens that cannot be completed (partial keys, literals or
+// dangling separators). It returns false if no valid prefix exists yet.
+//
+// Partial string values are kept so that text can be rendered while it is
+// being generated.
+func repairPartialJSON(text string) (string, bool) {
+	var s partialJSONScanner
+	for i := 0; i < len(text); i++ {
+		s.step(text, i)
+	}
+	return s.finish(text)
+}
+
+// partialJSONScanner tracks enough of the JSON grammar to find the last
+// position at which the document can be cut and closed.
+type partialJSONScanner struct {
+	// stack holds the closing character of every open array or object.
+	stack []byte
+	// expectKey holds, for every open object, whether the next string is a key.
+	expectKey []bool
+
+	inString     bool
+	stringIsKey  bool
+	escape       bool
+	unicodeLeft  int
+	inLiteral    bool
+	literalStart int
+
+	// cut is the length of the longest prefix that ends right after an opening
+	// bracket or a complete value, and cutStack the containers open at that
+	// point. hasCut reports whether such a prefix has been seen.
+	cut      int
+	cutStack []byte
+	hasCut   bool
+}
+
+func (s *partialJSONScanner) step(text string, i int) {
+	c := text[i]
+
+	if s.inString {
+		switch {
+		case s.escape:
+			s.escape = false
+			if c == 'u' {
+				s.unicodeLeft = 4
+			}
+		case s.unicodeLeft > 0:
+			s.unicodeLeft--
+		case c == '\\':
+			s.escape = true
+		case c == '"':
+			s.inString = false
+			if !s.stringIsKey {
+				s.checkpoint(i + 1)
+			}
+		}
+		return
+	}
+
+	if s.inLiteral {
+		if isLiteralByte(c) {
+			return
+		}
+		s.inLiteral = false
+		s.checkpoint(i)
+	}
+
+	switch c {
+	case ' ', '\t', '\n', '\r':
+	case '{':
+		s.stack = append(s.stack, '}')
+		s.expectKey = append(s.expectKey, true)
+		s.checkpoint(i + 1)
+	case '[':
+		s.stack = append(s.stack, ']')
+		s.expectKey = append(s.expectKey, false)
+		s.checkpoint(i + 1)
+	case '}', ']':
+		if len(s.stack) > 0 {
+			s.stack = s.stack[:len(s.stack)-1]
+			s.expectKey = s.expectKey[:len(s.expectKey)-1]
+		}
+		s.checkpoint(i + 1)
+	case ':':
+		s.setExpectKey(false)
+	case ',':
+		s.setExpectKey(true)
+	case '"':
+		s.inString = true
+		s.stringIsKey = s.inObject() && s.expectKey[len(s.expectKey)-1]
+	default:
+		s.inLiteral = true
+		s.literalStart = i
+	}
+}
+
+func (s *partialJSONScanner) finish(text string) (string, bool) {
+	switch {
+	case s.inString && !s.stringIsKey:
+		// Close the partial string value, dropping any incomplete escape sequence.
+		end := len(text)
+		if s.escape {
+			end--
+		} else if s.unicodeLeft > 0 {
+			end -= 2 + 4 - s.unicodeLeft
+		}
+		return text[:end] + `"` + closers(s.stack), true
+	case s.inLiteral && json.Valid([]byte(text[s.literalStart:])):
+		return text + closers(s.stack), true
+	case !s.hasCut:
+		return "", false
+	default:
+		return text[:s.cut] + closers(s.cutStack), true
+	}
+}
+
+func (s *partialJSONScanner) checkpoint(i int) {
+	s.cut = i
+	s.cutStack = append(s.cutStack[:0], s.stack...)
+	s.hasCut = true
+}
+
+func (s *partialJSONScanner) inObject() bool {
+	return len(s.stack) > 0 && s.stack[len(s.stack)-1] == '}'
+}
+
+func (s *partialJSONScanner) setExpectKey(expectKey bool) {
+	if s.inObject() {
+		s.expectKey[len(s.expectKey)-1] = expectKey
+	}
+}
+
+func isLiteralByte(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' ||
+		c == '.' || c == '+' || c == '-'
+}
+
+// closers returns the characters that close the given open containers.
+func closers(stack []byte) string {
+	closing := slices.Clone(stack)
+	slices.Reverse(closing)
+	return string(closing)
+}
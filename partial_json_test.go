@@ -0,0 +1,68 @@
+package ai
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepairPartialJSON(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		ok       bool
+	}{
+		{input: ``, ok: false},
+		{input: `  `, ok: false},
+		{input: `{`, expected: `{}`, ok: true},
+		{input: `{"na`, expected: `{}`, ok: true},
+		{input: `{"name"`, expected: `{}`, ok: true},
+		{input: `{"name":`, expected: `{}`, ok: true},
+		{input: `{"name": "Pan`, expected: `{"name": "Pan"}`, ok: true},
+		{input: `{"name": "Pancakes"`, expected: `{"name": "Pancakes"}`, ok: true},
+		{input: `{"name": "Pancakes",`, expected: `{"name": "Pancakes"}`, ok: true},
+		{input: `{"name": "Pancakes", "ingredients": [`, expected: `{"name": "Pancakes", "ingredients": []}`, ok: true},
+		{input: `{"a": ["x", "y`, expected: `{"a": ["x", "y"]}`, ok: true},
+		{input: `{"a": ["x", "y"], "b": {"c": 1`, expected: `{"a": ["x", "y"], "b": {"c": 1}}`, ok: true},
+		{input: `{"a": 12`, expected: `{"a": 12}`, ok: true},
+		{input: `{"a": 12.`, expected: `{}`, ok: true},
+		{input: `{"a": -`, expected: `{}`, ok: true},
+		{input: `{"a": tr`, expected: `{}`, ok: true},
+		{input: `{"a": true`, expected: `{"a": true}`, ok: true},
+		{input: `{"a": "x", "b": nu`, expected: `{"a": "x"}`, ok: true},
+		{input: `{"a": "line\`, expected: `{"a": "line"}`, ok: true},
+		{input: `{"a": "quote \"`, expected: `{"a": "quote \""}`, ok: true},
+		{input: `{"a": "\u00`, expected: `{"a": ""}`, ok: true},
+		{input: `{"a": "é`, expected: `{"a": "é"}`, ok: true},
+		{input: `[1, 2, 3`, expected: `[1, 2, 3]`, ok: true},
+		{input: `[{"a": 1}, {"b"`, expected: `[{"a": 1}, {}]`, ok: true},
+		{input: `{"a": {"b": [1]}}`, expected: `{"a": {"b": [1]}}`, ok: true},
+		{input: `"hel`, expected: `"hel"`, ok: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			repaired, ok := repairPartialJSON(tt.input)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, repaired)
+			if ok {
+				assert.True(t, json.Valid([]byte(repaired)), "invalid JSON: %s", repaired)
+			}
+		})
+	}
+}
+
+func TestRepairPartialJSON_EveryPrefix(t *testing.T) {
+	full := `{"name": "Crêpes \"fines\"", "servings": 4, "vegan": false, "tags": ["sweet", null],` +
+		` "steps": [{"text": "Mix\nwell", "minutes": 2.5e1}], "note": "été"}`
+	for i := 1; i <= len(full); i++ {
+		repaired, ok := repairPartialJSON(full[:i])
+		if ok {
+			assert.True(t, json.Valid([]byte(repaired)), "prefix %q repaired to invalid JSON %q", full[:i], repaired)
+		}
+	}
+	repaired, ok := repairPartialJSON(full)
+	assert.True(t, ok)
+	assert.Equal(t, full, repaired)
+}
@@ -0,0 +1,112 @@
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/tidwall/gjson"
+	"go.jetify.com/ai/api"
+)
+
+// DecodeError converts an error returned by the Anthropic SDK for a failed HTTP
+// request into an *api.APICallError, so that it can be retried and inspected
+// with errors.As. Other errors are returned unchanged.
+func DecodeError(err error) error {
+	var anthropicErr *anthropic.Error
+	if !errors.As(err, &anthropicErr) {
+		return err
+	}
+
+	body := strings.TrimSpace(anthropicErr.RawJSON())
+	status := http.StatusText(anthropicErr.StatusCode)
+	if anthropicErr.Response != nil {
+		status = anthropicErr.Response.Status
+	}
+	message := status
+	// Anthropic errors have the shape {"type": "error", "error": {"type": ..., "message": ...}}.
+	if detail := gjson.Get(body, "error.message").String(); detail != "" {
+		message = fmt.Sprintf("%s: %s", status, detail)
+	} else if body != "" {
+		message = fmt.Sprintf("%s: %s", status, body)
+	}
+
+	apiErr := &api.APICallError{
+		AISDKError:   api.NewAISDKError("AI_APICallError", message, err),
+		Request:      anthropicErr.Request,
+		StatusCode:   anthropicErr.StatusCode,
+		Response:     anthropicErr.Response,
+		ResponseBody: []byte(body),
+		Data:         anthropicErr,
+	}
+	if anthropicErr.Request != nil {
+		apiErr.URL = anthropicErr.Request.URL
+		apiErr.RequestBody = requestBody(anthropicErr.Request)
+	}
+	return apiErr
+}
+
+// streamErrorPrefix is how the Anthropic SDK reports an error event received
+// in the middle of a stream, followed by the JSON data of the event.
+const streamErrorPrefix = "received error while streaming: "
+
+// streamErrorStatus maps the error types Anthropic reports in stream error
+// events to the status code the same error has when it is returned before
+// the stream starts, so that retryable errors are recognized as such.
+var streamErrorStatus = map[string]int{
+	"invalid_request_error": http.StatusBadRequest,
+	"authentication_error":  http.StatusUnauthorized,
+	"permission_error":      http.StatusForbidden,
+	"not_found_error":       http.StatusNotFound,
+	"request_too_large":     http.StatusRequestEntityTooLarge,
+	"rate_limit_error":      http.StatusTooManyRequests,
+	"api_error":             http.StatusInternalServerError,
+	"overloaded_error":      529,
+}
+
+// decodeStreamError converts an error that ended a stream into an
+// *api.APICallError. Error events sent by Anthropic carry the type of the
+// error, which is mapped to a status code; other errors, such as a broken
+// connection, have no status code.
+func decodeStreamError(err error) error {
+	var apiErr *api.APICallError
+	if errors.As(DecodeError(err), &apiErr) {
+		return apiErr
+	}
+
+	body, isErrorEvent := strings.CutPrefix(err.Error(), streamErrorPrefix)
+	if !isErrorEvent {
+		return &api.APICallError{
+			AISDKError: api.NewAISDKError("AI_APICallError", fmt.Sprintf("reading stream: %v", err), err),
+		}
+	}
+
+	errorType := gjson.Get(body, "error.type").String()
+	message := gjson.Get(body, "error.message").String()
+	if message == "" {
+		message = body
+	}
+	return &api.APICallError{
+		AISDKError:   api.NewAISDKError("AI_APICallError", message, err),
+		StatusCode:   streamErrorStatus[errorType],
+		ResponseBody: []byte(body),
+		Data:         errorType,
+	}
+}
+
+// requestBody returns a copy of the body of req, if it can be read again.
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+	content, _ := io.ReadAll(body)
+	return content
+}
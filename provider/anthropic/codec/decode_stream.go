@@ -0,0 +1,238 @@
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"strconv"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"go.jetify.com/ai/api"
+)
+
+// StreamReader is an interface for reading from an SSE stream.
+// This abstraction makes testing easier as we can mock this interface instead of the concrete ssestream.Stream type.
+type StreamReader interface {
+	Next() bool
+	Current() anthropic.BetaRawMessageStreamEventUnion
+	Err() error
+}
+
+// DecodeStream converts an Anthropic SSE stream to our API's StreamResponse.
+// The warnings are sent with the StreamStartEvent that opens the stream.
+func DecodeStream(stream StreamReader, warnings []api.CallWarning) (*api.StreamResponse, error) {
+	decoder := &streamDecoder{
+		warnings: warnings,
+		blocks:   make(map[int64]*streamBlock),
+	}
+	return &api.StreamResponse{
+		Stream: decoder.decodeEvents(stream),
+	}, nil
+}
+
+// streamDecoder maintains state while decoding a stream of Anthropic events.
+type streamDecoder struct {
+	// Warnings for the call, sent with the StreamStartEvent
+	warnings []api.CallWarning
+
+	// Map from content block index to the ongoing content block
+	blocks map[int64]*streamBlock
+
+	// Usage statistics, reported cumulatively by message_start and message_delta
+	usage anthropic.BetaUsage
+
+	stopReason anthropic.BetaStopReason
+}
+
+// streamBlock tracks a content block between its start and stop events.
+type streamBlock struct {
+	blockType  string
+	toolCallID string
+	toolName   string
+	args       []byte
+}
+
+// decodeEvents returns an iterator that yields events from the Anthropic stream.
+func (d *streamDecoder) decodeEvents(stream StreamReader) iter.Seq[api.StreamEvent] {
+	return func(yield func(api.StreamEvent) bool) {
+		warnings := d.warnings
+		if warnings == nil {
+			warnings = []api.CallWarning{}
+		}
+		if !yield(&api.StreamStartEvent{Warnings: warnings}) {
+			return
+		}
+
+		for stream.Next() {
+			for _, event := range d.decodeEvent(stream.Current()) {
+				if !yield(event) {
+					return
+				}
+			}
+		}
+
+		if err := stream.Err(); err != nil && !errors.Is(err, io.EOF) {
+			if !yield(&api.ErrorEvent{Err: decodeStreamError(err)}) {
+				return
+			}
+		}
+
+		yield(&api.FinishEvent{
+			FinishReason: decodeFinishReason(d.stopReason),
+			Usage:        decodeUsage(d.usage),
+			ProviderMetadata: api.NewProviderMetadata(map[string]any{
+				"anthropic": &Metadata{
+					Usage: Usage{
+						InputTokens:              d.usage.InputTokens,
+						OutputTokens:             d.usage.OutputTokens,
+						CacheCreationInputTokens: d.usage.CacheCreationInputTokens,
+						CacheReadInputTokens:     d.usage.CacheReadInputTokens,
+					},
+				},
+			}),
+		})
+	}
+}
+
+// decodeEvent translates an Anthropic event to our API event format.
+// It returns no events if the event only updates the decoder state.
+func (d *streamDecoder) decodeEvent(event anthropic.BetaRawMessageStreamEventUnion) []api.StreamEvent {
+	switch event.Type {
+	case "message_start":
+		return d.decodeMessageStart(event)
+	case "content_block_start":
+		return d.decodeContentBlockStart(event)
+	case "content_block_delta":
+		return d.decodeContentBlockDelta(event)
+	case "content_block_stop":
+		return d.decodeContentBlockStop(event)
+	case "message_delta":
+		d.decodeMessageDelta(event)
+	}
+	return nil
+}
+
+// decodeMessageStart handles message_start events, which carry the response
+// metadata and the input token usage.
+func (d *streamDecoder) decodeMessageStart(event anthropic.BetaRawMessageStreamEventUnion) []api.StreamEvent {
+	msg := event.Message
+	d.usage = msg.Usage
+	return []api.StreamEvent{&api.ResponseMetadataEvent{
+		ID:      msg.ID,
+		ModelID: string(msg.Model),
+	}}
+}
+
+// decodeContentBlockStart handles content_block_start events. Content blocks
+// are identified by their index in the message.
+func (d *streamDecoder) decodeContentBlockStart(event anthropic.BetaRawMessageStreamEventUnion) []api.StreamEvent {
+	block := event.ContentBlock
+	id := strconv.FormatInt(event.Index, 10)
+
+	switch block.Type {
+	case "text":
+		d.blocks[event.Index] = &streamBlock{blockType: block.Type}
+		decoded := []api.StreamEvent{&api.TextStartEvent{ID: id}}
+		if block.Text != "" {
+			decoded = append(decoded, &api.TextDeltaEvent{TextDelta: block.Text})
+		}
+		return decoded
+	case "thinking", "redacted_thinking":
+		// Redacted thinking has no text, so it is only reported by its
+		// start and end events.
+		d.blocks[event.Index] = &streamBlock{blockType: block.Type}
+		decoded := []api.StreamEvent{&api.ReasoningStartEvent{ID: id}}
+		if block.Thinking != "" {
+			decoded = append(decoded, &api.ReasoningEvent{TextDelta: block.Thinking})
+		}
+		return decoded
+	case "tool_use":
+		d.blocks[event.Index] = &streamBlock{
+			blockType:  block.Type,
+			toolCallID: block.ID,
+			toolName:   block.Name,
+		}
+		return []api.StreamEvent{&api.ToolInputStartEvent{
+			ToolCallID: block.ID,
+			ToolName:   block.Name,
+		}}
+	}
+	return nil
+}
+
+// decodeContentBlockDelta handles content_block_delta events
+func (d *streamDecoder) decodeContentBlockDelta(event anthropic.BetaRawMessageStreamEventUnion) []api.StreamEvent {
+	delta := event.Delta
+	switch delta.Type {
+	case "text_delta":
+		return []api.StreamEvent{&api.TextDeltaEvent{TextDelta: delta.Text}}
+	case "thinking_delta":
+		return []api.StreamEvent{&api.ReasoningEvent{TextDelta: delta.Thinking}}
+	case "signature_delta":
+		return []api.StreamEvent{&api.ReasoningSignatureEvent{Signature: delta.Signature}}
+	case "input_json_delta":
+		block, ok := d.blocks[event.Index]
+		if !ok || block.blockType != "tool_use" || delta.PartialJSON == "" {
+			return nil
+		}
+		block.args = append(block.args, delta.PartialJSON...)
+		return []api.StreamEvent{&api.ToolCallDeltaEvent{
+			ToolCallID: block.toolCallID,
+			ToolName:   block.toolName,
+			ArgsDelta:  []byte(delta.PartialJSON),
+		}}
+	}
+	return nil
+}
+
+// decodeContentBlockStop handles content_block_stop events, which end the
+// block started at the same index. Tool calls are completed once their input
+// has been fully received.
+func (d *streamDecoder) decodeContentBlockStop(event anthropic.BetaRawMessageStreamEventUnion) []api.StreamEvent {
+	block, ok := d.blocks[event.Index]
+	if !ok {
+		return nil
+	}
+	delete(d.blocks, event.Index)
+	id := strconv.FormatInt(event.Index, 10)
+
+	switch block.blockType {
+	case "text":
+		return []api.StreamEvent{&api.TextEndEvent{ID: id}}
+	case "thinking", "redacted_thinking":
+		return []api.StreamEvent{&api.ReasoningEndEvent{ID: id}}
+	case "tool_use":
+		args := block.args
+		if len(args) == 0 {
+			args = []byte("{}")
+		}
+		return []api.StreamEvent{
+			&api.ToolInputEndEvent{ToolCallID: block.toolCallID},
+			&api.ToolCallEvent{
+				ToolCallID: block.toolCallID,
+				ToolName:   block.toolName,
+				Args:       json.RawMessage(args),
+			},
+		}
+	}
+	return nil
+}
+
+// decodeMessageDelta handles message_delta events, which carry the stop
+// reason and the cumulative output token usage.
+func (d *streamDecoder) decodeMessageDelta(event anthropic.BetaRawMessageStreamEventUnion) {
+	if event.Delta.StopReason != "" {
+		d.stopReason = event.Delta.StopReason
+	}
+	d.usage.OutputTokens = event.Usage.OutputTokens
+	if event.Usage.InputTokens > 0 {
+		d.usage.InputTokens = event.Usage.InputTokens
+	}
+	if event.Usage.CacheCreationInputTokens > 0 {
+		d.usage.CacheCreationInputTokens = event.Usage.CacheCreationInputTokens
+	}
+	if event.Usage.CacheReadInputTokens > 0 {
+		d.usage.CacheReadInputTokens = event.Usage.CacheReadInputTokens
+	}
+}
@@ -0,0 +1,201 @@
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+)
+
+func TestDecodeStream(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventJSONs []string
+		warnings   []api.CallWarning
+		err        error
+		want       []api.StreamEvent
+	}{
+		{
+			name: "text stream",
+			eventJSONs: []string{
+				`{"type": "message_start", "message": {"id": "msg_1", "model": "claude-3", "usage": {"input_tokens": 10, "output_tokens": 1}}}`,
+				`{"type": "content_block_start", "index": 0, "content_block": {"type": "text", "text": ""}}`,
+				`{"type": "content_block_delta", "index": 0, "delta": {"type": "text_delta", "text": "Hello"}}`,
+				`{"type": "content_block_delta", "index": 0, "delta": {"type": "text_delta", "text": " world"}}`,
+				`{"type": "content_block_stop", "index": 0}`,
+				`{"type": "message_delta", "delta": {"stop_reason": "end_turn"}, "usage": {"output_tokens": 5}}`,
+				`{"type": "message_stop"}`,
+			},
+			warnings: []api.CallWarning{{Type: "unsupported-setting", Setting: "TopK"}},
+			want: []api.StreamEvent{
+				&api.StreamStartEvent{Warnings: []api.CallWarning{{Type: "unsupported-setting", Setting: "TopK"}}},
+				&api.ResponseMetadataEvent{ID: "msg_1", ModelID: "claude-3"},
+				&api.TextStartEvent{ID: "0"},
+				&api.TextDeltaEvent{TextDelta: "Hello"},
+				&api.TextDeltaEvent{TextDelta: " world"},
+				&api.TextEndEvent{ID: "0"},
+				&api.FinishEvent{
+					FinishReason: api.FinishReasonStop,
+					Usage:        api.Usage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15},
+					ProviderMetadata: api.NewProviderMetadata(map[string]any{
+						"anthropic": &Metadata{Usage: Usage{InputTokens: 10, OutputTokens: 5}},
+					}),
+				},
+			},
+		},
+		{
+			name: "thinking and tool use",
+			eventJSONs: []string{
+				`{"type": "content_block_start", "index": 0, "content_block": {"type": "thinking", "thinking": ""}}`,
+				`{"type": "content_block_delta", "index": 0, "delta": {"type": "thinking_delta", "thinking": "Let me check"}}`,
+				`{"type": "content_block_delta", "index": 0, "delta": {"type": "signature_delta", "signature": "sig"}}`,
+				`{"type": "content_block_stop", "index": 0}`,
+				`{"type": "content_block_start", "index": 1, "content_block": {"type": "tool_use", "id": "toolu_1", "name": "weather", "input": {}}}`,
+				`{"type": "content_block_delta", "index": 1, "delta": {"type": "input_json_delta", "partial_json": "{\"city\":"}}`,
+				`{"type": "content_block_delta", "index": 1, "delta": {"type": "input_json_delta", "partial_json": "\"Paris\"}"}}`,
+				`{"type": "content_block_stop", "index": 1}`,
+				`{"type": "content_block_start", "index": 2, "content_block": {"type": "tool_use", "id": "toolu_2", "name": "time", "input": {}}}`,
+				`{"type": "content_block_stop", "index": 2}`,
+				`{"type": "message_delta", "delta": {"stop_reason": "tool_use"}, "usage": {"output_tokens": 20}}`,
+			},
+			want: []api.StreamEvent{
+				&api.StreamStartEvent{Warnings: []api.CallWarning{}},
+				&api.ReasoningStartEvent{ID: "0"},
+				&api.ReasoningEvent{TextDelta: "Let me check"},
+				&api.ReasoningSignatureEvent{Signature: "sig"},
+				&api.ReasoningEndEvent{ID: "0"},
+				&api.ToolInputStartEvent{ToolCallID: "toolu_1", ToolName: "weather"},
+				&api.ToolCallDeltaEvent{ToolCallID: "toolu_1", ToolName: "weather", ArgsDelta: []byte(`{"city":`)},
+				&api.ToolCallDeltaEvent{ToolCallID: "toolu_1", ToolName: "weather", ArgsDelta: []byte(`"Paris"}`)},
+				&api.ToolInputEndEvent{ToolCallID: "toolu_1"},
+				&api.ToolCallEvent{ToolCallID: "toolu_1", ToolName: "weather", Args: json.RawMessage(`{"city":"Paris"}`)},
+				&api.ToolInputStartEvent{ToolCallID: "toolu_2", ToolName: "time"},
+				&api.ToolInputEndEvent{ToolCallID: "toolu_2"},
+				&api.ToolCallEvent{ToolCallID: "toolu_2", ToolName: "time", Args: json.RawMessage(`{}`)},
+				&api.FinishEvent{
+					FinishReason: api.FinishReasonToolCalls,
+					Usage:        api.Usage{OutputTokens: 20, TotalTokens: 20},
+					ProviderMetadata: api.NewProviderMetadata(map[string]any{
+						"anthropic": &Metadata{Usage: Usage{OutputTokens: 20}},
+					}),
+				},
+			},
+		},
+		{
+			name: "stream error",
+			eventJSONs: []string{
+				`{"type": "content_block_start", "index": 0, "content_block": {"type": "text", "text": "Hi"}}`,
+			},
+			err: errors.New("connection reset"),
+			want: []api.StreamEvent{
+				&api.StreamStartEvent{Warnings: []api.CallWarning{}},
+				&api.TextStartEvent{ID: "0"},
+				&api.TextDeltaEvent{TextDelta: "Hi"},
+				&api.ErrorEvent{Err: &api.APICallError{
+					AISDKError: api.NewAISDKError("AI_APICallError", "reading stream: connection reset",
+						errors.New("connection reset")),
+				}},
+				&api.FinishEvent{
+					FinishReason: api.FinishReasonUnknown,
+					ProviderMetadata: api.NewProviderMetadata(map[string]any{
+						"anthropic": &Metadata{},
+					}),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []anthropic.BetaRawMessageStreamEventUnion
+			for _, jsonStr := range tt.eventJSONs {
+				var event anthropic.BetaRawMessageStreamEventUnion
+				require.NoError(t, json.Unmarshal([]byte(jsonStr), &event))
+				events = append(events, event)
+			}
+
+			result, err := DecodeStream(&mockStreamReader{events: events, index: -1, err: tt.err}, tt.warnings)
+			require.NoError(t, err)
+
+			var got []api.StreamEvent
+			for event := range result.Stream {
+				got = append(got, event)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDecodeStream_ErrorTypes(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		message    string
+		statusCode int
+		retryable  bool
+	}{
+		{
+			name:       "overloaded error event",
+			err:        errors.New(`received error while streaming: {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`),
+			message:    "Overloaded",
+			statusCode: 529,
+			retryable:  true,
+		},
+		{
+			name:       "invalid request error event",
+			err:        errors.New(`received error while streaming: {"type":"error","error":{"type":"invalid_request_error","message":"bad"}}`),
+			message:    "bad",
+			statusCode: 400,
+		},
+		{
+			name:    "connection error",
+			err:     errors.New("connection reset"),
+			message: "reading stream: connection reset",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := DecodeStream(&mockStreamReader{index: -1, err: tt.err}, nil)
+			require.NoError(t, err)
+
+			var streamErr error
+			for event := range result.Stream {
+				if errEvent, ok := event.(*api.ErrorEvent); ok {
+					streamErr = errEvent.Err
+				}
+			}
+
+			var apiErr *api.APICallError
+			require.ErrorAs(t, streamErr, &apiErr)
+			assert.Equal(t, tt.message, apiErr.Message)
+			assert.Equal(t, tt.statusCode, apiErr.StatusCode)
+			assert.Equal(t, tt.retryable, apiErr.IsRetryable())
+			assert.Equal(t, tt.err, apiErr.Cause)
+		})
+	}
+}
+
+// mockStreamReader implements the StreamReader interface for testing
+type mockStreamReader struct {
+	events []anthropic.BetaRawMessageStreamEventUnion
+	index  int
+	err    error
+}
+
+func (m *mockStreamReader) Next() bool {
+	m.index++
+	return m.index < len(m.events)
+}
+
+func (m *mockStreamReader) Current() anthropic.BetaRawMessageStreamEventUnion {
+	return m.events[m.index]
+}
+
+func (m *mockStreamReader) Err() error {
+	return m.err
+}
@@ -3,55 +3,57 @@ package anthropic
 import (
 	"context"
 
-	"github.com/anthropics/anthropic-sdk-go"
 	"go.jetify.com/ai/api"
 	"go.jetify.com/ai/provider/anthropic/codec"
 )
 
-// ModelOption is a function type that modifies a LanguageModel.
-type ModelOption func(*LanguageModel)
-
-// WithClient returns a ModelOption that sets the client.
-func WithClient(client anthropic.Client) ModelOption {
-	// TODO: Instead of only supporting an anthropic.Client, we can "flatten"
-	// the options supported by the Anthropic SDK.
-	return func(m *LanguageModel) {
-		m.client = client
-	}
-}
+// ModelOption configures a LanguageModel created with NewLanguageModel.
+// It accepts the same options as NewProvider.
+type ModelOption = ProviderOption
 
 // LanguageModel represents an Anthropic language model.
 type LanguageModel struct {
 	modelID string
-	client  anthropic.Client
+	pc      ProviderConfig
 }
 
 var _ api.LanguageModel = &LanguageModel{}
 
 // NewLanguageModel creates a new Anthropic language model.
 func NewLanguageModel(modelID string, opts ...ModelOption) *LanguageModel {
-	// Create model with default settings
-	model := &LanguageModel{
-		modelID: modelID,
-		client:  anthropic.NewClient(), // Default client
-	}
+	return newProvider(opts...).newLanguageModel(modelID)
+}
 
-	// Apply options
-	for _, opt := range opts {
-		opt(model)
-	}
+// LanguageModel creates a new Anthropic language model.
+func (p *Provider) LanguageModel(modelID string) (api.LanguageModel, error) {
+	return p.newLanguageModel(modelID), nil
+}
 
-	return model
+func (p *Provider) newLanguageModel(modelID string) *LanguageModel {
+	return &LanguageModel{
+		modelID: modelID,
+		pc: ProviderConfig{
+			providerName: p.name,
+			client:       p.client,
+		},
+	}
 }
 
 func (m *LanguageModel) ProviderName() string {
-	return ProviderName
+	return m.pc.providerName
 }
 
 func (m *LanguageModel) ModelID() string {
 	return m.modelID
 }
 
+// DefaultObjectGenerationMode returns the default mode for object generation.
+// Anthropic does not support a JSON response format, so objects
+// are generated by forcing a tool call.
+func (m *LanguageModel) DefaultObjectGenerationMode() api.ObjectGenerationMode {
+	return api.ObjectGenerationModeTool
+}
+
 func (m *LanguageModel) SupportedUrls() []api.SupportedURL {
 	// TODO: Make configurable via the constructor.
 	return []api.SupportedURL{
@@ -72,9 +74,9 @@ func (m *LanguageModel) Generate(
 		return nil, err
 	}
 
-	message, err := m.client.Beta.Messages.New(ctx, params)
+	message, err := m.pc.client.Beta.Messages.New(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, codec.DecodeError(err)
 	}
 
 	response, err := codec.DecodeResponse(message)
@@ -89,5 +91,11 @@ func (m *LanguageModel) Generate(
 func (m *LanguageModel) Stream(
 	ctx context.Context, prompt []api.Message, opts api.CallOptions,
 ) (*api.StreamResponse, error) {
-	return nil, api.NewUnsupportedFunctionalityError("streaming generation", "")
+	params, warnings, err := codec.EncodeParams(m.modelID, prompt, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	stream := m.pc.client.Beta.Messages.NewStreaming(ctx, params)
+	return codec.DecodeStream(stream, warnings)
 }
@@ -1,13 +1,16 @@
 package anthropic
 
 import (
+	"encoding/json"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/anthropics/anthropic-sdk-go/option"
 	"github.com/stretchr/testify/require"
 	"go.jetify.com/ai/api"
+	"go.jetify.com/ai/provider/anthropic/codec"
 	"go.jetify.com/pkg/httpmock"
 )
 
@@ -145,3 +148,67 @@ func TestGenerate(t *testing.T) {
 		})
 	}
 }
+
+func TestStream(t *testing.T) {
+	var body strings.Builder
+	for _, event := range []string{
+		`{"type": "message_start", "message": {"id": "msg_1", "type": "message", "role": "assistant", "model": "claude-3", "content": [], "usage": {"input_tokens": 10, "output_tokens": 1}}}`,
+		`{"type": "content_block_start", "index": 0, "content_block": {"type": "text", "text": ""}}`,
+		`{"type": "content_block_delta", "index": 0, "delta": {"type": "text_delta", "text": "Hello"}}`,
+		`{"type": "content_block_stop", "index": 0}`,
+		`{"type": "message_delta", "delta": {"stop_reason": "end_turn"}, "usage": {"output_tokens": 5}}`,
+		`{"type": "message_stop"}`,
+	} {
+		var parsed struct {
+			Type string `json:"type"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(event), &parsed))
+		body.WriteString("event: " + parsed.Type + "\ndata: " + event + "\n\n")
+	}
+
+	server := httpmock.NewServer(t, []httpmock.Exchange{
+		{
+			Request: httpmock.Request{
+				Method: http.MethodPost,
+				Path:   "/v1/messages",
+			},
+			Response: httpmock.Response{
+				StatusCode: http.StatusOK,
+				Headers:    map[string]string{"Content-Type": "text/event-stream"},
+				Body:       body.String(),
+			},
+		},
+	})
+	defer server.Close()
+
+	client := anthropic.NewClient(
+		option.WithBaseURL(server.BaseURL()),
+		option.WithAPIKey("test-key"),
+		option.WithMaxRetries(0),
+	)
+	model := NewLanguageModel("claude-3", WithClient(client))
+
+	resp, err := model.Stream(t.Context(), []api.Message{
+		&api.UserMessage{Content: api.ContentFromText("Hi")},
+	}, api.CallOptions{})
+	require.NoError(t, err)
+
+	var events []api.StreamEvent
+	for event := range resp.Stream {
+		events = append(events, event)
+	}
+	require.Equal(t, []api.StreamEvent{
+		&api.StreamStartEvent{Warnings: []api.CallWarning{}},
+		&api.ResponseMetadataEvent{ID: "msg_1", ModelID: "claude-3"},
+		&api.TextStartEvent{ID: "0"},
+		&api.TextDeltaEvent{TextDelta: "Hello"},
+		&api.TextEndEvent{ID: "0"},
+		&api.FinishEvent{
+			FinishReason: api.FinishReasonStop,
+			Usage:        api.Usage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15},
+			ProviderMetadata: api.NewProviderMetadata(map[string]any{
+				"anthropic": &codec.Metadata{Usage: codec.Usage{InputTokens: 10, OutputTokens: 5}},
+			}),
+		},
+	}, events)
+}
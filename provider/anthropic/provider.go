@@ -0,0 +1,69 @@
+package anthropic
+
+import (
+	"github.com/anthropics/anthropic-sdk-go"
+	"go.jetify.com/ai/api"
+)
+
+type Provider struct {
+	// client is the Anthropic client used to make API calls.
+	client anthropic.Client
+	// name is the name of the provider, overrides the default "anthropic".
+	name string
+}
+
+var _ api.Provider = &Provider{}
+
+type ProviderOption func(*Provider)
+
+// WithClient sets the Anthropic client used to make API calls.
+func WithClient(c anthropic.Client) ProviderOption {
+	// TODO: Instead of only supporting an anthropic.Client, we can "flatten"
+	// the options supported by the Anthropic SDK.
+	return func(p *Provider) { p.client = c }
+}
+
+func WithName(name string) ProviderOption {
+	return func(p *Provider) { p.name = name }
+}
+
+func NewProvider(opts ...ProviderOption) api.Provider {
+	return newProvider(opts...)
+}
+
+func newProvider(opts ...ProviderOption) *Provider {
+	p := &Provider{client: anthropic.NewClient()}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.name == "" {
+		p.name = ProviderName
+	}
+
+	return p
+}
+
+// TextEmbeddingModel is not supported by the Anthropic provider.
+func (p *Provider) TextEmbeddingModel(modelID string) (api.EmbeddingModel[string, api.Embedding], error) {
+	return nil, api.NewUnsupportedFunctionalityError(p.name, "TextEmbeddingModel")
+}
+
+// MultimodalEmbeddingModel is not supported by the Anthropic provider.
+func (p *Provider) MultimodalEmbeddingModel(modelID string) (api.EmbeddingModel[api.MultimodalEmbeddingInput, api.Embedding], error) {
+	return nil, api.NewUnsupportedFunctionalityError(p.name, "MultimodalEmbeddingModel")
+}
+
+// SparseEmbeddingModel is not supported by the Anthropic provider.
+func (p *Provider) SparseEmbeddingModel(modelID string) (api.EmbeddingModel[string, api.SparseEmbedding], error) {
+	return nil, api.NewUnsupportedFunctionalityError(p.name, "SparseEmbeddingModel")
+}
+
+// SegmentingModel is not supported by the Anthropic provider.
+func (p *Provider) SegmentingModel(modelID string) (api.SegmentingModel, error) {
+	return nil, api.NewUnsupportedFunctionalityError(p.name, "SegmentingModel")
+}
+
+// RankingModel is not supported by the Anthropic provider.
+func (p *Provider) RankingModel(modelID string) (api.RankingModel, error) {
+	return nil, api.NewUnsupportedFunctionalityError(p.name, "RankingModel")
+}
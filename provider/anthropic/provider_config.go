@@ -0,0 +1,8 @@
+package anthropic
+
+import "github.com/anthropics/anthropic-sdk-go"
+
+type ProviderConfig struct {
+	providerName string
+	client       anthropic.Client
+}
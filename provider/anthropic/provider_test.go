@@ -0,0 +1,57 @@
+package anthropic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+)
+
+func TestProvider_LanguageModel(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []ProviderOption
+		providerName string
+	}{
+		{
+			name:         "default name",
+			providerName: "anthropic",
+		},
+		{
+			name:         "custom name",
+			opts:         []ProviderOption{WithName("claude")},
+			providerName: "claude",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := NewProvider(tt.opts...).LanguageModel(ModelClaudeSonnet4_0)
+			require.NoError(t, err)
+			assert.IsType(t, &LanguageModel{}, model)
+			assert.Equal(t, tt.providerName, model.ProviderName())
+			assert.Equal(t, ModelClaudeSonnet4_0, model.ModelID())
+		})
+	}
+}
+
+func TestProvider_Unsupported(t *testing.T) {
+	p := NewProvider()
+	var unsupported *api.UnsupportedFunctionalityError
+
+	_, err := p.TextEmbeddingModel("model")
+	assert.ErrorAs(t, err, &unsupported)
+
+	_, err = p.MultimodalEmbeddingModel("model")
+	assert.ErrorAs(t, err, &unsupported)
+
+	_, err = p.SparseEmbeddingModel("model")
+	assert.ErrorAs(t, err, &unsupported)
+
+	_, err = p.SegmentingModel("model")
+	assert.ErrorAs(t, err, &unsupported)
+
+	_, err = p.RankingModel("model")
+	assert.ErrorAs(t, err, &unsupported)
+}
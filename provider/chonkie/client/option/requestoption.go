@@ -1,6 +1,9 @@
 package option
 
 import (
+	"net/http"
+	"time"
+
 	"go.jetify.com/ai/provider/internal/requesterx"
 )
 
@@ -10,3 +13,22 @@ import (
 func WithEnvironmentProduction() requesterx.RequestOption {
 	return requesterx.WithDefaultBaseURL("https://api.chonkie.ai/v1/")
 }
+
+// WithHTTPClient returns a RequestOption that changes the HTTP client used to
+// send requests. By default, requests share a client with a pooled transport
+// and no timeout. It can be used to configure timeouts or to record and replay
+// requests in tests.
+func WithHTTPClient(c *http.Client) requesterx.RequestOption {
+	return requesterx.WithHTTPClient(c)
+}
+
+// WithBaseURL returns a RequestOption that sets the base URL of the API.
+func WithBaseURL(baseURL string) requesterx.RequestOption {
+	return requesterx.WithBaseURL(baseURL)
+}
+
+// WithTimeout returns a RequestOption that bounds the time taken by each
+// request. A zero duration means no timeout.
+func WithTimeout(d time.Duration) requesterx.RequestOption {
+	return requesterx.WithTimeout(d)
+}
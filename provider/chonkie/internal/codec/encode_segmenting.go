@@ -28,6 +28,10 @@ func EncodeSegment(text string, opts api.TransportOptions) (chonkie.SegmentReque
 		reqOpts = append(reqOpts, requesterx.WithBaseURL(opts.BaseURL))
 	}
 
+	if opts.Timeout > 0 {
+		reqOpts = append(reqOpts, requesterx.WithTimeout(opts.Timeout))
+	}
+
 	if opts.UseRawBaseURL {
 		reqOpts = append(reqOpts, requesterx.WithUseRawBaseURL())
 	}
@@ -57,6 +61,10 @@ func EncodeSegmentBatch(texts []string, opts api.TransportOptions) (chonkie.Batc
 		reqOpts = append(reqOpts, requesterx.WithBaseURL(opts.BaseURL))
 	}
 
+	if opts.Timeout > 0 {
+		reqOpts = append(reqOpts, requesterx.WithTimeout(opts.Timeout))
+	}
+
 	if opts.UseRawBaseURL {
 		reqOpts = append(reqOpts, requesterx.WithUseRawBaseURL())
 	}
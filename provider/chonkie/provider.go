@@ -1,8 +1,13 @@
 package chonkie
 
 import (
+	"net/http"
+	"slices"
+	"time"
+
 	"go.jetify.com/ai/api"
 	chonkie "go.jetify.com/ai/provider/chonkie/client"
+	"go.jetify.com/ai/provider/internal/requesterx"
 )
 
 type Provider struct {
@@ -14,6 +19,12 @@ type Provider struct {
 
 	// apiKey is the API key used for authentication.
 	apiKey string
+
+	// httpClient, if set, is used to send every request of the provider.
+	httpClient *http.Client
+
+	// httpConfig configures the HTTP client built when httpClient is not set.
+	httpConfig requesterx.HTTPClientConfig
 }
 
 var _ api.Provider = &Provider{}
@@ -32,6 +43,38 @@ func WithAPIKey(apiKey string) ProviderOption {
 	return func(p *Provider) { p.apiKey = apiKey }
 }
 
+// WithHTTPClient sets the HTTP client used to send every request of the
+// provider. It takes precedence over WithTimeout and the connection pool
+// options, and can be used to share one tuned client between providers.
+func WithHTTPClient(c *http.Client) ProviderOption {
+	return func(p *Provider) { p.httpClient = c }
+}
+
+// WithTimeout bounds the time taken by each request of the provider,
+// including reading the response body. Use api.TransportOptions.Timeout to
+// bound a single call instead.
+func WithTimeout(d time.Duration) ProviderOption {
+	return func(p *Provider) { p.httpConfig.Timeout = d }
+}
+
+// WithMaxIdleConns limits the number of idle connections the provider keeps
+// open across all hosts.
+func WithMaxIdleConns(n int) ProviderOption {
+	return func(p *Provider) { p.httpConfig.MaxIdleConns = n }
+}
+
+// WithMaxIdleConnsPerHost limits the number of idle connections the provider
+// keeps open to each host.
+func WithMaxIdleConnsPerHost(n int) ProviderOption {
+	return func(p *Provider) { p.httpConfig.MaxIdleConnsPerHost = n }
+}
+
+// WithIdleConnTimeout sets how long an idle connection is kept open before
+// it is closed.
+func WithIdleConnTimeout(d time.Duration) ProviderOption {
+	return func(p *Provider) { p.httpConfig.IdleConnTimeout = d }
+}
+
 func NewProvider(opts ...ProviderOption) api.Provider {
 	p := &Provider{client: chonkie.NewClient()}
 
@@ -39,6 +82,13 @@ func NewProvider(opts ...ProviderOption) api.Provider {
 		opt(p)
 	}
 
+	if p.httpClient == nil && !p.httpConfig.IsZero() {
+		p.httpClient = requesterx.NewHTTPClient(p.httpConfig)
+	}
+	if p.httpClient != nil {
+		p.client = chonkie.NewClient(append(slices.Clone(p.client.Options), requesterx.WithHTTPClient(p.httpClient))...)
+	}
+
 	if p.name == "" {
 		p.name = "chonkie"
 	}
@@ -0,0 +1,41 @@
+package cohere
+
+import (
+	"os"
+
+	"go.jetify.com/ai/provider/cohere/client/option"
+	"go.jetify.com/ai/provider/internal/requesterx"
+)
+
+type Client struct {
+	Options    []requesterx.RequestOption
+	Embeddings EmbeddingService
+	Ranking    RankingService
+}
+
+// DefaultClientOptions read from the environment (COHERE_BASE_URL, and
+// COHERE_API_KEY or CO_API_KEY). This should be used to initialize new clients.
+func DefaultClientOptions() []requesterx.RequestOption {
+	defaults := []requesterx.RequestOption{
+		option.WithEnvironmentProduction(),
+		requesterx.WithErrorDecoder(decodeError),
+	}
+	if o, ok := os.LookupEnv("COHERE_BASE_URL"); ok {
+		defaults = append(defaults, requesterx.WithBaseURL(o))
+	}
+	if o, ok := os.LookupEnv("COHERE_API_KEY"); ok {
+		defaults = append(defaults, requesterx.WithAPIKey(o))
+	} else if o, ok := os.LookupEnv("CO_API_KEY"); ok {
+		defaults = append(defaults, requesterx.WithAPIKey(o))
+	}
+	return defaults
+}
+
+func NewClient(opts ...requesterx.RequestOption) (r Client) {
+	opts = append(DefaultClientOptions(), opts...)
+
+	r = Client{Options: opts}
+	r.Embeddings = NewEmbeddingService(opts...)
+	r.Ranking = NewRankingService(opts...)
+	return r
+}
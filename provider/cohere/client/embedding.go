@@ -0,0 +1,217 @@
+package cohere
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.jetify.com/ai/provider/internal/requesterx"
+)
+
+// EmbeddingService contains methods and other services that help with interacting
+// with the embed API.
+//
+// Note, unlike clients, this service does not read variables from the environment
+// automatically. You should not instantiate this service directly, and instead use
+// the [NewEmbeddingService] method instead.
+type EmbeddingService struct {
+	Options []requesterx.RequestOption
+}
+
+type EmbeddingModel = string
+
+const (
+	// v4: Multimodal multilingual embeddings with configurable dimensions
+	EmbeddingModelEmbedV4 EmbeddingModel = "embed-v4.0"
+
+	// English text and image embeddings (1024 dimensions)
+	EmbeddingModelEmbedEnglishV3 EmbeddingModel = "embed-english-v3.0"
+
+	// Faster English text and image embeddings (384 dimensions)
+	EmbeddingModelEmbedEnglishLightV3 EmbeddingModel = "embed-english-light-v3.0"
+
+	// Multilingual text and image embeddings (1024 dimensions)
+	EmbeddingModelEmbedMultilingualV3 EmbeddingModel = "embed-multilingual-v3.0"
+
+	// Faster multilingual text and image embeddings (384 dimensions)
+	EmbeddingModelEmbedMultilingualLightV3 EmbeddingModel = "embed-multilingual-light-v3.0"
+)
+
+// InputType tells the model what the embeddings will be used for. It is
+// required by v3 and later models.
+type InputType string
+
+const (
+	// Embeddings of documents stored in a vector database for search.
+	InputTypeSearchDocument InputType = "search_document"
+	// Embeddings of search queries run against a vector database.
+	InputTypeSearchQuery InputType = "search_query"
+	// Embeddings passed through a text classifier.
+	InputTypeClassification InputType = "classification"
+	// Embeddings run through a clustering algorithm.
+	InputTypeClustering InputType = "clustering"
+	// Embeddings of images.
+	InputTypeImage InputType = "image"
+)
+
+// EmbeddingType is the numeric format of the returned embeddings.
+type EmbeddingType string
+
+const (
+	// Floating point embeddings.
+	EmbeddingTypeFloat EmbeddingType = "float"
+	// Signed int8 embeddings, with values between -128 and 127.
+	EmbeddingTypeInt8 EmbeddingType = "int8"
+	// Unsigned int8 embeddings, with values between 0 and 255.
+	EmbeddingTypeUint8 EmbeddingType = "uint8"
+	// Signed binary embeddings packed in int8 values, eight dimensions per value.
+	EmbeddingTypeBinary EmbeddingType = "binary"
+	// Unsigned binary embeddings packed in uint8 values, eight dimensions per value.
+	EmbeddingTypeUbinary EmbeddingType = "ubinary"
+)
+
+// Truncate specifies how inputs longer than the maximum token length are
+// handled: "NONE" returns an error, "START" and "END" discard the start or
+// the end of the input.
+type Truncate string
+
+const (
+	TruncateNone  Truncate = "NONE"
+	TruncateStart Truncate = "START"
+	TruncateEnd   Truncate = "END"
+)
+
+// EmbedRequest models the POST body for the Cohere embed API. Exactly one of
+// Texts, Images and Inputs must be set.
+type EmbedRequest struct {
+	// ID of the model to use.
+	Model EmbeddingModel `json:"model"`
+	// Texts to embed. At most 96 texts can be sent per request.
+	Texts []string `json:"texts,omitempty"`
+	// Images to embed, as data URIs. At most one image can be sent per
+	// request; use Inputs to embed several.
+	Images []string `json:"images,omitempty"`
+	// Inputs to embed, each mixing text and image content.
+	Inputs []EmbedInput `json:"inputs,omitempty"`
+	// InputType tells the model what the embeddings will be used for.
+	InputType InputType `json:"input_type,omitempty"`
+	// EmbeddingTypes are the formats of the returned embeddings. Defaults to float.
+	EmbeddingTypes []EmbeddingType `json:"embedding_types,omitempty"`
+	// OutputDimension is the number of dimensions of the returned embeddings.
+	// Only supported by embed-v4.0 and later models.
+	OutputDimension *int `json:"output_dimension,omitempty"`
+	// Truncate specifies how inputs longer than the maximum token length are handled.
+	Truncate *Truncate `json:"truncate,omitempty"`
+}
+
+// EmbedInput is an input of the embed API mixing text and image content.
+type EmbedInput struct {
+	Content []EmbedContent `json:"content"`
+}
+
+// EmbedContent is a text or image component of an EmbedInput.
+type EmbedContent struct {
+	// Type is either "text" or "image_url".
+	Type     string         `json:"type"`
+	Text     *string        `json:"text,omitempty"`
+	ImageURL *EmbedImageURL `json:"image_url,omitempty"`
+}
+
+// EmbedImageURL references an image by URL or data URI.
+type EmbedImageURL struct {
+	URL string `json:"url"`
+}
+
+// EmbeddingNewParams allows callers to pass provider metadata to tweak
+// embedding behavior for Cohere.
+type EmbeddingNewParams struct {
+	// InputType tells the model what the embeddings will be used for.
+	// Defaults to "search_document".
+	InputType *InputType `json:"input_type,omitempty"`
+	// EmbeddingTypes holds the format of the returned embeddings, which are
+	// returned as api.Embedding values. At most one type can be requested;
+	// defaults to float.
+	EmbeddingTypes []EmbeddingType `json:"embedding_types,omitempty"`
+	// OutputDimension is the number of dimensions of the returned embeddings.
+	OutputDimension *int `json:"output_dimension,omitempty"`
+	// Truncate specifies how inputs longer than the maximum token length are handled.
+	Truncate *Truncate `json:"truncate,omitempty"`
+}
+
+// EmbedResponse represents the response from the Cohere embed API.
+type EmbedResponse struct {
+	// ID identifies the request.
+	ID string `json:"id"`
+	// Embeddings holds one list of embeddings per requested embedding type.
+	Embeddings EmbeddingsByType `json:"embeddings"`
+	// Texts are the embedded texts.
+	Texts []string `json:"texts,omitempty"`
+	// Meta contains the billed units of the request.
+	Meta Meta `json:"meta"`
+}
+
+// EmbeddingsByType holds the embeddings of each requested type, in input order.
+type EmbeddingsByType struct {
+	Float   [][]float64 `json:"float,omitempty"`
+	Int8    [][]int     `json:"int8,omitempty"`
+	Uint8   [][]int     `json:"uint8,omitempty"`
+	Binary  [][]int     `json:"binary,omitempty"`
+	Ubinary [][]int     `json:"ubinary,omitempty"`
+}
+
+type embedRequestConcrete struct {
+	EmbedRequest
+}
+
+func (p embedRequestConcrete) validate() error {
+	if p.Model == "" {
+		return fmt.Errorf("model is required")
+	}
+	set := 0
+	for _, n := range []int{len(p.Texts), len(p.Images), len(p.Inputs)} {
+		if n > 0 {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of texts, images or inputs must be non-empty")
+	}
+	for i, s := range p.Texts {
+		if s == "" {
+			return fmt.Errorf("texts[%d]: empty string", i)
+		}
+	}
+	for i, in := range p.Inputs {
+		if len(in.Content) == 0 {
+			return fmt.Errorf("inputs[%d]: content must be non-empty", i)
+		}
+	}
+	return nil
+}
+
+// NewEmbeddingService generates a new service that applies the given options to
+// each request. These options are applied after the parent client's options (if
+// there is one), and before any request-specific options.
+func NewEmbeddingService(opts ...requesterx.RequestOption) (r EmbeddingService) {
+	r = EmbeddingService{}
+	r.Options = opts
+	return r
+}
+
+// New creates embeddings for the texts, images or inputs of the request.
+func (r *EmbeddingService) New(ctx context.Context, body EmbedRequest, opts ...requesterx.RequestOption) (res *EmbedResponse, err error) {
+	req := embedRequestConcrete{
+		EmbedRequest: body,
+	}
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	all := append([]requesterx.RequestOption{}, r.Options...)
+	all = append(all, opts...)
+	path := "embed"
+	err = requesterx.ExecuteNewRequest(ctx, http.MethodPost, path, body, &res, all...)
+	if err != nil {
+		return nil, fmt.Errorf("cohere embed: %w", err)
+	}
+	return res, nil
+}
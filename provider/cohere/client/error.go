@@ -0,0 +1,22 @@
+package cohere
+
+import "encoding/json"
+
+// ErrorResponse represents the body of a failed Cohere request.
+type ErrorResponse struct {
+	// ID identifies the failed request
+	ID string `json:"id,omitempty"`
+	// Message is the error message
+	Message string `json:"message"`
+}
+
+// decodeError decodes a Cohere error body. It is used as the client's
+// requesterx.ErrorDecoder so that the returned api.APICallError carries an
+// *ErrorResponse as its Data.
+func decodeError(body []byte) (string, any, bool) {
+	var res ErrorResponse
+	if err := json.Unmarshal(body, &res); err != nil || res.Message == "" {
+		return "", nil, false
+	}
+	return res.Message, &res, true
+}
@@ -0,0 +1,21 @@
+package cohere
+
+// Meta contains information about a request returned with every response.
+type Meta struct {
+	// BilledUnits are the units the request was billed for.
+	BilledUnits BilledUnits `json:"billed_units"`
+	// Warnings returned by the API, e.g. about deprecated models.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// BilledUnits are the units a request was billed for.
+type BilledUnits struct {
+	// The number of input tokens billed.
+	InputTokens int64 `json:"input_tokens,omitempty"`
+	// The number of output tokens billed.
+	OutputTokens int64 `json:"output_tokens,omitempty"`
+	// The number of images billed.
+	Images int64 `json:"images,omitempty"`
+	// The number of billed search units, used by rerank requests.
+	SearchUnits int64 `json:"search_units,omitempty"`
+}
@@ -0,0 +1,122 @@
+package cohere
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.jetify.com/ai/provider/internal/requesterx"
+)
+
+// RankingService contains methods for reranking documents with the Cohere
+// rerank API.
+//
+// Note, unlike clients, this service does not read variables from the environment
+// automatically. You should not instantiate this service directly, and instead use
+// the [NewRankingService] method instead.
+type RankingService struct {
+	Options []requesterx.RequestOption
+}
+
+type RankingModel = string
+
+const (
+	// v3.5: Multilingual reranker with reasoning over semi-structured data
+	RankingModelRerankV35 RankingModel = "rerank-v3.5"
+
+	// English reranker
+	RankingModelRerankEnglishV3 RankingModel = "rerank-english-v3.0"
+
+	// Multilingual reranker (100+ languages)
+	RankingModelRerankMultilingualV3 RankingModel = "rerank-multilingual-v3.0"
+)
+
+// RankRequest models the POST body for the Cohere rerank API.
+type RankRequest struct {
+	// ID of the model to use.
+	Model RankingModel `json:"model"`
+	// Query to rank the documents against.
+	Query string `json:"query"`
+	// Documents to rank. At most 1000 documents can be sent per request.
+	Documents []string `json:"documents"`
+	// TopN limits the results to the N most relevant documents. All documents
+	// are returned if it is not set.
+	TopN *int `json:"top_n,omitempty"`
+	// MaxTokensPerDoc truncates long documents to the given number of tokens.
+	MaxTokensPerDoc *int `json:"max_tokens_per_doc,omitempty"`
+}
+
+// RankingNewParams allows callers to pass provider metadata to tweak
+// ranking behavior for Cohere.
+type RankingNewParams struct {
+	// TopN limits the results to the N most relevant documents.
+	TopN *int `json:"top_n,omitempty"`
+	// MaxTokensPerDoc truncates long documents to the given number of tokens.
+	MaxTokensPerDoc *int `json:"max_tokens_per_doc,omitempty"`
+}
+
+// RankResponse represents the response from the Cohere rerank API.
+type RankResponse struct {
+	// ID identifies the request.
+	ID string `json:"id"`
+	// The ranked documents, sorted by descending relevance score.
+	Results []RankResult `json:"results"`
+	// Meta contains the billed units of the request.
+	Meta Meta `json:"meta"`
+}
+
+// RankResult represents a single ranked document.
+type RankResult struct {
+	// Index is the position of the document in the request.
+	Index int `json:"index"`
+	// RelevanceScore is the relevance of the document to the query, between 0 and 1.
+	RelevanceScore float64 `json:"relevance_score"`
+}
+
+type rankRequestConcrete struct {
+	RankRequest
+}
+
+func (p rankRequestConcrete) validate() error {
+	if p.Model == "" {
+		return fmt.Errorf("model is required")
+	}
+	if p.Query == "" {
+		return fmt.Errorf("query is required")
+	}
+	if len(p.Documents) == 0 {
+		return fmt.Errorf("documents: []string must be non-empty")
+	}
+	if p.TopN != nil && *p.TopN <= 0 {
+		return fmt.Errorf("top_n must be positive")
+	}
+	return nil
+}
+
+// NewRankingService generates a new service that applies the given options to
+// each request. These options are applied after the parent client's options (if
+// there is one), and before any request-specific options.
+func NewRankingService(opts ...requesterx.RequestOption) (r RankingService) {
+	r = RankingService{}
+	r.Options = opts
+	return r
+}
+
+// Rank reorders the given documents based on their relevance to the query.
+// Returns documents sorted by relevance score in descending order.
+func (r *RankingService) Rank(ctx context.Context, body RankRequest, opts ...requesterx.RequestOption) (res *RankResponse, err error) {
+	req := rankRequestConcrete{
+		RankRequest: body,
+	}
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	all := append([]requesterx.RequestOption{}, r.Options...)
+	all = append(all, opts...)
+	path := "rerank"
+	err = requesterx.ExecuteNewRequest(ctx, http.MethodPost, path, body, &res, all...)
+	if err != nil {
+		return nil, fmt.Errorf("cohere rerank: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,80 @@
+package cohere
+
+import (
+	"context"
+	"fmt"
+
+	"go.jetify.com/ai/api"
+	"go.jetify.com/ai/provider/cohere/internal/codec"
+)
+
+// EmbeddingModel represents a Cohere text embedding model.
+type EmbeddingModel struct {
+	modelID string
+	pc      ProviderConfig
+}
+
+var _ api.EmbeddingModel[string, api.Embedding] = &EmbeddingModel{}
+
+// TextEmbeddingModel creates a new Cohere text embedding model. Texts are
+// embedded as "search_document" unless another input type is set with the
+// "cohere" provider metadata (see cohere.EmbeddingNewParams).
+func (p *Provider) TextEmbeddingModel(modelID string) (api.EmbeddingModel[string, api.Embedding], error) {
+	// Create model with provider's client
+	model := &EmbeddingModel{
+		modelID: modelID,
+		pc: ProviderConfig{
+			providerName: fmt.Sprintf("%s.embedding", p.name),
+			client:       p.client,
+			apiKey:       p.apiKey,
+		},
+	}
+
+	return model, nil
+}
+
+func (m *EmbeddingModel) ProviderName() string {
+	return m.pc.providerName
+}
+
+func (m *EmbeddingModel) SpecificationVersion() string {
+	return "v2"
+}
+
+func (m *EmbeddingModel) ModelID() string {
+	return m.modelID
+}
+
+// SupportsParallelCalls implements api.EmbeddingModel.
+func (m *EmbeddingModel) SupportsParallelCalls() bool {
+	return true
+}
+
+// MaxEmbeddingsPerCall implements api.EmbeddingModel.
+func (m *EmbeddingModel) MaxEmbeddingsPerCall() *int {
+	max := 96
+	return &max
+}
+
+// DoEmbed implements api.EmbeddingModel.
+func (m *EmbeddingModel) DoEmbed(
+	ctx context.Context,
+	values []string,
+	opts api.TransportOptions,
+) (api.DenseEmbeddingResponse, error) {
+	embeddingParams, cohereOpts, _, err := codec.EncodeEmbedding(
+		m.modelID,
+		values,
+		opts,
+	)
+	if err != nil {
+		return api.DenseEmbeddingResponse{}, err
+	}
+
+	resp, err := m.pc.client.Embeddings.New(ctx, embeddingParams, cohereOpts...)
+	if err != nil {
+		return api.DenseEmbeddingResponse{}, err
+	}
+
+	return codec.DecodeEmbedding(resp, codec.EmbeddingType(embeddingParams))
+}
@@ -0,0 +1,84 @@
+package cohere
+
+import (
+	"context"
+	"fmt"
+
+	"go.jetify.com/ai/api"
+	"go.jetify.com/ai/provider/cohere/internal/codec"
+)
+
+// MultimodalEmbeddingModel represents a Cohere embedding model for text and
+// images.
+type MultimodalEmbeddingModel struct {
+	modelID string
+	pc      ProviderConfig
+}
+
+var _ api.EmbeddingModel[api.MultimodalEmbeddingInput, api.Embedding] = &MultimodalEmbeddingModel{}
+
+// MultimodalEmbeddingModel creates a new Cohere multimodal embedding model.
+// Images must be data URIs or raw base64 data: Cohere does not fetch images,
+// so http(s) URLs are rejected with an [api.InvalidArgumentError]. Every input
+// is sent in the "inputs" field with the "search_document" input type unless
+// the provider metadata sets another one. Inputs that mix text and images
+// require embed-v4.0 or later.
+func (p *Provider) MultimodalEmbeddingModel(modelID string) (api.EmbeddingModel[api.MultimodalEmbeddingInput, api.Embedding], error) {
+	// Create model with provider's client
+	model := &MultimodalEmbeddingModel{
+		modelID: modelID,
+		pc: ProviderConfig{
+			providerName: fmt.Sprintf("%s.embedding", p.name),
+			client:       p.client,
+			apiKey:       p.apiKey,
+		},
+	}
+
+	return model, nil
+}
+
+func (m *MultimodalEmbeddingModel) ProviderName() string {
+	return m.pc.providerName
+}
+
+func (m *MultimodalEmbeddingModel) SpecificationVersion() string {
+	return "v2"
+}
+
+func (m *MultimodalEmbeddingModel) ModelID() string {
+	return m.modelID
+}
+
+// SupportsParallelCalls implements api.EmbeddingModel.
+func (m *MultimodalEmbeddingModel) SupportsParallelCalls() bool {
+	return true
+}
+
+// MaxEmbeddingsPerCall implements api.EmbeddingModel.
+func (m *MultimodalEmbeddingModel) MaxEmbeddingsPerCall() *int {
+	max := 96
+	return &max
+}
+
+// DoEmbed implements api.EmbeddingModel.
+func (m *MultimodalEmbeddingModel) DoEmbed(
+	ctx context.Context,
+	values []api.MultimodalEmbeddingInput,
+	opts api.TransportOptions,
+) (api.DenseEmbeddingResponse, error) {
+	embeddingParams, cohereOpts, _, err := codec.EncodeMultimodalEmbedding(
+		m.modelID,
+		values,
+		opts,
+	)
+	if err != nil {
+		return api.DenseEmbeddingResponse{}, err
+	}
+
+	resp, err := m.pc.client.Embeddings.New(ctx, embeddingParams, cohereOpts...)
+	if err != nil {
+		return api.DenseEmbeddingResponse{}, err
+	}
+
+	return codec.DecodeEmbedding(resp, codec.EmbeddingType(embeddingParams))
+}
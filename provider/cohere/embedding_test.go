@@ -0,0 +1,251 @@
+package cohere
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+	cohereClient "go.jetify.com/ai/provider/cohere/client"
+	"go.jetify.com/ai/provider/internal/requesterx"
+	"go.jetify.com/pkg/httpmock"
+)
+
+// pngBase64 is the base64-encoded PNG file signature.
+const pngBase64 = "iVBORw0KGgo="
+
+func TestDoEmbed(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        []string
+		options      api.TransportOptions
+		exchanges    []httpmock.Exchange
+		wantErr      bool
+		invalidArg   bool
+		expectedResp api.DenseEmbeddingResponse
+	}{
+		{
+			name:  "float embeddings of documents by default",
+			input: []string{"Hello", "World"},
+			exchanges: []httpmock.Exchange{
+				{
+					Request: httpmock.Request{
+						Method:  http.MethodPost,
+						Path:    "/embed",
+						Headers: map[string]string{"Authorization": "Bearer test-key"},
+						Body:    `{"model":"embed-v4.0","texts":["Hello","World"],"input_type":"search_document"}`,
+					},
+					Response: httpmock.Response{
+						StatusCode: http.StatusOK,
+						Body:       `{"id":"emb-1","embeddings":{"float":[[0.1,0.2],[0.3,0.4]]},"texts":["Hello","World"],"meta":{"billed_units":{"input_tokens":2}}}`,
+					},
+				},
+			},
+			expectedResp: api.DenseEmbeddingResponse{
+				Embeddings:  []api.Embedding{{0.1, 0.2}, {0.3, 0.4}},
+				Usage:       &api.EmbeddingUsage{PromptTokens: 2, TotalTokens: 2},
+				RawResponse: &api.EmbeddingRawResponse{Headers: http.Header{}},
+			},
+		},
+		{
+			name:  "query input type and int8 embeddings via provider metadata",
+			input: []string{"Hello"},
+			options: api.TransportOptions{
+				ProviderMetadata: api.NewProviderMetadata(map[string]any{
+					"cohere": &cohereClient.EmbeddingNewParams{
+						InputType:       ptr(cohereClient.InputTypeSearchQuery),
+						EmbeddingTypes:  []cohereClient.EmbeddingType{cohereClient.EmbeddingTypeInt8},
+						OutputDimension: ptr(256),
+						Truncate:        ptr(cohereClient.TruncateEnd),
+					},
+				}),
+			},
+			exchanges: []httpmock.Exchange{
+				{
+					Request: httpmock.Request{
+						Method: http.MethodPost,
+						Path:   "/embed",
+						Body:   `{"model":"embed-v4.0","texts":["Hello"],"input_type":"search_query","embedding_types":["int8"],"output_dimension":256,"truncate":"END"}`,
+					},
+					Response: httpmock.Response{
+						StatusCode: http.StatusOK,
+						Body:       `{"id":"emb-2","embeddings":{"int8":[[-12,127]]},"meta":{"billed_units":{"input_tokens":1}}}`,
+					},
+				},
+			},
+			expectedResp: api.DenseEmbeddingResponse{
+				Embeddings:  []api.Embedding{{-12, 127}},
+				Usage:       &api.EmbeddingUsage{PromptTokens: 1, TotalTokens: 1},
+				RawResponse: &api.EmbeddingRawResponse{Headers: http.Header{}},
+			},
+		},
+		{
+			name:  "unsupported embedding type",
+			input: []string{"Hello"},
+			options: api.TransportOptions{
+				ProviderMetadata: api.NewProviderMetadata(map[string]any{
+					"cohere": &cohereClient.EmbeddingNewParams{
+						EmbeddingTypes: []cohereClient.EmbeddingType{"base64"},
+					},
+				}),
+			},
+			wantErr:    true,
+			invalidArg: true,
+		},
+		{
+			name:  "several embedding types",
+			input: []string{"Hello"},
+			options: api.TransportOptions{
+				ProviderMetadata: api.NewProviderMetadata(map[string]any{
+					"cohere": &cohereClient.EmbeddingNewParams{
+						EmbeddingTypes: []cohereClient.EmbeddingType{cohereClient.EmbeddingTypeInt8, cohereClient.EmbeddingTypeFloat},
+					},
+				}),
+			},
+			wantErr:    true,
+			invalidArg: true,
+		},
+		{
+			name:    "empty input",
+			input:   []string{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httpmock.NewServer(t, tt.exchanges)
+			defer server.Close()
+
+			client := cohereClient.NewClient(requesterx.WithBaseURL(server.BaseURL()))
+			provider := NewProvider(WithClient(client), WithAPIKey("test-key"))
+			model, err := provider.TextEmbeddingModel(cohereClient.EmbeddingModelEmbedV4)
+			require.NoError(t, err)
+
+			resp, err := model.DoEmbed(t.Context(), tt.input, tt.options)
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.invalidArg {
+					var invalidArg *api.InvalidArgumentError
+					require.ErrorAs(t, err, &invalidArg)
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedResp, resp)
+		})
+	}
+}
+
+func TestDoEmbed_Multimodal(t *testing.T) {
+	text := "A red square"
+	image := pngBase64
+	dataURI := "data:image/jpeg;base64,/9j/4AAQ"
+
+	tests := []struct {
+		name      string
+		input     []api.MultimodalEmbeddingInput
+		exchanges []httpmock.Exchange
+		wantErr   bool
+		want      []api.Embedding
+	}{
+		{
+			name:  "images only",
+			input: []api.MultimodalEmbeddingInput{{Image: &image}, {Image: &dataURI}},
+			exchanges: []httpmock.Exchange{
+				{
+					Request: httpmock.Request{
+						Method: http.MethodPost,
+						Path:   "/embed",
+						Body: `{"model":"embed-v4.0","inputs":[` +
+							`{"content":[{"type":"image_url","image_url":{"url":"data:image/png;base64,` + pngBase64 + `"}}]},` +
+							`{"content":[{"type":"image_url","image_url":{"url":"data:image/jpeg;base64,/9j/4AAQ"}}]}` +
+							`],"input_type":"search_document"}`,
+					},
+					Response: httpmock.Response{
+						StatusCode: http.StatusOK,
+						Body:       `{"id":"emb-3","embeddings":{"float":[[1,0],[0,1]]},"meta":{"billed_units":{"images":2}}}`,
+					},
+				},
+			},
+			want: []api.Embedding{{1, 0}, {0, 1}},
+		},
+		{
+			name:  "text and images",
+			input: []api.MultimodalEmbeddingInput{{Text: &text}, {Image: &dataURI}},
+			exchanges: []httpmock.Exchange{
+				{
+					Request: httpmock.Request{
+						Method: http.MethodPost,
+						Path:   "/embed",
+						Body: `{"model":"embed-v4.0","inputs":[` +
+							`{"content":[{"type":"text","text":"A red square"}]},` +
+							`{"content":[{"type":"image_url","image_url":{"url":"data:image/jpeg;base64,/9j/4AAQ"}}]}` +
+							`],"input_type":"search_document"}`,
+					},
+					Response: httpmock.Response{
+						StatusCode: http.StatusOK,
+						Body:       `{"id":"emb-4","embeddings":{"float":[[0.2],[0.8]]},"meta":{"billed_units":{"input_tokens":4,"images":1}}}`,
+					},
+				},
+			},
+			want: []api.Embedding{{0.2}, {0.8}},
+		},
+		{
+			name:    "image URL",
+			input:   []api.MultimodalEmbeddingInput{{Image: ptr("https://example.com/cat.png")}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid image",
+			input:   []api.MultimodalEmbeddingInput{{Image: ptr("not base64!")}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httpmock.NewServer(t, tt.exchanges)
+			defer server.Close()
+
+			client := cohereClient.NewClient(requesterx.WithBaseURL(server.BaseURL()))
+			model, err := NewProvider(WithClient(client)).MultimodalEmbeddingModel(cohereClient.EmbeddingModelEmbedV4)
+			require.NoError(t, err)
+
+			resp, err := model.DoEmbed(t.Context(), tt.input, api.TransportOptions{})
+			if tt.wantErr {
+				var invalidArg *api.InvalidArgumentError
+				require.ErrorAs(t, err, &invalidArg)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, resp.Embeddings)
+		})
+	}
+}
+
+func TestDoEmbed_APICallError(t *testing.T) {
+	server := httpmock.NewServer(t, []httpmock.Exchange{{
+		Request: httpmock.Request{Method: http.MethodPost, Path: "/embed"},
+		Response: httpmock.Response{
+			StatusCode: http.StatusBadRequest,
+			Headers:    map[string]string{"Content-Type": "application/json"},
+			Body:       `{"id":"req-1","message":"invalid request: input_type is required"}`,
+		},
+	}})
+	defer server.Close()
+
+	client := cohereClient.NewClient(requesterx.WithBaseURL(server.BaseURL()))
+	model, err := NewProvider(WithClient(client)).TextEmbeddingModel(cohereClient.EmbeddingModelEmbedV4)
+	require.NoError(t, err)
+
+	_, err = model.DoEmbed(t.Context(), []string{"Hello"}, api.TransportOptions{})
+
+	var apiErr *api.APICallError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	require.Equal(t, "400 Bad Request: invalid request: input_type is required", apiErr.Error())
+	require.Equal(t, &cohereClient.ErrorResponse{ID: "req-1", Message: "invalid request: input_type is required"}, apiErr.Data)
+}
+
+func ptr[T any](v T) *T { return &v }
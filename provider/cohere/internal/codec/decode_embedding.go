@@ -0,0 +1,65 @@
+package codec
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.jetify.com/ai/api"
+	cohere "go.jetify.com/ai/provider/cohere/client"
+)
+
+// DecodeEmbedding maps the Cohere embed API response to the unified
+// api.EmbeddingResponse. The embeddings of the given type are returned;
+// integer and binary embeddings are converted to float64 values, with binary
+// embeddings kept packed eight dimensions per value.
+func DecodeEmbedding(resp *cohere.EmbedResponse, embeddingType cohere.EmbeddingType) (api.DenseEmbeddingResponse, error) {
+	if resp == nil {
+		return api.DenseEmbeddingResponse{}, api.NewEmptyResponseBodyError("response from Cohere embed API is nil")
+	}
+
+	var embs []api.Embedding
+	switch embeddingType {
+	case cohere.EmbeddingTypeFloat:
+		embs = make([]api.Embedding, len(resp.Embeddings.Float))
+		for i, e := range resp.Embeddings.Float {
+			vec := make([]float64, len(e))
+			copy(vec, e)
+			embs[i] = vec
+		}
+	case cohere.EmbeddingTypeInt8:
+		embs = intEmbeddings(resp.Embeddings.Int8)
+	case cohere.EmbeddingTypeUint8:
+		embs = intEmbeddings(resp.Embeddings.Uint8)
+	case cohere.EmbeddingTypeBinary:
+		embs = intEmbeddings(resp.Embeddings.Binary)
+	case cohere.EmbeddingTypeUbinary:
+		embs = intEmbeddings(resp.Embeddings.Ubinary)
+	default:
+		return api.DenseEmbeddingResponse{}, fmt.Errorf("cohere/embed: unsupported embedding type %q", embeddingType)
+	}
+
+	usage := &api.EmbeddingUsage{
+		PromptTokens: resp.Meta.BilledUnits.InputTokens,
+		TotalTokens:  resp.Meta.BilledUnits.InputTokens,
+	}
+
+	return api.DenseEmbeddingResponse{
+		Embeddings: embs,
+		Usage:      usage,
+		RawResponse: &api.EmbeddingRawResponse{
+			Headers: http.Header{},
+		},
+	}, nil
+}
+
+func intEmbeddings(values [][]int) []api.Embedding {
+	embs := make([]api.Embedding, len(values))
+	for i, e := range values {
+		vec := make([]float64, len(e))
+		for j, v := range e {
+			vec[j] = float64(v)
+		}
+		embs[i] = vec
+	}
+	return embs
+}
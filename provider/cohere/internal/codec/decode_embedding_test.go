@@ -0,0 +1,68 @@
+package codec
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+	cohere "go.jetify.com/ai/provider/cohere/client"
+)
+
+func TestDecodeEmbedding(t *testing.T) {
+	resp := &cohere.EmbedResponse{
+		Embeddings: cohere.EmbeddingsByType{
+			Float:   [][]float64{{0.5, -0.5}},
+			Int8:    [][]int{{-128, 127}},
+			Uint8:   [][]int{{0, 255}},
+			Binary:  [][]int{{-1}},
+			Ubinary: [][]int{{170}},
+		},
+		Meta: cohere.Meta{BilledUnits: cohere.BilledUnits{InputTokens: 3}},
+	}
+
+	tests := []struct {
+		name          string
+		in            *cohere.EmbedResponse
+		embeddingType cohere.EmbeddingType
+		want          []api.Embedding
+		wantErrSub    string
+	}{
+		{
+			name:       "nil response -> error",
+			in:         nil,
+			wantErrSub: "response from Cohere embed API is nil",
+		},
+		{name: "float", in: resp, embeddingType: cohere.EmbeddingTypeFloat, want: []api.Embedding{{0.5, -0.5}}},
+		{name: "int8", in: resp, embeddingType: cohere.EmbeddingTypeInt8, want: []api.Embedding{{-128, 127}}},
+		{name: "uint8", in: resp, embeddingType: cohere.EmbeddingTypeUint8, want: []api.Embedding{{0, 255}}},
+		{name: "binary", in: resp, embeddingType: cohere.EmbeddingTypeBinary, want: []api.Embedding{{-1}}},
+		{name: "ubinary", in: resp, embeddingType: cohere.EmbeddingTypeUbinary, want: []api.Embedding{{170}}},
+		{
+			name:          "unsupported type",
+			in:            resp,
+			embeddingType: "base64",
+			wantErrSub:    `unsupported embedding type "base64"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeEmbedding(tt.in, tt.embeddingType)
+
+			if tt.wantErrSub != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrSub)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, api.DenseEmbeddingResponse{
+				Embeddings:  tt.want,
+				Usage:       &api.EmbeddingUsage{PromptTokens: 3, TotalTokens: 3},
+				RawResponse: &api.EmbeddingRawResponse{Headers: http.Header{}},
+			}, got)
+		})
+	}
+}
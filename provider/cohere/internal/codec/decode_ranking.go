@@ -0,0 +1,35 @@
+package codec
+
+import (
+	"go.jetify.com/ai/api"
+	cohere "go.jetify.com/ai/provider/cohere/client"
+)
+
+// DecodeRank maps the Cohere rerank API response to the unified api.RankingResponse.
+// Cohere returns the results sorted by relevance, possibly truncated to the top N,
+// so the scores of the numTexts inputs are restored from the result indices.
+func DecodeRank(resp *cohere.RankResponse, numTexts int) (api.RankingResponse, error) {
+	if resp == nil {
+		return api.RankingResponse{}, api.NewEmptyResponseBodyError("response from Cohere rerank API is nil")
+	}
+
+	scores := make([]float64, numTexts)
+	results := make([]api.RankingResult, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		if r.Index < 0 || r.Index >= numTexts {
+			continue
+		}
+		scores[r.Index] = r.RelevanceScore
+		results = append(results, api.RankingResult{Index: r.Index, Score: r.RelevanceScore})
+	}
+	api.SortRankingResults(results)
+
+	return api.RankingResponse{
+		Scores:  scores,
+		Results: results,
+		Usage: &api.RankingUsage{
+			SearchUnits: resp.Meta.BilledUnits.SearchUnits,
+		},
+		RequestID: resp.ID,
+	}, nil
+}
@@ -0,0 +1,174 @@
+package codec
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.jetify.com/ai/api"
+	cohere "go.jetify.com/ai/provider/cohere/client"
+	"go.jetify.com/ai/provider/internal/requesterx"
+)
+
+// EncodeEmbedding builds Cohere embed params + request options from the unified API options.
+func EncodeEmbedding(
+	modelID string,
+	values []string,
+	opts api.TransportOptions,
+) (cohere.EmbedRequest, []requesterx.RequestOption, []api.CallWarning, error) {
+	params := cohere.EmbedRequest{
+		Model:     cohere.EmbeddingModel(modelID),
+		Texts:     values,
+		InputType: cohere.InputTypeSearchDocument,
+	}
+
+	if err := applyEmbeddingMetadata(&params, opts); err != nil {
+		return cohere.EmbedRequest{}, nil, nil, err
+	}
+
+	var warnings []api.CallWarning
+
+	return params, encodeRequestOptions(opts), warnings, nil
+}
+
+// EncodeMultimodalEmbedding builds Cohere embed params + request options from
+// the unified API options. Every value is sent as an input with text and image
+// content, since the images field of the embed API only accepts one image.
+func EncodeMultimodalEmbedding(
+	modelID string,
+	values []api.MultimodalEmbeddingInput,
+	opts api.TransportOptions,
+) (cohere.EmbedRequest, []requesterx.RequestOption, []api.CallWarning, error) {
+	params := cohere.EmbedRequest{
+		Model:     cohere.EmbeddingModel(modelID),
+		InputType: cohere.InputTypeSearchDocument,
+		Inputs:    make([]cohere.EmbedInput, len(values)),
+	}
+
+	for i, v := range values {
+		var content []cohere.EmbedContent
+		if v.Text != nil && *v.Text != "" {
+			content = append(content, cohere.EmbedContent{Type: "text", Text: v.Text})
+		}
+		if v.Image != nil && *v.Image != "" {
+			uri, err := imageURL(*v.Image)
+			if err != nil {
+				return cohere.EmbedRequest{}, nil, nil, api.NewInvalidArgumentError(err.Error(), fmt.Sprintf("values[%d].Image", i), err)
+			}
+			content = append(content, cohere.EmbedContent{Type: "image_url", ImageURL: &cohere.EmbedImageURL{URL: uri}})
+		}
+		params.Inputs[i] = cohere.EmbedInput{Content: content}
+	}
+
+	if err := applyEmbeddingMetadata(&params, opts); err != nil {
+		return cohere.EmbedRequest{}, nil, nil, err
+	}
+
+	var warnings []api.CallWarning
+
+	return params, encodeRequestOptions(opts), warnings, nil
+}
+
+// EmbeddingType returns the embedding type whose values are returned as
+// api.Embedding: the requested type, or float.
+func EmbeddingType(params cohere.EmbedRequest) cohere.EmbeddingType {
+	if len(params.EmbeddingTypes) > 0 {
+		return params.EmbeddingTypes[0]
+	}
+	return cohere.EmbeddingTypeFloat
+}
+
+// encodeRequestOptions maps the transport options shared by every Cohere
+// request to request options.
+func encodeRequestOptions(opts api.TransportOptions) []requesterx.RequestOption {
+	var reqOpts []requesterx.RequestOption
+	if opts.Headers != nil {
+		reqOpts = append(reqOpts, applyHeaders(opts.Headers)...)
+	}
+
+	if opts.APIKey != "" {
+		reqOpts = append(reqOpts, requesterx.WithAPIKey(opts.APIKey))
+	}
+
+	if len(opts.BaseURL) > 0 {
+		reqOpts = append(reqOpts, requesterx.WithBaseURL(opts.BaseURL))
+	}
+
+	if opts.Timeout > 0 {
+		reqOpts = append(reqOpts, requesterx.WithTimeout(opts.Timeout))
+	}
+
+	if opts.UseRawBaseURL {
+		reqOpts = append(reqOpts, requesterx.WithUseRawBaseURL())
+	}
+	return reqOpts
+}
+
+// applyHeaders applies the provided HTTP headers to the request options.
+func applyHeaders(headers http.Header) []requesterx.RequestOption {
+	var reqOpts []requesterx.RequestOption
+	for k, vs := range headers {
+		for _, v := range vs {
+			reqOpts = append(reqOpts, requesterx.WithHeaderAdd(k, v))
+		}
+	}
+	return reqOpts
+}
+
+// applyEmbeddingMetadata applies metadata-specific options to the parameters
+func applyEmbeddingMetadata(params *cohere.EmbedRequest, opts api.TransportOptions) error {
+	if opts.ProviderMetadata == nil {
+		return nil
+	}
+	metadata := GetEmbeddingMetadata(opts)
+	if metadata == nil {
+		return nil
+	}
+	if metadata.InputType != nil && *metadata.InputType != "" {
+		params.InputType = *metadata.InputType
+	}
+	for _, t := range metadata.EmbeddingTypes {
+		switch t {
+		case cohere.EmbeddingTypeFloat, cohere.EmbeddingTypeInt8, cohere.EmbeddingTypeUint8,
+			cohere.EmbeddingTypeBinary, cohere.EmbeddingTypeUbinary:
+		default:
+			return api.NewInvalidArgumentError(
+				fmt.Sprintf("cohere/embed: unsupported embedding type %q", t), "EmbeddingTypes", nil)
+		}
+	}
+	// Only the embeddings of one type can be returned as api.Embedding values,
+	// so requesting more would pay for embeddings that are thrown away.
+	if len(metadata.EmbeddingTypes) > 1 {
+		return api.NewInvalidArgumentError(
+			fmt.Sprintf("cohere/embed: only one embedding type can be requested, got %d", len(metadata.EmbeddingTypes)),
+			"EmbeddingTypes", nil)
+	}
+	if len(metadata.EmbeddingTypes) > 0 {
+		params.EmbeddingTypes = metadata.EmbeddingTypes
+	}
+	if metadata.OutputDimension != nil {
+		params.OutputDimension = metadata.OutputDimension
+	}
+	if metadata.Truncate != nil {
+		params.Truncate = metadata.Truncate
+	}
+	return nil
+}
+
+// imageURL returns image as the data URI Cohere expects. Data URIs are kept as
+// is; raw base64 data is wrapped in a data URI with its detected MIME type.
+// Cohere does not fetch images, so http(s) URLs are rejected.
+func imageURL(image string) (string, error) {
+	if strings.HasPrefix(image, "data:") {
+		return image, nil
+	}
+	if strings.HasPrefix(image, "http://") || strings.HasPrefix(image, "https://") {
+		return "", fmt.Errorf("cohere/embed: image URLs are not supported, pass a data URI or base64 data")
+	}
+	data, err := base64.StdEncoding.DecodeString(image)
+	if err != nil {
+		return "", fmt.Errorf("cohere/embed: image must be a data URI or base64 data: %w", err)
+	}
+	return fmt.Sprintf("data:%s;base64,%s", http.DetectContentType(data), image), nil
+}
@@ -0,0 +1,48 @@
+package codec
+
+import (
+	"go.jetify.com/ai/api"
+	cohere "go.jetify.com/ai/provider/cohere/client"
+	"go.jetify.com/ai/provider/internal/requesterx"
+)
+
+// EncodeRank builds Cohere rerank params + request options from the unified API options.
+func EncodeRank(
+	modelID string,
+	query string,
+	texts []string,
+	opts api.TransportOptions,
+) (cohere.RankRequest, []requesterx.RequestOption, []api.CallWarning, error) {
+	params := cohere.RankRequest{
+		Model:     cohere.RankingModel(modelID),
+		Query:     query,
+		Documents: texts,
+	}
+
+	// Cohere's v2 rerank API cannot echo the documents, so ReturnDocuments is
+	// left to the caller.
+	if opts.TopN > 0 {
+		params.TopN = &opts.TopN
+	}
+
+	applyRankProviderMetadata(&params, opts)
+
+	var warnings []api.CallWarning
+
+	return params, encodeRequestOptions(opts), warnings, nil
+}
+
+// applyRankProviderMetadata applies metadata-specific options to the rank parameters
+func applyRankProviderMetadata(params *cohere.RankRequest, opts api.TransportOptions) {
+	if opts.ProviderMetadata != nil {
+		metadata := GetRankingMetadata(opts)
+		if metadata != nil {
+			if metadata.TopN != nil {
+				params.TopN = metadata.TopN
+			}
+			if metadata.MaxTokensPerDoc != nil {
+				params.MaxTokensPerDoc = metadata.MaxTokensPerDoc
+			}
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package codec
+
+import (
+	"go.jetify.com/ai/api"
+	cohere "go.jetify.com/ai/provider/cohere/client"
+)
+
+// GetEmbeddingMetadata retrieves per-call knobs for the Cohere embed API.
+// See cohere.EmbeddingNewParams for available fields.
+func GetEmbeddingMetadata(source api.MetadataSource) *cohere.EmbeddingNewParams {
+	return api.GetMetadata[cohere.EmbeddingNewParams]("cohere", source)
+}
+
+// GetRankingMetadata retrieves per-call knobs for the Cohere rerank API.
+// See cohere.RankingNewParams for available fields.
+func GetRankingMetadata(source api.MetadataSource) *cohere.RankingNewParams {
+	return api.GetMetadata[cohere.RankingNewParams]("cohere", source)
+}
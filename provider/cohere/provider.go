@@ -0,0 +1,122 @@
+package cohere
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"go.jetify.com/ai/api"
+	cohere "go.jetify.com/ai/provider/cohere/client"
+	"go.jetify.com/ai/provider/internal/requesterx"
+)
+
+type Provider struct {
+	// client is the Cohere client used to make API calls.
+	client cohere.Client
+
+	// name is the name of the provider, overrides the default "cohere".
+	name string
+
+	// apiKey is the API key used for authentication.
+	apiKey string
+
+	// httpClient, if set, is used to send every request of the provider.
+	httpClient *http.Client
+
+	// httpConfig configures the HTTP client built when httpClient is not set.
+	httpConfig requesterx.HTTPClientConfig
+}
+
+var _ api.Provider = &Provider{}
+
+type ProviderOption func(*Provider)
+
+func WithClient(c cohere.Client) ProviderOption {
+	return func(p *Provider) { p.client = c }
+}
+
+func WithName(name string) ProviderOption {
+	return func(p *Provider) { p.name = name }
+}
+
+// WithAPIKey sets the API key sent with every request, overriding the
+// COHERE_API_KEY and CO_API_KEY environment variables.
+func WithAPIKey(apiKey string) ProviderOption {
+	return func(p *Provider) { p.apiKey = apiKey }
+}
+
+// WithHTTPClient sets the HTTP client used to send every request of the
+// provider. It takes precedence over WithTimeout and the connection pool
+// options, and can be used to share one tuned client between providers.
+func WithHTTPClient(c *http.Client) ProviderOption {
+	return func(p *Provider) { p.httpClient = c }
+}
+
+// WithTimeout bounds the time taken by each request of the provider,
+// including reading the response body. Use api.TransportOptions.Timeout to
+// bound a single call instead.
+func WithTimeout(d time.Duration) ProviderOption {
+	return func(p *Provider) { p.httpConfig.Timeout = d }
+}
+
+// WithMaxIdleConns limits the number of idle connections the provider keeps
+// open across all hosts.
+func WithMaxIdleConns(n int) ProviderOption {
+	return func(p *Provider) { p.httpConfig.MaxIdleConns = n }
+}
+
+// WithMaxIdleConnsPerHost limits the number of idle connections the provider
+// keeps open to each host.
+func WithMaxIdleConnsPerHost(n int) ProviderOption {
+	return func(p *Provider) { p.httpConfig.MaxIdleConnsPerHost = n }
+}
+
+// WithIdleConnTimeout sets how long an idle connection is kept open before
+// it is closed.
+func WithIdleConnTimeout(d time.Duration) ProviderOption {
+	return func(p *Provider) { p.httpConfig.IdleConnTimeout = d }
+}
+
+// NewProvider creates a new Cohere provider with the given options.
+func NewProvider(opts ...ProviderOption) api.Provider {
+	p := &Provider{client: cohere.NewClient()}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	var clientOpts []requesterx.RequestOption
+	if p.apiKey != "" {
+		clientOpts = append(clientOpts, requesterx.WithAPIKey(p.apiKey))
+	}
+	if p.httpClient == nil && !p.httpConfig.IsZero() {
+		p.httpClient = requesterx.NewHTTPClient(p.httpConfig)
+	}
+	if p.httpClient != nil {
+		clientOpts = append(clientOpts, requesterx.WithHTTPClient(p.httpClient))
+	}
+	if len(clientOpts) > 0 {
+		p.client = cohere.NewClient(append(slices.Clone(p.client.Options), clientOpts...)...)
+	}
+
+	if p.name == "" {
+		p.name = "cohere"
+	}
+
+	return p
+}
+
+// LanguageModel is not supported by the Cohere provider.
+func (p *Provider) LanguageModel(modelID string) (api.LanguageModel, error) {
+	return nil, api.NewUnsupportedFunctionalityError(p.name, "LanguageModel")
+}
+
+// SparseEmbeddingModel is not supported by the Cohere provider.
+func (p *Provider) SparseEmbeddingModel(modelID string) (api.EmbeddingModel[string, api.SparseEmbedding], error) {
+	return nil, api.NewUnsupportedFunctionalityError(p.name, "SparseEmbeddingModel")
+}
+
+// SegmentingModel is not supported by the Cohere provider.
+func (p *Provider) SegmentingModel(modelID string) (api.SegmentingModel, error) {
+	return nil, api.NewUnsupportedFunctionalityError(p.name, "SegmentingModel")
+}
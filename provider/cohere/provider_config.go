@@ -0,0 +1,11 @@
+package cohere
+
+import (
+	cohere "go.jetify.com/ai/provider/cohere/client"
+)
+
+type ProviderConfig struct {
+	providerName string
+	client       cohere.Client
+	apiKey       string
+}
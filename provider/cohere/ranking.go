@@ -0,0 +1,57 @@
+package cohere
+
+import (
+	"context"
+
+	"go.jetify.com/ai/api"
+	"go.jetify.com/ai/provider/cohere/internal/codec"
+)
+
+// RankingModel implements api.RankingModel using the Cohere rerank API.
+type RankingModel struct {
+	modelID string
+	pc      ProviderConfig
+}
+
+var _ api.RankingModel = &RankingModel{}
+
+// RankingModel creates a new Cohere ranking model. The model ID is one of the
+// Cohere rerank models, such as "rerank-v3.5".
+func (p *Provider) RankingModel(modelID string) (api.RankingModel, error) {
+	m := &RankingModel{
+		modelID: modelID,
+		pc: ProviderConfig{
+			providerName: p.name + ".ranking",
+			client:       p.client,
+			apiKey:       p.apiKey,
+		},
+	}
+	return m, nil
+}
+
+func (m *RankingModel) SpecificationVersion() string { return "v1" }
+func (m *RankingModel) ProviderName() string         { return m.pc.providerName }
+func (m *RankingModel) ModelID() string              { return m.modelID }
+func (m *RankingModel) SupportsParallelCalls() bool  { return true }
+
+// DoRank produces a score for each text given a query (implements api.RankingModel).
+// Top-N truncation can be requested with TopN or the "top_n" provider
+// metadata; the texts left out have a score of zero.
+func (m *RankingModel) DoRank(
+	ctx context.Context,
+	query string,
+	texts []string,
+	opts api.TransportOptions,
+) (api.RankingResponse, error) {
+	params, reqOpts, _, err := codec.EncodeRank(m.modelID, query, texts, opts)
+	if err != nil {
+		return api.RankingResponse{}, err
+	}
+
+	resp, err := m.pc.client.Ranking.Rank(ctx, params, reqOpts...)
+	if err != nil {
+		return api.RankingResponse{}, err
+	}
+
+	return codec.DecodeRank(resp, len(texts))
+}
@@ -0,0 +1,146 @@
+package cohere
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+	cohereClient "go.jetify.com/ai/provider/cohere/client"
+	"go.jetify.com/ai/provider/internal/requesterx"
+	"go.jetify.com/pkg/httpmock"
+)
+
+func TestDoRank(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		texts        []string
+		options      api.TransportOptions
+		exchanges    []httpmock.Exchange
+		wantErr      bool
+		expectedResp api.RankingResponse
+	}{
+		{
+			name:  "scores in input order",
+			query: "capital of France",
+			texts: []string{"Berlin is in Germany", "Paris is the capital of France"},
+			exchanges: []httpmock.Exchange{
+				{
+					Request: httpmock.Request{
+						Method: http.MethodPost,
+						Path:   "/rerank",
+						Body:   `{"model":"rerank-v3.5","query":"capital of France","documents":["Berlin is in Germany","Paris is the capital of France"]}`,
+					},
+					Response: httpmock.Response{
+						StatusCode: http.StatusOK,
+						Body:       `{"id":"rr-1","results":[{"index":1,"relevance_score":0.98},{"index":0,"relevance_score":0.02}],"meta":{"billed_units":{"search_units":1}}}`,
+					},
+				},
+			},
+			expectedResp: api.RankingResponse{
+				Scores: []float64{0.02, 0.98},
+				Results: []api.RankingResult{
+					{Index: 1, Score: 0.98},
+					{Index: 0, Score: 0.02},
+				},
+				Usage:     &api.RankingUsage{SearchUnits: 1},
+				RequestID: "rr-1",
+			},
+		},
+		{
+			name:    "top N via transport options",
+			query:   "capital of France",
+			texts:   []string{"Berlin is in Germany", "Paris is the capital of France"},
+			options: api.TransportOptions{TopN: 1, ReturnDocuments: true},
+			exchanges: []httpmock.Exchange{
+				{
+					Request: httpmock.Request{
+						Method: http.MethodPost,
+						Path:   "/rerank",
+						Body:   `{"model":"rerank-v3.5","query":"capital of France","documents":["Berlin is in Germany","Paris is the capital of France"],"top_n":1}`,
+					},
+					Response: httpmock.Response{
+						StatusCode: http.StatusOK,
+						Body:       `{"id":"rr-3","results":[{"index":1,"relevance_score":0.98}],"meta":{"billed_units":{"search_units":1}}}`,
+					},
+				},
+			},
+			expectedResp: api.RankingResponse{
+				Scores:    []float64{0, 0.98},
+				Results:   []api.RankingResult{{Index: 1, Score: 0.98}},
+				Usage:     &api.RankingUsage{SearchUnits: 1},
+				RequestID: "rr-3",
+			},
+		},
+		{
+			name:  "top N via provider metadata",
+			query: "capital of France",
+			texts: []string{"Berlin is in Germany", "Paris is the capital of France"},
+			options: api.TransportOptions{
+				ProviderMetadata: api.NewProviderMetadata(map[string]any{
+					"cohere": &cohereClient.RankingNewParams{TopN: ptr(1), MaxTokensPerDoc: ptr(512)},
+				}),
+			},
+			exchanges: []httpmock.Exchange{
+				{
+					Request: httpmock.Request{
+						Method: http.MethodPost,
+						Path:   "/rerank",
+						Body:   `{"model":"rerank-v3.5","query":"capital of France","documents":["Berlin is in Germany","Paris is the capital of France"],"top_n":1,"max_tokens_per_doc":512}`,
+					},
+					Response: httpmock.Response{
+						StatusCode: http.StatusOK,
+						Body:       `{"id":"rr-2","results":[{"index":1,"relevance_score":0.98}],"meta":{"billed_units":{"search_units":1}}}`,
+					},
+				},
+			},
+			expectedResp: api.RankingResponse{
+				Scores:    []float64{0, 0.98},
+				Results:   []api.RankingResult{{Index: 1, Score: 0.98}},
+				Usage:     &api.RankingUsage{SearchUnits: 1},
+				RequestID: "rr-2",
+			},
+		},
+		{
+			name:    "empty texts",
+			query:   "capital of France",
+			texts:   []string{},
+			wantErr: true,
+		},
+		{
+			name:  "server error",
+			query: "capital of France",
+			texts: []string{"Paris"},
+			exchanges: []httpmock.Exchange{
+				{
+					Request:  httpmock.Request{Method: http.MethodPost, Path: "/rerank"},
+					Response: httpmock.Response{StatusCode: http.StatusTooManyRequests, Body: `{"message":"rate limited"}`},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httpmock.NewServer(t, tt.exchanges)
+			defer server.Close()
+
+			client := cohereClient.NewClient(
+				requesterx.WithBaseURL(server.BaseURL()),
+				requesterx.WithAPIKey("test-key"),
+			)
+			model, err := NewProvider(WithClient(client)).RankingModel(cohereClient.RankingModelRerankV35)
+			require.NoError(t, err)
+
+			resp, err := model.DoRank(t.Context(), tt.query, tt.texts, tt.options)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedResp, resp)
+		})
+	}
+}
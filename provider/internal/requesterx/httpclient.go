@@ -0,0 +1,73 @@
+package requesterx
+
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultTransport is the transport shared by the providers that have not been
+// configured with their own HTTP client. It is a clone of
+// [http.DefaultTransport] that keeps more idle connections per host, since
+// embedding and ranking workloads usually send many concurrent requests to a
+// single endpoint.
+var DefaultTransport = newTransport(HTTPClientConfig{})
+
+// DefaultHTTPClient is the HTTP client used by requests that were not given
+// one with WithHTTPClient. It uses DefaultTransport and has no timeout.
+var DefaultHTTPClient = &http.Client{Transport: DefaultTransport}
+
+const (
+	defaultMaxIdleConns        = 100
+	defaultMaxIdleConnsPerHost = 32
+	defaultIdleConnTimeout     = 90 * time.Second
+)
+
+// HTTPClientConfig holds the settings used by NewHTTPClient. Zero values fall
+// back to the settings of DefaultTransport.
+type HTTPClientConfig struct {
+	// Timeout limits the time taken by each request, including reading the
+	// response body. Zero means no timeout.
+	Timeout time.Duration
+
+	// MaxIdleConns limits the number of idle connections across all hosts.
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost limits the number of idle connections kept per host.
+	MaxIdleConnsPerHost int
+
+	// IdleConnTimeout is how long an idle connection is kept in the pool.
+	IdleConnTimeout time.Duration
+}
+
+// IsZero reports whether no setting has been configured.
+func (c HTTPClientConfig) IsZero() bool {
+	return c == HTTPClientConfig{}
+}
+
+// NewHTTPClient returns an HTTP client configured with cfg. The client shares
+// DefaultTransport, and its connection pool, unless cfg changes one of the
+// pooling settings.
+func NewHTTPClient(cfg HTTPClientConfig) *http.Client {
+	transport := DefaultTransport
+	if cfg.MaxIdleConns != 0 || cfg.MaxIdleConnsPerHost != 0 || cfg.IdleConnTimeout != 0 {
+		transport = newTransport(cfg)
+	}
+	return &http.Client{Transport: transport, Timeout: cfg.Timeout}
+}
+
+func newTransport(cfg HTTPClientConfig) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = defaultMaxIdleConns
+	t.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	t.IdleConnTimeout = defaultIdleConnTimeout
+	if cfg.MaxIdleConns != 0 {
+		t.MaxIdleConns = cfg.MaxIdleConns
+	}
+	if cfg.MaxIdleConnsPerHost != 0 {
+		t.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+	if cfg.IdleConnTimeout != 0 {
+		t.IdleConnTimeout = cfg.IdleConnTimeout
+	}
+	return t
+}
@@ -9,6 +9,9 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
+
+	"go.jetify.com/ai/api"
 )
 
 // RequestOption is applied when preparing an HTTP request (headers, base URL, etc.).
@@ -20,6 +23,11 @@ type RequestOptionFunc func(*RequestConfig) error
 
 func (s RequestOptionFunc) Apply(r *RequestConfig) error { return s(r) }
 
+// ErrorDecoder extracts a provider-specific error message and payload from the
+// body of a failed response. It returns ok == false if the body does not match
+// the provider's error format.
+type ErrorDecoder func(body []byte) (message string, data any, ok bool)
+
 // RequestConfig holds reusable request settings for the TEI client.
 type RequestConfig struct {
 	BaseURL        *url.URL
@@ -34,6 +42,16 @@ type RequestConfig struct {
 	// ResponseBodyInto. If Destination is a []byte, then it will return the body as
 	// is.
 	ResponseBodyInto any
+	// RequestBody is the JSON-encoded request body, kept so that it can be
+	// reported when the request fails.
+	RequestBody []byte
+	// Timeout, if positive, bounds the time taken by the request, including
+	// reading the response body. Only this request is cancelled when it
+	// expires; the caller's context is left untouched.
+	Timeout time.Duration
+	// ErrorDecoder, if set, decodes the body of responses with a status code
+	// >= 400 into the message and data of the returned api.APICallError.
+	ErrorDecoder ErrorDecoder
 }
 
 // NewRequestConfig returns a minimal config with sensible defaults.
@@ -45,8 +63,10 @@ func NewRequestConfig(
 	opts ...RequestOption,
 ) (*RequestConfig, error) {
 	var reader io.Reader
+	var content []byte
 	if body != nil {
-		content, err := json.Marshal(body)
+		var err error
+		content, err = json.Marshal(body)
 		if err != nil {
 			return nil, err
 		}
@@ -63,8 +83,9 @@ func NewRequestConfig(
 
 	cfg := &RequestConfig{
 		Request:          req,
-		HTTPClient:       http.DefaultClient,
+		HTTPClient:       DefaultHTTPClient,
 		ResponseBodyInto: dst,
+		RequestBody:      content,
 	}
 
 	if err := cfg.Apply(opts...); err != nil {
@@ -107,6 +128,12 @@ func (cfg *RequestConfig) Execute() error {
 		cfg.Request.URL = u
 	}
 
+	if cfg.Timeout > 0 {
+		ctx, cancel := context.WithTimeout(cfg.Request.Context(), cfg.Timeout)
+		defer cancel()
+		cfg.Request = cfg.Request.WithContext(ctx)
+	}
+
 	resp, err := cfg.HTTPClient.Do(cfg.Request)
 	if err != nil {
 		return err
@@ -115,7 +142,7 @@ func (cfg *RequestConfig) Execute() error {
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("PROVIDER API ERROR: status=%d body=%s request=%s", resp.StatusCode, string(body), cfg.Request.URL.String())
+		return cfg.newAPICallError(resp, body)
 	}
 
 	if cfg.ResponseBodyInto == nil {
@@ -148,6 +175,41 @@ func (cfg *RequestConfig) Execute() error {
 	return nil
 }
 
+// newAPICallError builds the error returned for a response with a status code
+// >= 400. The message and data come from the ErrorDecoder when it recognizes
+// the body; otherwise the message is the status line followed by the raw body.
+func (cfg *RequestConfig) newAPICallError(resp *http.Response, body []byte) *api.APICallError {
+	// The body has been consumed; make it readable again for callers that
+	// inspect the response.
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	message := resp.Status
+	if trimmed := strings.TrimSpace(string(body)); trimmed != "" {
+		message = fmt.Sprintf("%s: %s", resp.Status, trimmed)
+	}
+
+	var data any
+	if cfg.ErrorDecoder != nil {
+		if msg, decoded, ok := cfg.ErrorDecoder(body); ok {
+			if msg != "" {
+				message = fmt.Sprintf("%s: %s", resp.Status, msg)
+			}
+			data = decoded
+		}
+	}
+
+	return &api.APICallError{
+		AISDKError:   api.NewAISDKError("AI_APICallError", message, nil),
+		URL:          cfg.Request.URL,
+		Request:      cfg.Request,
+		StatusCode:   resp.StatusCode,
+		Response:     resp,
+		ResponseBody: body,
+		RequestBody:  cfg.RequestBody,
+		Data:         data,
+	}
+}
+
 // Apply applies each option in order.
 func (cfg *RequestConfig) Apply(opts ...RequestOption) error {
 	for _, opt := range opts {
@@ -0,0 +1,138 @@
+package requesterx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+)
+
+func TestExecute_APICallError(t *testing.T) {
+	type payload struct {
+		Reason string `json:"reason"`
+	}
+	decoder := func(body []byte) (string, any, bool) {
+		if string(body) != `{"reason":"bad input"}` {
+			return "", nil, false
+		}
+		return "bad input", &payload{Reason: "bad input"}, true
+	}
+
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		opts          []RequestOption
+		wantMessage   string
+		wantData      any
+		wantRetryable bool
+	}{
+		{
+			name:          "server error without decoder",
+			status:        http.StatusServiceUnavailable,
+			body:          "overloaded\n",
+			wantMessage:   "503 Service Unavailable: overloaded",
+			wantRetryable: true,
+		},
+		{
+			name:        "empty body",
+			status:      http.StatusNotFound,
+			wantMessage: "404 Not Found",
+		},
+		{
+			name:        "decoded body",
+			status:      http.StatusUnprocessableEntity,
+			body:        `{"reason":"bad input"}`,
+			opts:        []RequestOption{WithErrorDecoder(decoder)},
+			wantMessage: "422 Unprocessable Entity: bad input",
+			wantData:    &payload{Reason: "bad input"},
+		},
+		{
+			name:          "body not recognized by decoder",
+			status:        http.StatusTooManyRequests,
+			body:          "slow down",
+			opts:          []RequestOption{WithErrorDecoder(decoder)},
+			wantMessage:   "429 Too Many Requests: slow down",
+			wantRetryable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-Id", "req-1")
+				w.WriteHeader(tt.status)
+				_, _ = io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			opts := append([]RequestOption{WithBaseURL(server.URL)}, tt.opts...)
+			err := ExecuteNewRequest(t.Context(), http.MethodPost, "embed", map[string]any{"inputs": "hi"}, nil, opts...)
+
+			var apiErr *api.APICallError
+			require.True(t, errors.As(err, &apiErr), "expected *api.APICallError, got %T", err)
+			assert.Equal(t, tt.wantMessage, apiErr.Error())
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+			assert.Equal(t, server.URL+"/embed", apiErr.URL.String())
+			assert.Equal(t, "req-1", apiErr.Response.Header.Get("X-Request-Id"))
+			assert.Equal(t, tt.body, string(apiErr.ResponseBody))
+			assert.JSONEq(t, `{"inputs":"hi"}`, string(apiErr.RequestBody))
+			assert.Equal(t, tt.wantData, apiErr.Data)
+			assert.Equal(t, tt.wantRetryable, apiErr.IsRetryable())
+
+			body, err := io.ReadAll(apiErr.Response.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, string(body), "response body stays readable")
+		})
+	}
+}
+
+func TestExecute_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx := t.Context()
+	err := ExecuteNewRequest(ctx, http.MethodGet, "slow", nil, nil,
+		WithBaseURL(server.URL),
+		WithTimeout(20*time.Millisecond),
+	)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NoError(t, ctx.Err(), "the caller's context is not cancelled")
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	t.Run("zero config shares the default transport", func(t *testing.T) {
+		c := NewHTTPClient(HTTPClientConfig{})
+		assert.Same(t, DefaultTransport, c.Transport)
+		assert.Zero(t, c.Timeout)
+	})
+
+	t.Run("timeout keeps the shared pool", func(t *testing.T) {
+		c := NewHTTPClient(HTTPClientConfig{Timeout: time.Second})
+		assert.Same(t, DefaultTransport, c.Transport)
+		assert.Equal(t, time.Second, c.Timeout)
+	})
+
+	t.Run("pool settings use a dedicated transport", func(t *testing.T) {
+		c := NewHTTPClient(HTTPClientConfig{MaxIdleConnsPerHost: 4})
+		transport, ok := c.Transport.(*http.Transport)
+		require.True(t, ok)
+		assert.NotSame(t, DefaultTransport, transport)
+		assert.Equal(t, 4, transport.MaxIdleConnsPerHost)
+		assert.Equal(t, DefaultTransport.MaxIdleConns, transport.MaxIdleConns)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 func WithBaseURL(raw string) RequestOption {
@@ -44,7 +45,7 @@ func WithHeaderDel(key string) RequestOption {
 }
 
 // WithHTTPClient returns a RequestOption that changes the underlying http client used to make this
-// request, which by default is [http.DefaultClient].
+// request, which by default is [DefaultHTTPClient].
 func WithHTTPClient(c *http.Client) RequestOption {
 	return RequestOptionFunc(func(r *RequestConfig) error {
 		if c != nil {
@@ -84,3 +85,21 @@ func WithUseRawBaseURL() RequestOption {
 		return nil
 	})
 }
+
+// WithErrorDecoder returns a RequestOption that sets the decoder used to
+// extract a provider-specific message and payload from error responses.
+func WithErrorDecoder(d ErrorDecoder) RequestOption {
+	return RequestOptionFunc(func(r *RequestConfig) error {
+		r.ErrorDecoder = d
+		return nil
+	})
+}
+
+// WithTimeout returns a RequestOption that bounds the time taken by the
+// request. A zero or negative duration means no timeout.
+func WithTimeout(d time.Duration) RequestOption {
+	return RequestOptionFunc(func(r *RequestConfig) error {
+		r.Timeout = d
+		return nil
+	})
+}
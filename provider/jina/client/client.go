@@ -11,13 +11,17 @@ type Client struct {
 	Options    []requesterx.RequestOption
 	Embeddings EmbeddingService
 	Segments   SegmentingService
+	Ranking    RankingService
 }
 
 // DefaultClientOptions read from the environment (JINA_API_KEY, JINA_ORG_ID,
 // JINA_PROJECT_ID, JINA_WEBHOOK_SECRET, JINA_BASE_URL). This should be used
 // to initialize new clients.
 func DefaultClientOptions() []requesterx.RequestOption {
-	defaults := []requesterx.RequestOption{option.WithEnvironmentProduction()}
+	defaults := []requesterx.RequestOption{
+		option.WithEnvironmentProduction(),
+		requesterx.WithErrorDecoder(decodeError),
+	}
 	if o, ok := os.LookupEnv("JINA_BASE_URL"); ok {
 		defaults = append(defaults, requesterx.WithBaseURL(o))
 	}
@@ -33,5 +37,6 @@ func NewClient(opts ...requesterx.RequestOption) (r Client) {
 	r = Client{Options: opts}
 	r.Embeddings = NewEmbeddingService(opts...)
 	r.Segments = NewSegmentingService(opts...)
+	r.Ranking = NewRankingService(opts...)
 	return r
 }
@@ -0,0 +1,51 @@
+package jina
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ErrorResponse represents the body of a failed Jina request.
+type ErrorResponse struct {
+	// Detail describes the error. It is usually a string, but validation
+	// errors report a list of ValidationError.
+	Detail json.RawMessage `json:"detail"`
+}
+
+// ValidationError describes an invalid field of a request.
+type ValidationError struct {
+	// Loc is the location of the invalid field in the request
+	Loc []any `json:"loc"`
+	// Msg is the error message
+	Msg string `json:"msg"`
+	// Type is the kind of validation error
+	Type string `json:"type"`
+}
+
+// Message returns a human readable description of the error.
+func (e *ErrorResponse) Message() string {
+	var detail string
+	if err := json.Unmarshal(e.Detail, &detail); err == nil {
+		return detail
+	}
+	var errs []ValidationError
+	if err := json.Unmarshal(e.Detail, &errs); err == nil {
+		msgs := make([]string, 0, len(errs))
+		for _, v := range errs {
+			msgs = append(msgs, v.Msg)
+		}
+		return strings.Join(msgs, "; ")
+	}
+	return string(e.Detail)
+}
+
+// decodeError decodes a Jina error body. It is used as the client's
+// requesterx.ErrorDecoder so that the returned api.APICallError carries an
+// *ErrorResponse as its Data.
+func decodeError(body []byte) (string, any, bool) {
+	var res ErrorResponse
+	if err := json.Unmarshal(body, &res); err != nil || len(res.Detail) == 0 {
+		return "", nil, false
+	}
+	return res.Message(), &res, true
+}
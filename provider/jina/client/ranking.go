@@ -0,0 +1,147 @@
+package jina
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.jetify.com/ai/provider/internal/requesterx"
+)
+
+// RankingService contains methods for reranking documents with the Jina
+// Reranker API.
+//
+// Note, unlike clients, this service does not read variables from the environment
+// automatically. You should not instantiate this service directly, and instead use
+// the [NewRankingService] method instead.
+type RankingService struct {
+	Options []requesterx.RequestOption
+}
+
+type RankingModel = string
+
+const (
+	// v3: Multilingual listwise reranker with 131K context
+	RankingModelJinaRerankerV3 RankingModel = "jina-reranker-v3"
+
+	// Multimodal multilingual reranker for visual documents
+	RankingModelJinaRerankerM0 RankingModel = "jina-reranker-m0"
+
+	// Multilingual cross-encoder reranker (100+ languages)
+	RankingModelJinaRerankerV2BaseMultilingual RankingModel = "jina-reranker-v2-base-multilingual"
+
+	// Multilingual late-interaction (ColBERT) reranker
+	RankingModelJinaColbertV2 RankingModel = "jina-colbert-v2"
+
+	// English-only reranker (deprecated)
+	RankingModelJinaRerankerV1BaseEn RankingModel = "jina-reranker-v1-base-en"
+
+	// Fast English-only reranker (deprecated)
+	RankingModelJinaRerankerV1TurboEn RankingModel = "jina-reranker-v1-turbo-en"
+
+	// Smallest English-only reranker (deprecated)
+	RankingModelJinaRerankerV1TinyEn RankingModel = "jina-reranker-v1-tiny-en"
+)
+
+// RankRequest models the POST body for the Jina Reranker API.
+type RankRequest struct {
+	// ID of the model to use.
+	Model RankingModel `json:"model"`
+	// Query to rank the documents against.
+	Query string `json:"query"`
+	// Documents to rank.
+	Documents []string `json:"documents"`
+	// TopN limits the results to the N most relevant documents. All documents
+	// are returned if it is not set.
+	TopN *int `json:"top_n,omitempty"`
+	// ReturnDocuments indicates whether to echo the documents in the results.
+	ReturnDocuments *bool `json:"return_documents,omitempty"`
+}
+
+// RankingNewParams allows callers to pass provider metadata to tweak
+// ranking behavior for Jina.
+type RankingNewParams struct {
+	// TopN limits the results to the N most relevant documents.
+	TopN *int `json:"top_n,omitempty"`
+	// ReturnDocuments indicates whether to echo the documents in the results.
+	ReturnDocuments *bool `json:"return_documents,omitempty"`
+}
+
+// RankResponse represents the response from the Jina Reranker API.
+type RankResponse struct {
+	// The name of the model used to rank the documents.
+	Model string `json:"model"`
+	// The usage information for the request.
+	Usage RankResponseUsage `json:"usage"`
+	// The ranked documents, sorted by descending relevance score.
+	Results []RankResult `json:"results"`
+}
+
+// The usage information for the request.
+type RankResponseUsage struct {
+	// The total number of tokens used by the request.
+	TotalTokens int64 `json:"total_tokens"`
+}
+
+// RankResult represents a single ranked document.
+type RankResult struct {
+	// Index is the position of the document in the request.
+	Index int `json:"index"`
+	// RelevanceScore is the relevance of the document to the query.
+	RelevanceScore float64 `json:"relevance_score"`
+	// Document is the ranked document, if ReturnDocuments was set.
+	Document *RankDocument `json:"document,omitempty"`
+}
+
+// RankDocument is a document echoed in a ranking result.
+type RankDocument struct {
+	Text string `json:"text"`
+}
+
+type rankRequestConcrete struct {
+	RankRequest
+}
+
+func (p rankRequestConcrete) validate() error {
+	if p.Model == "" {
+		return fmt.Errorf("model is required")
+	}
+	if p.Query == "" {
+		return fmt.Errorf("query is required")
+	}
+	if len(p.Documents) == 0 {
+		return fmt.Errorf("documents: []string must be non-empty")
+	}
+	if p.TopN != nil && *p.TopN <= 0 {
+		return fmt.Errorf("top_n must be positive")
+	}
+	return nil
+}
+
+// NewRankingService generates a new service that applies the given options to
+// each request. These options are applied after the parent client's options (if
+// there is one), and before any request-specific options.
+func NewRankingService(opts ...requesterx.RequestOption) (r RankingService) {
+	r = RankingService{}
+	r.Options = opts
+	return r
+}
+
+// Rank reorders the given documents based on their relevance to the query.
+// Returns documents sorted by relevance score in descending order.
+func (r *RankingService) Rank(ctx context.Context, body RankRequest, opts ...requesterx.RequestOption) (res *RankResponse, err error) {
+	req := rankRequestConcrete{
+		RankRequest: body,
+	}
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	all := append([]requesterx.RequestOption{}, r.Options...)
+	all = append(all, opts...)
+	path := "rerank"
+	err = requesterx.ExecuteNewRequest(ctx, http.MethodPost, path, body, &res, all...)
+	if err != nil {
+		return nil, fmt.Errorf("jina rerank: %w", err)
+	}
+	return res, nil
+}
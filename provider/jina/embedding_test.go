@@ -1,8 +1,12 @@
 package jina
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.jetify.com/ai/api"
@@ -183,3 +187,93 @@ func runDoEmbedTests(t *testing.T, tests []struct {
 		})
 	}
 }
+
+func TestDoEmbed_APICallError(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		body        string
+		wantMessage string
+	}{
+		{
+			name:        "string detail",
+			statusCode:  http.StatusUnauthorized,
+			body:        `{"detail": "Invalid API key"}`,
+			wantMessage: "401 Unauthorized: Invalid API key",
+		},
+		{
+			name:        "validation errors",
+			statusCode:  http.StatusUnprocessableEntity,
+			body:        `{"detail": [{"loc": ["body", "input"], "msg": "field required", "type": "value_error.missing"}]}`,
+			wantMessage: "422 Unprocessable Entity: field required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httpmock.NewServer(t, []httpmock.Exchange{{
+				Request: httpmock.Request{Method: http.MethodPost, Path: "/embeddings"},
+				Response: httpmock.Response{
+					StatusCode: tt.statusCode,
+					Headers:    map[string]string{"Content-Type": "application/json"},
+					Body:       tt.body,
+				},
+			}})
+			defer server.Close()
+
+			client := jina.NewClient(requesterx.WithBaseURL(server.BaseURL()))
+			model, err := NewProvider(WithClient(client)).TextEmbeddingModel("jina-embeddings-v3")
+			require.NoError(t, err)
+
+			_, err = model.DoEmbed(t.Context(), []string{"Hello"}, api.TransportOptions{})
+
+			var apiErr *api.APICallError
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, tt.statusCode, apiErr.StatusCode)
+			require.Equal(t, tt.wantMessage, apiErr.Error())
+			require.IsType(t, &jina.ErrorResponse{}, apiErr.Data)
+		})
+	}
+}
+
+func TestDoEmbed_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	tests := []struct {
+		name         string
+		providerOpts []ProviderOption
+		options      api.TransportOptions
+	}{
+		{
+			name:         "provider timeout",
+			providerOpts: []ProviderOption{WithTimeout(20 * time.Millisecond)},
+		},
+		{
+			name:    "per-call timeout",
+			options: api.TransportOptions{Timeout: 20 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := jina.NewClient(requesterx.WithBaseURL(server.URL))
+			opts := append([]ProviderOption{WithClient(client)}, tt.providerOpts...)
+			model, err := NewProvider(opts...).TextEmbeddingModel("jina-embeddings-v3")
+			require.NoError(t, err)
+
+			ctx := t.Context()
+			_, err = model.DoEmbed(ctx, []string{"Hello"}, tt.options)
+			require.Error(t, err)
+			require.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+			require.NoError(t, ctx.Err())
+		})
+	}
+}
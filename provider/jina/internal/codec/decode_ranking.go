@@ -0,0 +1,38 @@
+package codec
+
+import (
+	"go.jetify.com/ai/api"
+	jina "go.jetify.com/ai/provider/jina/client"
+)
+
+// DecodeRank maps the Jina rerank API response to the unified api.RankingResponse.
+// Jina returns the results sorted by relevance, possibly truncated to the top N,
+// so the scores of the numTexts inputs are restored from the result indices.
+func DecodeRank(resp *jina.RankResponse, numTexts int) (api.RankingResponse, error) {
+	if resp == nil {
+		return api.RankingResponse{}, api.NewEmptyResponseBodyError("response from Jina rerank API is nil")
+	}
+
+	scores := make([]float64, numTexts)
+	results := make([]api.RankingResult, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		if r.Index < 0 || r.Index >= numTexts {
+			continue
+		}
+		scores[r.Index] = r.RelevanceScore
+		result := api.RankingResult{Index: r.Index, Score: r.RelevanceScore}
+		if r.Document != nil {
+			result.Document = r.Document.Text
+		}
+		results = append(results, result)
+	}
+	api.SortRankingResults(results)
+
+	return api.RankingResponse{
+		Scores:  scores,
+		Results: results,
+		Usage: &api.RankingUsage{
+			TotalTokens: resp.Usage.TotalTokens,
+		},
+	}, nil
+}
@@ -0,0 +1,94 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+	jina "go.jetify.com/ai/provider/jina/client"
+)
+
+func TestDecodeRank(t *testing.T) {
+	type tc struct {
+		name       string
+		in         *jina.RankResponse
+		numTexts   int
+		want       api.RankingResponse
+		wantErrSub string
+	}
+
+	tests := []tc{
+		{
+			name:       "nil response -> error",
+			in:         nil,
+			wantErrSub: "response from Jina rerank API is nil",
+		},
+		{
+			name: "restores input order and keeps documents",
+			in: &jina.RankResponse{
+				Results: []jina.RankResult{
+					{Index: 1, RelevanceScore: 0.9, Document: &jina.RankDocument{Text: "b"}},
+					{Index: 2, RelevanceScore: 0.5, Document: &jina.RankDocument{Text: "c"}},
+					{Index: 0, RelevanceScore: 0.1, Document: &jina.RankDocument{Text: "a"}},
+				},
+				Usage: jina.RankResponseUsage{TotalTokens: 42},
+			},
+			numTexts: 3,
+			want: api.RankingResponse{
+				Scores: []float64{0.1, 0.9, 0.5},
+				Results: []api.RankingResult{
+					{Index: 1, Score: 0.9, Document: "b"},
+					{Index: 2, Score: 0.5, Document: "c"},
+					{Index: 0, Score: 0.1, Document: "a"},
+				},
+				Usage: &api.RankingUsage{TotalTokens: 42},
+			},
+		},
+		{
+			name: "top N leaves missing texts at zero",
+			in: &jina.RankResponse{
+				Results: []jina.RankResult{
+					{Index: 2, RelevanceScore: 0.7},
+				},
+				Usage: jina.RankResponseUsage{TotalTokens: 10},
+			},
+			numTexts: 3,
+			want: api.RankingResponse{
+				Scores:  []float64{0, 0, 0.7},
+				Results: []api.RankingResult{{Index: 2, Score: 0.7}},
+				Usage:   &api.RankingUsage{TotalTokens: 10},
+			},
+		},
+		{
+			name: "out of range indices are ignored",
+			in: &jina.RankResponse{
+				Results: []jina.RankResult{
+					{Index: 5, RelevanceScore: 0.7},
+					{Index: 0, RelevanceScore: 0.3},
+				},
+			},
+			numTexts: 1,
+			want: api.RankingResponse{
+				Scores:  []float64{0.3},
+				Results: []api.RankingResult{{Index: 0, Score: 0.3}},
+				Usage:   &api.RankingUsage{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeRank(tt.in, tt.numTexts)
+
+			if tt.wantErrSub != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrSub)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -27,6 +27,10 @@ func EncodeMultimodalEmbedding(
 		reqOpts = append(reqOpts, requesterx.WithBaseURL(opts.BaseURL))
 	}
 
+	if opts.Timeout > 0 {
+		reqOpts = append(reqOpts, requesterx.WithTimeout(opts.Timeout))
+	}
+
 	if opts.UseRawBaseURL {
 		reqOpts = append(reqOpts, requesterx.WithUseRawBaseURL())
 	}
@@ -63,6 +67,10 @@ func EncodeEmbedding(
 	if len(opts.BaseURL) > 0 {
 		reqOpts = append(reqOpts, requesterx.WithBaseURL(opts.BaseURL))
 	}
+
+	if opts.Timeout > 0 {
+		reqOpts = append(reqOpts, requesterx.WithTimeout(opts.Timeout))
+	}
 	if opts.UseRawBaseURL {
 		reqOpts = append(reqOpts, requesterx.WithUseRawBaseURL())
 	}
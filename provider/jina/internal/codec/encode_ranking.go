@@ -0,0 +1,70 @@
+package codec
+
+import (
+	"go.jetify.com/ai/api"
+	"go.jetify.com/ai/provider/internal/requesterx"
+	jina "go.jetify.com/ai/provider/jina/client"
+)
+
+// EncodeRank builds Jina rerank params + request options from the unified API options.
+func EncodeRank(
+	modelID string,
+	query string,
+	texts []string,
+	opts api.TransportOptions,
+) (jina.RankRequest, []requesterx.RequestOption, []api.CallWarning, error) {
+	var reqOpts []requesterx.RequestOption
+	if opts.Headers != nil {
+		reqOpts = append(reqOpts, applyHeaders(opts.Headers)...)
+	}
+
+	if opts.APIKey != "" {
+		reqOpts = append(reqOpts, requesterx.WithAPIKey(opts.APIKey))
+	}
+
+	if len(opts.BaseURL) > 0 {
+		reqOpts = append(reqOpts, requesterx.WithBaseURL(opts.BaseURL))
+	}
+
+	if opts.Timeout > 0 {
+		reqOpts = append(reqOpts, requesterx.WithTimeout(opts.Timeout))
+	}
+
+	if opts.UseRawBaseURL {
+		reqOpts = append(reqOpts, requesterx.WithUseRawBaseURL())
+	}
+
+	params := jina.RankRequest{
+		Model:     jina.RankingModel(modelID),
+		Query:     query,
+		Documents: texts,
+	}
+
+	if opts.TopN > 0 {
+		params.TopN = &opts.TopN
+	}
+	if opts.ReturnDocuments {
+		params.ReturnDocuments = &opts.ReturnDocuments
+	}
+
+	applyRankProviderMetadata(&params, opts)
+
+	var warnings []api.CallWarning
+
+	return params, reqOpts, warnings, nil
+}
+
+// applyRankProviderMetadata applies metadata-specific options to the rank parameters
+func applyRankProviderMetadata(params *jina.RankRequest, opts api.TransportOptions) {
+	if opts.ProviderMetadata != nil {
+		metadata := GetRankingMetadata(opts)
+		if metadata != nil {
+			if metadata.TopN != nil {
+				params.TopN = metadata.TopN
+			}
+			if metadata.ReturnDocuments != nil {
+				params.ReturnDocuments = metadata.ReturnDocuments
+			}
+		}
+	}
+}
@@ -27,6 +27,10 @@ func EncodeSegment(text string, opts api.TransportOptions) (jina.SegmentRequest,
 		reqOpts = append(reqOpts, requesterx.WithBaseURL(opts.BaseURL))
 	}
 
+	if opts.Timeout > 0 {
+		reqOpts = append(reqOpts, requesterx.WithTimeout(opts.Timeout))
+	}
+
 	if opts.UseRawBaseURL {
 		reqOpts = append(reqOpts, requesterx.WithUseRawBaseURL())
 	}
@@ -18,3 +18,9 @@ func GetMultimodalEmbeddingMetadata(source api.MetadataSource) *jina.MultimodalE
 func GetSegmentingMetadata(source api.MetadataSource) *jina.SegmentingNewParams {
 	return api.GetMetadata[jina.SegmentingNewParams]("jina", source)
 }
+
+// GetRankingMetadata retrieves per-call knobs for the Jina Reranker.
+// See jina.RankingNewParams for available fields.
+func GetRankingMetadata(source api.MetadataSource) *jina.RankingNewParams {
+	return api.GetMetadata[jina.RankingNewParams]("jina", source)
+}
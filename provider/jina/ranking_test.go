@@ -0,0 +1,148 @@
+package jina
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+	"go.jetify.com/ai/provider/internal/requesterx"
+	jinaClient "go.jetify.com/ai/provider/jina/client"
+	"go.jetify.com/pkg/httpmock"
+)
+
+func TestDoRank(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		texts        []string
+		options      api.TransportOptions
+		exchanges    []httpmock.Exchange
+		wantErr      bool
+		expectedResp api.RankingResponse
+	}{
+		{
+			name:  "scores in input order",
+			query: "organic food",
+			texts: []string{"Cheap snacks", "Organic vegetables", "Fresh fruit"},
+			exchanges: []httpmock.Exchange{
+				{
+					Request: httpmock.Request{
+						Method: http.MethodPost,
+						Path:   "/rerank",
+						Body:   `{"model":"jina-reranker-v2-base-multilingual","query":"organic food","documents":["Cheap snacks","Organic vegetables","Fresh fruit"]}`,
+					},
+					Response: httpmock.Response{
+						StatusCode: http.StatusOK,
+						Body:       `{"model":"jina-reranker-v2-base-multilingual","usage":{"total_tokens":38},"results":[{"index":1,"relevance_score":0.92,"document":{"text":"Organic vegetables"}},{"index":2,"relevance_score":0.41,"document":{"text":"Fresh fruit"}},{"index":0,"relevance_score":0.03,"document":{"text":"Cheap snacks"}}]}`,
+					},
+				},
+			},
+			expectedResp: api.RankingResponse{
+				Scores: []float64{0.03, 0.92, 0.41},
+				Results: []api.RankingResult{
+					{Index: 1, Score: 0.92, Document: "Organic vegetables"},
+					{Index: 2, Score: 0.41, Document: "Fresh fruit"},
+					{Index: 0, Score: 0.03, Document: "Cheap snacks"},
+				},
+				Usage: &api.RankingUsage{TotalTokens: 38},
+			},
+		},
+		{
+			name:  "top N via provider metadata",
+			query: "organic food",
+			texts: []string{"Cheap snacks", "Organic vegetables", "Fresh fruit"},
+			options: api.TransportOptions{
+				ProviderMetadata: api.NewProviderMetadata(map[string]any{
+					"jina": &jinaClient.RankingNewParams{TopN: ptr(1), ReturnDocuments: ptr(false)},
+				}),
+			},
+			exchanges: []httpmock.Exchange{
+				{
+					Request: httpmock.Request{
+						Method: http.MethodPost,
+						Path:   "/rerank",
+						Body:   `{"model":"jina-reranker-v2-base-multilingual","query":"organic food","documents":["Cheap snacks","Organic vegetables","Fresh fruit"],"top_n":1,"return_documents":false}`,
+					},
+					Response: httpmock.Response{
+						StatusCode: http.StatusOK,
+						Body:       `{"model":"jina-reranker-v2-base-multilingual","usage":{"total_tokens":38},"results":[{"index":1,"relevance_score":0.92}]}`,
+					},
+				},
+			},
+			expectedResp: api.RankingResponse{
+				Scores:  []float64{0, 0.92, 0},
+				Results: []api.RankingResult{{Index: 1, Score: 0.92}},
+				Usage:   &api.RankingUsage{TotalTokens: 38},
+			},
+		},
+		{
+			name:    "top N and return documents via transport options",
+			query:   "organic food",
+			texts:   []string{"Cheap snacks", "Organic vegetables", "Fresh fruit"},
+			options: api.TransportOptions{TopN: 1, ReturnDocuments: true},
+			exchanges: []httpmock.Exchange{
+				{
+					Request: httpmock.Request{
+						Method: http.MethodPost,
+						Path:   "/rerank",
+						Body:   `{"model":"jina-reranker-v2-base-multilingual","query":"organic food","documents":["Cheap snacks","Organic vegetables","Fresh fruit"],"top_n":1,"return_documents":true}`,
+					},
+					Response: httpmock.Response{
+						StatusCode: http.StatusOK,
+						Body:       `{"model":"jina-reranker-v2-base-multilingual","usage":{"total_tokens":38},"results":[{"index":1,"relevance_score":0.92,"document":{"text":"Organic vegetables"}}]}`,
+					},
+				},
+			},
+			expectedResp: api.RankingResponse{
+				Scores:  []float64{0, 0.92, 0},
+				Results: []api.RankingResult{{Index: 1, Score: 0.92, Document: "Organic vegetables"}},
+				Usage:   &api.RankingUsage{TotalTokens: 38},
+			},
+		},
+		{
+			name:    "empty texts",
+			query:   "organic food",
+			texts:   []string{},
+			wantErr: true,
+		},
+		{
+			name:  "server error",
+			query: "organic food",
+			texts: []string{"Cheap snacks"},
+			exchanges: []httpmock.Exchange{
+				{
+					Request:  httpmock.Request{Method: http.MethodPost, Path: "/rerank"},
+					Response: httpmock.Response{StatusCode: http.StatusInternalServerError, Body: `{"detail":"boom"}`},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httpmock.NewServer(t, tt.exchanges)
+			defer server.Close()
+
+			client := jinaClient.NewClient(
+				requesterx.WithBaseURL(server.BaseURL()),
+				requesterx.WithAPIKey("test-key"),
+			)
+
+			provider := NewProvider(WithClient(client))
+			model, err := provider.RankingModel(jinaClient.RankingModelJinaRerankerV2BaseMultilingual)
+			require.NoError(t, err)
+
+			resp, err := model.DoRank(t.Context(), tt.query, tt.texts, tt.options)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedResp, resp)
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,165 @@
+package mock
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/stretchr/testify/assert"
+	"go.jetify.com/ai/api"
+)
+
+// EmbeddingResult represents the scripted result of a single DoEmbed call.
+type EmbeddingResult[E api.EmbeddingVector] struct {
+	Response api.EmbeddingResponse[E]
+	Error    error
+}
+
+// EmbeddingCall records the arguments of a single DoEmbed call.
+type EmbeddingCall[I api.EmbeddingInput] struct {
+	Values  []I
+	Options api.TransportOptions
+}
+
+// EmbeddingModel is a mock [api.EmbeddingModel]. It either returns scripted
+// results, or computes deterministic fake embeddings from a hash of every
+// input value.
+type EmbeddingModel[I api.EmbeddingInput, E api.EmbeddingVector] struct {
+	modelConfig
+	results   []EmbeddingResult[E]
+	embed     func(I) E
+	callCount atomic.Int32
+
+	mu    sync.Mutex
+	calls []EmbeddingCall[I]
+}
+
+var _ api.EmbeddingModel[string, api.Embedding] = (*EmbeddingModel[string, api.Embedding])(nil)
+
+// NewEmbeddingModel creates a mock embedding model that returns the given
+// results in order as DoEmbed is called. Calls beyond the scripted results
+// return an empty response.
+func NewEmbeddingModel[I api.EmbeddingInput, E api.EmbeddingVector](
+	results []EmbeddingResult[E], opts ...ModelOption,
+) *EmbeddingModel[I, E] {
+	return &EmbeddingModel[I, E]{
+		modelConfig: newModelConfig(opts),
+		results:     results,
+	}
+}
+
+// NewHashEmbeddingModel creates a mock embedding model that embeds every value
+// with [HashEmbedding], so that equal values always get equal embeddings.
+func NewHashEmbeddingModel[I api.EmbeddingInput](dimensions int, opts ...ModelOption) *EmbeddingModel[I, api.Embedding] {
+	return &EmbeddingModel[I, api.Embedding]{
+		modelConfig: newModelConfig(opts),
+		embed:       func(value I) api.Embedding { return HashEmbedding(value, dimensions) },
+	}
+}
+
+// NewSparseHashEmbeddingModel creates a mock sparse embedding model that embeds
+// every value with [HashSparseEmbedding].
+func NewSparseHashEmbeddingModel(opts ...ModelOption) *EmbeddingModel[string, api.SparseEmbedding] {
+	return &EmbeddingModel[string, api.SparseEmbedding]{
+		modelConfig: newModelConfig(opts),
+		embed:       HashSparseEmbedding,
+	}
+}
+
+func (m *EmbeddingModel[I, E]) SpecificationVersion() string { return "v1" }
+
+func (m *EmbeddingModel[I, E]) ProviderName() string { return m.providerName }
+
+func (m *EmbeddingModel[I, E]) ModelID() string { return m.modelID }
+
+func (m *EmbeddingModel[I, E]) MaxEmbeddingsPerCall() *int { return m.maxEmbeddingsPerCall }
+
+func (m *EmbeddingModel[I, E]) SupportsParallelCalls() bool { return m.supportsParallelCalls }
+
+func (m *EmbeddingModel[I, E]) DoEmbed(
+	ctx context.Context, values []I, opts api.TransportOptions,
+) (api.EmbeddingResponse[E], error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, EmbeddingCall[I]{Values: values, Options: opts})
+	m.mu.Unlock()
+
+	index := int(m.callCount.Add(1) - 1)
+	if index < len(m.results) {
+		result := m.results[index]
+		return result.Response, result.Error
+	}
+	if m.embed == nil {
+		return api.EmbeddingResponse[E]{}, nil
+	}
+
+	embeddings := make([]E, len(values))
+	for i, value := range values {
+		embeddings[i] = m.embed(value)
+	}
+	return api.EmbeddingResponse[E]{Embeddings: embeddings}, nil
+}
+
+// Calls returns the arguments of every DoEmbed call, in the order the calls
+// were made.
+func (m *EmbeddingModel[I, E]) Calls() []EmbeddingCall[I] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]EmbeddingCall[I](nil), m.calls...)
+}
+
+// AssertCount verifies that all scripted results have been consumed.
+// For models without fake embeddings, it also fails if DoEmbed was called
+// more often than scripted.
+func (m *EmbeddingModel[I, E]) AssertCount(t T) {
+	t.Helper()
+	callCount := int(m.callCount.Load())
+	if m.embed != nil {
+		assert.GreaterOrEqual(t, callCount, len(m.results),
+			"EmbeddingModel: expected at least %d DoEmbed calls, but got %d", len(m.results), callCount)
+		return
+	}
+	assert.Equal(t, len(m.results), callCount,
+		"EmbeddingModel: expected %d DoEmbed calls, but got %d", len(m.results), callCount)
+}
+
+// HashEmbedding returns a deterministic, unit-length fake embedding of the
+// given dimension for value. Equal values always get equal embeddings, and
+// different values get unrelated ones.
+func HashEmbedding[I api.EmbeddingInput](value I, dimensions int) api.Embedding {
+	data, _ := json.Marshal(value)
+	sum := sha256.Sum256(data)
+	rng := rand.New(rand.NewPCG(binary.LittleEndian.Uint64(sum[:8]), binary.LittleEndian.Uint64(sum[8:16])))
+
+	embedding := make(api.Embedding, dimensions)
+	var norm float64
+	for i := range embedding {
+		embedding[i] = rng.Float64()*2 - 1
+		norm += embedding[i] * embedding[i]
+	}
+	norm = math.Sqrt(norm)
+	if norm > 0 {
+		for i := range embedding {
+			embedding[i] /= norm
+		}
+	}
+	return embedding
+}
+
+// HashSparseEmbedding returns a deterministic fake sparse embedding for text.
+// Every lowercased word of text becomes a token whose weight is derived from
+// a hash of the word and multiplied by the number of times it occurs.
+func HashSparseEmbedding(text string) api.SparseEmbedding {
+	embedding := api.SparseEmbedding{}
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		sum := sha256.Sum256([]byte(word))
+		weight := float64(binary.LittleEndian.Uint16(sum[:2])%1000+1) / 1000
+		embedding[word] += weight
+	}
+	return embedding
+}
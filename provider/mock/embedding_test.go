@@ -0,0 +1,104 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai"
+	"go.jetify.com/ai/api"
+)
+
+func TestEmbeddingModel_Scripted(t *testing.T) {
+	ctx := context.Background()
+	model := NewEmbeddingModel[string]([]EmbeddingResult[api.Embedding]{
+		{Response: api.EmbeddingResponse[api.Embedding]{Embeddings: []api.Embedding{{1, 2}}}},
+		{Error: errors.New("rate limit exceeded")},
+	}, WithModelID("embedder"), WithMaxEmbeddingsPerCall(8), WithParallelCalls())
+
+	assert.Equal(t, "mock-provider", model.ProviderName())
+	assert.Equal(t, "embedder", model.ModelID())
+	assert.Equal(t, 8, *model.MaxEmbeddingsPerCall())
+	assert.True(t, model.SupportsParallelCalls())
+
+	mockT := &mockTestingT{}
+	model.AssertCount(mockT)
+	assert.True(t, mockT.failed, "AssertCount should fail before the results are consumed")
+
+	resp, err := model.DoEmbed(ctx, []string{"a"}, api.TransportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []api.Embedding{{1, 2}}, resp.Embeddings)
+
+	_, err = model.DoEmbed(ctx, []string{"b"}, api.TransportOptions{APIKey: "key"})
+	assert.EqualError(t, err, "rate limit exceeded")
+
+	mockT = &mockTestingT{}
+	model.AssertCount(mockT)
+	assert.False(t, mockT.failed)
+
+	assert.Equal(t, []EmbeddingCall[string]{
+		{Values: []string{"a"}},
+		{Values: []string{"b"}, Options: api.TransportOptions{APIKey: "key"}},
+	}, model.Calls())
+
+	resp, err = model.DoEmbed(ctx, []string{"c"}, api.TransportOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Embeddings)
+	model.AssertCount(mockT)
+	assert.True(t, mockT.failed, "AssertCount should fail after an unscripted call")
+}
+
+func TestHashEmbeddingModel(t *testing.T) {
+	model := NewHashEmbeddingModel[string](16, WithMaxEmbeddingsPerCall(2))
+
+	resp, err := ai.EmbedMany(context.Background(), model, []string{"cat", "dog", "cat"})
+	require.NoError(t, err)
+	require.Len(t, resp.Embeddings, 3)
+
+	for _, embedding := range resp.Embeddings {
+		assert.Len(t, embedding, 16)
+		var norm float64
+		for _, v := range embedding {
+			norm += v * v
+		}
+		assert.InDelta(t, 1, math.Sqrt(norm), 1e-9)
+	}
+	assert.Equal(t, resp.Embeddings[0], resp.Embeddings[2])
+	assert.NotEqual(t, resp.Embeddings[0], resp.Embeddings[1])
+	assert.Equal(t, HashEmbedding("cat", 16), resp.Embeddings[0])
+
+	assert.Len(t, model.Calls(), 2, "EmbedMany should respect MaxEmbeddingsPerCall")
+	model.AssertCount(t)
+}
+
+func TestHashEmbeddingModel_Multimodal(t *testing.T) {
+	text, image := "a cat", "https://example.com/cat.png"
+	model := NewHashEmbeddingModel[api.MultimodalEmbeddingInput](4)
+
+	resp, err := model.DoEmbed(context.Background(),
+		[]api.MultimodalEmbeddingInput{{Text: &text}, {Image: &image}}, api.TransportOptions{})
+	require.NoError(t, err)
+	assert.NotEqual(t, resp.Embeddings[0], resp.Embeddings[1])
+	assert.Equal(t, HashEmbedding(api.MultimodalEmbeddingInput{Image: &image}, 4), resp.Embeddings[1])
+}
+
+func TestSparseHashEmbeddingModel(t *testing.T) {
+	model := NewSparseHashEmbeddingModel()
+
+	resp, err := model.DoEmbed(context.Background(), []string{"The cat", "the the"}, api.TransportOptions{})
+	require.NoError(t, err)
+	require.Len(t, resp.Embeddings, 2)
+	assert.ElementsMatch(t, []string{"the", "cat"}, keys(resp.Embeddings[0]))
+	assert.InDelta(t, 2*resp.Embeddings[0]["the"], resp.Embeddings[1]["the"], 1e-9)
+}
+
+func keys(embedding api.SparseEmbedding) []string {
+	var keys []string
+	for k := range embedding {
+		keys = append(keys, k)
+	}
+	return keys
+}
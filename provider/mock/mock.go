@@ -19,12 +19,30 @@
 //
 //	// Verify all expectations were met
 //	mock.AssertCount(t)
+//
+// Streaming calls are scripted with [WithStreams] or [NewStreamModel]:
+//
+//	mock := mock.NewStreamModel([]mock.MockStream{
+//		{Events: []api.StreamEvent{
+//			&api.TextDeltaEvent{TextDelta: "Hello"},
+//			&api.FinishEvent{FinishReason: api.FinishReasonStop},
+//		}},
+//	})
+//
+// Every call records the prompt and options it received; see
+// [GenerateModel.Calls].
+//
+// [EmbeddingModel], [RankingModel] and [SegmentingModel] follow the same
+// pattern for retrieval code. [NewHashEmbeddingModel] needs no script at all:
+// it derives deterministic fake embeddings from a hash of each input.
 package mock
 
 import (
 	"context"
 	"errors"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.jetify.com/ai/api"
@@ -36,12 +54,38 @@ type MockResult struct {
 	Error    error
 }
 
+// MockStream represents the scripted result of a single Stream call.
+type MockStream struct {
+	// Events are yielded in order by the stream. Use an [api.ErrorEvent] to
+	// simulate an error in the middle of a stream.
+	Events []api.StreamEvent
+
+	// Delay is how long the stream waits before yielding each event.
+	// If the context is cancelled while waiting, the stream yields an
+	// [api.ErrorEvent] with the context error and stops.
+	Delay time.Duration
+
+	// Error, if set, is returned by Stream itself instead of a stream.
+	Error error
+}
+
+// Call records the arguments of a single Generate or Stream call.
+type Call struct {
+	Prompt  []api.Message
+	Options api.CallOptions
+
+	// Stream is true for calls to Stream and false for calls to Generate.
+	Stream bool
+}
+
 type GenerateModel struct {
+	modelConfig
 	results         []MockResult
 	callCount       atomic.Int32
 	streamCallCount atomic.Int32
-	providerName    string
-	modelID         string
+
+	mu    sync.Mutex
+	calls []Call
 }
 
 // T is an interface that captures the testing.T methods we need
@@ -51,23 +95,6 @@ type T interface {
 	Helper()
 }
 
-// GenerateModelOption is a functional option for configuring a GenerateModel
-type GenerateModelOption func(*GenerateModel)
-
-// WithProviderName sets the provider name for the mock model
-func WithProviderName(name string) GenerateModelOption {
-	return func(m *GenerateModel) {
-		m.providerName = name
-	}
-}
-
-// WithModelID sets the model ID for the mock model
-func WithModelID(id string) GenerateModelOption {
-	return func(m *GenerateModel) {
-		m.modelID = id
-	}
-}
-
 // NewGenerateModel creates a new mock GenerateModel with the given results.
 // The results will be returned in order as Generate is called.
 // If results is nil, it will be treated as an empty slice.
@@ -77,18 +104,17 @@ func NewGenerateModel(results []MockResult, opts ...GenerateModelOption) *Genera
 		results = []MockResult{}
 	}
 
-	m := &GenerateModel{
-		results:      results,
-		providerName: "mock-provider", // Default
-		modelID:      "mock-model",    // Default
-	}
-
-	// Apply options
-	for _, opt := range opts {
-		opt(m)
+	return &GenerateModel{
+		modelConfig: newModelConfig(opts),
+		results:     results,
 	}
+}
 
-	return m
+// NewStreamModel creates a new mock GenerateModel whose Stream calls return the
+// given streams in order. It is a shorthand for NewGenerateModel(nil,
+// WithStreams(streams...)).
+func NewStreamModel(streams []MockStream, opts ...GenerateModelOption) *GenerateModel {
+	return NewGenerateModel(nil, append([]GenerateModelOption{WithStreams(streams...)}, opts...)...)
 }
 
 func (m *GenerateModel) ProviderName() string {
@@ -106,6 +132,8 @@ func (m *GenerateModel) SupportedUrls() []api.SupportedURL {
 func (m *GenerateModel) Generate(
 	ctx context.Context, prompt []api.Message, opts api.CallOptions,
 ) (*api.Response, error) {
+	m.record(Call{Prompt: prompt, Options: opts})
+
 	// Atomically increment and get the new count
 	newCount := m.callCount.Add(1)
 	index := newCount - 1
@@ -120,19 +148,70 @@ func (m *GenerateModel) Generate(
 func (m *GenerateModel) Stream(
 	ctx context.Context, prompt []api.Message, opts api.CallOptions,
 ) (*api.StreamResponse, error) {
-	m.streamCallCount.Add(1)
-	return nil, errors.New("Stream: not implemented")
+	m.record(Call{Prompt: prompt, Options: opts, Stream: true})
+
+	newCount := m.streamCallCount.Add(1)
+	if len(m.streams) == 0 {
+		return nil, errors.New("Stream: not implemented")
+	}
+
+	index := int(newCount - 1)
+	if index >= len(m.streams) {
+		return &api.StreamResponse{Stream: func(yield func(api.StreamEvent) bool) {}}, nil
+	}
+
+	script := m.streams[index]
+	if script.Error != nil {
+		return nil, script.Error
+	}
+	return &api.StreamResponse{Stream: replay(ctx, script)}, nil
+}
+
+// replay returns a stream that yields the scripted events, waiting for
+// the configured delay before each one.
+func replay(ctx context.Context, script MockStream) func(yield func(api.StreamEvent) bool) {
+	return func(yield func(api.StreamEvent) bool) {
+		for _, event := range script.Events {
+			if script.Delay > 0 {
+				select {
+				case <-ctx.Done():
+					yield(&api.ErrorEvent{Err: ctx.Err()})
+					return
+				case <-time.After(script.Delay):
+				}
+			}
+			if !yield(event) {
+				return
+			}
+		}
+	}
+}
+
+func (m *GenerateModel) record(call Call) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, call)
+}
+
+// Calls returns the arguments of every Generate and Stream call, in the
+// order the calls were made.
+func (m *GenerateModel) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
 }
 
 // AssertCount verifies that all expected results have been consumed
-// and that Stream was never called.
-// It fails the test if there are unused results or if Stream was called.
+// and that Stream was called exactly once per scripted stream (never, if no
+// streams were scripted).
+// It fails the test if there are unused results or streams, or if there were
+// more calls than scripted.
 func (m *GenerateModel) AssertCount(t T) {
 	t.Helper()
 	callCount := int(m.callCount.Load())
 	streamCallCount := int(m.streamCallCount.Load())
 	assert.Equal(t, len(m.results), callCount,
 		"GenerateModel: expected %d Generate calls, but got %d", len(m.results), callCount)
-	assert.Equal(t, 0, streamCallCount,
-		"GenerateModel: expected 0 Stream calls, but got %d", streamCallCount)
+	assert.Equal(t, len(m.streams), streamCallCount,
+		"GenerateModel: expected %d Stream calls, but got %d", len(m.streams), streamCallCount)
 }
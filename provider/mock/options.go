@@ -0,0 +1,66 @@
+package mock
+
+// modelConfig holds the settings shared by all mock models. Settings that do
+// not apply to a model are ignored.
+type modelConfig struct {
+	providerName          string
+	modelID               string
+	streams               []MockStream
+	maxEmbeddingsPerCall  *int
+	supportsParallelCalls bool
+}
+
+// ModelOption is a functional option for configuring a mock model.
+type ModelOption func(*modelConfig)
+
+// GenerateModelOption is a functional option for configuring a GenerateModel
+type GenerateModelOption = ModelOption
+
+func newModelConfig(opts []ModelOption) modelConfig {
+	config := modelConfig{
+		providerName: "mock-provider", // Default
+		modelID:      "mock-model",    // Default
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// WithProviderName sets the provider name for the mock model
+func WithProviderName(name string) ModelOption {
+	return func(c *modelConfig) {
+		c.providerName = name
+	}
+}
+
+// WithModelID sets the model ID for the mock model
+func WithModelID(id string) ModelOption {
+	return func(c *modelConfig) {
+		c.modelID = id
+	}
+}
+
+// WithStreams scripts the results of Stream calls. The streams are returned
+// in order as Stream is called.
+func WithStreams(streams ...MockStream) ModelOption {
+	return func(c *modelConfig) {
+		c.streams = append(c.streams, streams...)
+	}
+}
+
+// WithMaxEmbeddingsPerCall sets the limit reported by MaxEmbeddingsPerCall of
+// a mock embedding model. By default there is no limit.
+func WithMaxEmbeddingsPerCall(n int) ModelOption {
+	return func(c *modelConfig) {
+		c.maxEmbeddingsPerCall = &n
+	}
+}
+
+// WithParallelCalls makes the mock embedding, ranking and segmenting models
+// report that they support parallel calls. By default they do not.
+func WithParallelCalls() ModelOption {
+	return func(c *modelConfig) {
+		c.supportsParallelCalls = true
+	}
+}
@@ -0,0 +1,204 @@
+package mock
+
+import (
+	"reflect"
+	"sync"
+
+	"go.jetify.com/ai/api"
+)
+
+// ModelRequest records a single request for a model made to a [Provider].
+type ModelRequest struct {
+	ModelType api.ModelType
+	ModelID   string
+}
+
+type modelKey struct {
+	modelType api.ModelType
+	modelID   string
+}
+
+// functionality names the Provider method that returns each model type, as
+// reported in UnsupportedFunctionalityError.
+var functionality = map[api.ModelType]string{
+	api.LanguageModelType:            "LanguageModel",
+	api.TextEmbeddingModelType:       "TextEmbeddingModel",
+	api.MultimodalEmbeddingModelType: "MultimodalEmbeddingModel",
+	api.SparseEmbeddingModelType:     "SparseEmbeddingModel",
+	api.RankingModelType:             "RankingModel",
+	api.SegmentingModelType:          "SegmentingModel",
+}
+
+// Provider is a mock [api.Provider] that returns the models registered with
+// its options.
+//
+// Requesting a model ID that was not registered, or was registered with a nil
+// model, returns an
+// [api.NoSuchModelError], unless the model type was marked as unsupported
+// with [WithUnsupported], in which case an
+// [api.UnsupportedFunctionalityError] is returned.
+//
+// Example usage:
+//
+//	provider := mock.NewProvider(
+//		mock.WithLanguageModel("gpt", mock.NewGenerateModel(results)),
+//		mock.WithRankingModel("reranker", mock.NewRankingModel(rankings)),
+//		mock.WithUnsupported(api.SparseEmbeddingModelType),
+//	)
+type Provider struct {
+	name        string
+	models      map[modelKey]any
+	errors      map[modelKey]error
+	unsupported map[api.ModelType]bool
+
+	mu       sync.Mutex
+	requests []ModelRequest
+}
+
+var _ api.Provider = (*Provider)(nil)
+
+// ProviderOption is a functional option for configuring a mock Provider.
+type ProviderOption func(*Provider)
+
+// WithName sets the provider name reported in errors. Defaults to
+// "mock-provider".
+func WithName(name string) ProviderOption {
+	return func(p *Provider) {
+		p.name = name
+	}
+}
+
+// WithLanguageModel registers model under modelID.
+func WithLanguageModel(modelID string, model api.LanguageModel) ProviderOption {
+	return withModel(api.LanguageModelType, modelID, model)
+}
+
+// WithTextEmbeddingModel registers model under modelID.
+func WithTextEmbeddingModel(modelID string, model api.EmbeddingModel[string, api.Embedding]) ProviderOption {
+	return withModel(api.TextEmbeddingModelType, modelID, model)
+}
+
+// WithMultimodalEmbeddingModel registers model under modelID.
+func WithMultimodalEmbeddingModel(
+	modelID string, model api.EmbeddingModel[api.MultimodalEmbeddingInput, api.Embedding],
+) ProviderOption {
+	return withModel(api.MultimodalEmbeddingModelType, modelID, model)
+}
+
+// WithSparseEmbeddingModel registers model under modelID.
+func WithSparseEmbeddingModel(modelID string, model api.EmbeddingModel[string, api.SparseEmbedding]) ProviderOption {
+	return withModel(api.SparseEmbeddingModelType, modelID, model)
+}
+
+// WithRankingModel registers model under modelID.
+func WithRankingModel(modelID string, model api.RankingModel) ProviderOption {
+	return withModel(api.RankingModelType, modelID, model)
+}
+
+// WithSegmentingModel registers model under modelID.
+func WithSegmentingModel(modelID string, model api.SegmentingModel) ProviderOption {
+	return withModel(api.SegmentingModelType, modelID, model)
+}
+
+func withModel(modelType api.ModelType, modelID string, model any) ProviderOption {
+	return func(p *Provider) {
+		p.models[modelKey{modelType, modelID}] = model
+	}
+}
+
+// WithModelError makes requests for the model of the given type and ID return
+// err, e.g. an [api.NoSuchModelError] or an [api.UnsupportedFunctionalityError].
+func WithModelError(modelType api.ModelType, modelID string, err error) ProviderOption {
+	return func(p *Provider) {
+		p.errors[modelKey{modelType, modelID}] = err
+	}
+}
+
+// WithUnsupported makes requests for unregistered models of the given types
+// return an [api.UnsupportedFunctionalityError] instead of an
+// [api.NoSuchModelError].
+func WithUnsupported(modelTypes ...api.ModelType) ProviderOption {
+	return func(p *Provider) {
+		for _, modelType := range modelTypes {
+			p.unsupported[modelType] = true
+		}
+	}
+}
+
+// NewProvider creates a new mock Provider with the given options.
+func NewProvider(opts ...ProviderOption) *Provider {
+	p := &Provider{
+		name:        "mock-provider",
+		models:      map[modelKey]any{},
+		errors:      map[modelKey]error{},
+		unsupported: map[api.ModelType]bool{},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *Provider) LanguageModel(modelID string) (api.LanguageModel, error) {
+	return lookup[api.LanguageModel](p, api.LanguageModelType, modelID)
+}
+
+func (p *Provider) TextEmbeddingModel(modelID string) (api.EmbeddingModel[string, api.Embedding], error) {
+	return lookup[api.EmbeddingModel[string, api.Embedding]](p, api.TextEmbeddingModelType, modelID)
+}
+
+func (p *Provider) MultimodalEmbeddingModel(
+	modelID string,
+) (api.EmbeddingModel[api.MultimodalEmbeddingInput, api.Embedding], error) {
+	return lookup[api.EmbeddingModel[api.MultimodalEmbeddingInput, api.Embedding]](
+		p, api.MultimodalEmbeddingModelType, modelID)
+}
+
+func (p *Provider) SparseEmbeddingModel(modelID string) (api.EmbeddingModel[string, api.SparseEmbedding], error) {
+	return lookup[api.EmbeddingModel[string, api.SparseEmbedding]](p, api.SparseEmbeddingModelType, modelID)
+}
+
+func (p *Provider) RankingModel(modelID string) (api.RankingModel, error) {
+	return lookup[api.RankingModel](p, api.RankingModelType, modelID)
+}
+
+func (p *Provider) SegmentingModel(modelID string) (api.SegmentingModel, error) {
+	return lookup[api.SegmentingModel](p, api.SegmentingModelType, modelID)
+}
+
+// Requests returns every model request made to the provider, in order.
+func (p *Provider) Requests() []ModelRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]ModelRequest(nil), p.requests...)
+}
+
+func lookup[M any](p *Provider, modelType api.ModelType, modelID string) (M, error) {
+	p.mu.Lock()
+	p.requests = append(p.requests, ModelRequest{ModelType: modelType, ModelID: modelID})
+	p.mu.Unlock()
+
+	var zero M
+	key := modelKey{modelType, modelID}
+	if err, ok := p.errors[key]; ok {
+		return zero, err
+	}
+	// A nil model, including a typed nil such as (*RankingModel)(nil), is
+	// reported as missing rather than returned to panic on first use.
+	if model, ok := p.models[key].(M); ok && !isNil(model) {
+		return model, nil
+	}
+	if p.unsupported[modelType] {
+		return zero, api.NewUnsupportedFunctionalityError(p.name, functionality[modelType])
+	}
+	return zero, api.NewNoSuchModelError(modelID, modelType)
+}
+
+// isNil reports whether v is nil or an interface holding a nil pointer.
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
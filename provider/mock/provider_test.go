@@ -0,0 +1,107 @@
+package mock
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai"
+	"go.jetify.com/ai/api"
+)
+
+func TestProvider(t *testing.T) {
+	llm := NewGenerateModel(nil)
+	embedder := NewHashEmbeddingModel[string](4)
+	multimodal := NewHashEmbeddingModel[api.MultimodalEmbeddingInput](4)
+	sparse := NewSparseHashEmbeddingModel()
+	ranker := NewRankingModel(nil)
+	segmenter := NewSegmentingModel(nil)
+	overloaded := errors.New("overloaded")
+
+	provider := NewProvider(
+		WithName("test"),
+		WithLanguageModel("llm", llm),
+		WithTextEmbeddingModel("embedder", embedder),
+		WithMultimodalEmbeddingModel("multimodal", multimodal),
+		WithSparseEmbeddingModel("sparse", sparse),
+		WithRankingModel("ranker", ranker),
+		WithSegmentingModel("segmenter", segmenter),
+		WithModelError(api.LanguageModelType, "broken", overloaded),
+		WithUnsupported(api.SegmentingModelType),
+	)
+
+	t.Run("registered models", func(t *testing.T) {
+		model, err := provider.LanguageModel("llm")
+		require.NoError(t, err)
+		assert.Same(t, llm, model)
+
+		textEmbedding, err := provider.TextEmbeddingModel("embedder")
+		require.NoError(t, err)
+		assert.Same(t, embedder, textEmbedding)
+
+		multimodalEmbedding, err := provider.MultimodalEmbeddingModel("multimodal")
+		require.NoError(t, err)
+		assert.Same(t, multimodal, multimodalEmbedding)
+
+		sparseEmbedding, err := provider.SparseEmbeddingModel("sparse")
+		require.NoError(t, err)
+		assert.Same(t, sparse, sparseEmbedding)
+
+		ranking, err := provider.RankingModel("ranker")
+		require.NoError(t, err)
+		assert.Same(t, ranker, ranking)
+
+		segmenting, err := provider.SegmentingModel("segmenter")
+		require.NoError(t, err)
+		assert.Same(t, segmenter, segmenting)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := provider.LanguageModel("broken")
+		assert.ErrorIs(t, err, overloaded)
+
+		_, err = provider.RankingModel("missing")
+		var noSuchModel *api.NoSuchModelError
+		require.ErrorAs(t, err, &noSuchModel)
+		assert.Equal(t, "missing", noSuchModel.ModelID)
+		assert.Equal(t, api.RankingModelType, noSuchModel.ModelType)
+
+		// Registered models of an unsupported type are still returned.
+		_, err = provider.SegmentingModel("missing")
+		var unsupported *api.UnsupportedFunctionalityError
+		require.ErrorAs(t, err, &unsupported)
+		assert.Equal(t, "test", unsupported.Functionality)
+		assert.Equal(t, "SegmentingModel", unsupported.Message)
+	})
+
+	t.Run("registry", func(t *testing.T) {
+		registry := ai.NewRegistry()
+		registry.Register("mock", provider)
+
+		model, err := registry.LanguageModel("mock:llm")
+		require.NoError(t, err)
+		assert.Same(t, llm, model)
+	})
+
+	requests := provider.Requests()
+	require.Len(t, requests, 10)
+	assert.Equal(t, ModelRequest{ModelType: api.LanguageModelType, ModelID: "llm"}, requests[0])
+	assert.Equal(t, ModelRequest{ModelType: api.SegmentingModelType, ModelID: "missing"}, requests[8])
+}
+
+func TestProvider_NilModel(t *testing.T) {
+	provider := NewProvider(
+		WithRankingModel("ranker", nil),
+		WithTextEmbeddingModel("embedder", (*EmbeddingModel[string, api.Embedding])(nil)),
+	)
+
+	_, err := provider.RankingModel("ranker")
+	var noSuchModel *api.NoSuchModelError
+	require.ErrorAs(t, err, &noSuchModel)
+	assert.Equal(t, "ranker", noSuchModel.ModelID)
+
+	_, err = provider.TextEmbeddingModel("embedder")
+	require.ErrorAs(t, err, &noSuchModel)
+	assert.Equal(t, "embedder", noSuchModel.ModelID)
+}
@@ -0,0 +1,85 @@
+package mock
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/stretchr/testify/assert"
+	"go.jetify.com/ai/api"
+)
+
+// RankingResult represents the scripted result of a single DoRank call.
+type RankingResult struct {
+	Response api.RankingResponse
+	Error    error
+}
+
+// RankingCall records the arguments of a single DoRank call.
+type RankingCall struct {
+	Query   string
+	Texts   []string
+	Options api.TransportOptions
+}
+
+// RankingModel is a mock [api.RankingModel] that returns scripted results.
+type RankingModel struct {
+	modelConfig
+	results   []RankingResult
+	callCount atomic.Int32
+
+	mu    sync.Mutex
+	calls []RankingCall
+}
+
+var _ api.RankingModel = (*RankingModel)(nil)
+
+// NewRankingModel creates a mock ranking model that returns the given results
+// in order as DoRank is called. Calls beyond the scripted results return an
+// empty response.
+func NewRankingModel(results []RankingResult, opts ...ModelOption) *RankingModel {
+	return &RankingModel{
+		modelConfig: newModelConfig(opts),
+		results:     results,
+	}
+}
+
+func (m *RankingModel) SpecificationVersion() string { return "v1" }
+
+func (m *RankingModel) ProviderName() string { return m.providerName }
+
+func (m *RankingModel) ModelID() string { return m.modelID }
+
+func (m *RankingModel) SupportsParallelCalls() bool { return m.supportsParallelCalls }
+
+func (m *RankingModel) DoRank(
+	ctx context.Context, query string, texts []string, opts api.TransportOptions,
+) (api.RankingResponse, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, RankingCall{Query: query, Texts: texts, Options: opts})
+	m.mu.Unlock()
+
+	index := int(m.callCount.Add(1) - 1)
+	if index < len(m.results) {
+		result := m.results[index]
+		return result.Response, result.Error
+	}
+	return api.RankingResponse{}, nil
+}
+
+// Calls returns the arguments of every DoRank call, in the order the calls
+// were made.
+func (m *RankingModel) Calls() []RankingCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]RankingCall(nil), m.calls...)
+}
+
+// AssertCount verifies that DoRank was called exactly once per scripted
+// result.
+func (m *RankingModel) AssertCount(t T) {
+	t.Helper()
+	callCount := int(m.callCount.Load())
+	assert.Equal(t, len(m.results), callCount,
+		"RankingModel: expected %d DoRank calls, but got %d", len(m.results), callCount)
+}
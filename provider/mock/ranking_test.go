@@ -0,0 +1,39 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai"
+	"go.jetify.com/ai/api"
+)
+
+func TestRankingModel(t *testing.T) {
+	model := NewRankingModel([]RankingResult{
+		{Response: api.RankingResponse{Scores: []float64{0.9, 0.1}}},
+		{Error: errors.New("boom")},
+	}, WithProviderName("ranker"))
+	assert.Equal(t, "ranker", model.ProviderName())
+	assert.False(t, model.SupportsParallelCalls())
+
+	resp, err := ai.RankMany(context.Background(), model, "query", []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0.9, 0.1}, resp.Scores)
+
+	_, err = ai.RankMany(context.Background(), model, "other", []string{"c"})
+	assert.EqualError(t, err, "boom")
+
+	assert.Equal(t, []RankingCall{
+		{Query: "query", Texts: []string{"a", "b"}},
+		{Query: "other", Texts: []string{"c"}},
+	}, model.Calls())
+	model.AssertCount(t)
+
+	mockT := &mockTestingT{}
+	_, _ = model.DoRank(context.Background(), "extra", nil, api.TransportOptions{})
+	model.AssertCount(mockT)
+	assert.True(t, mockT.failed)
+}
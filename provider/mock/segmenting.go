@@ -0,0 +1,84 @@
+package mock
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/stretchr/testify/assert"
+	"go.jetify.com/ai/api"
+)
+
+// SegmentingResult represents the scripted result of a single DoSegment call.
+type SegmentingResult struct {
+	Response api.SegmentingResponse
+	Error    error
+}
+
+// SegmentingCall records the arguments of a single DoSegment call.
+type SegmentingCall struct {
+	Texts   []string
+	Options api.TransportOptions
+}
+
+// SegmentingModel is a mock [api.SegmentingModel] that returns scripted results.
+type SegmentingModel struct {
+	modelConfig
+	results   []SegmentingResult
+	callCount atomic.Int32
+
+	mu    sync.Mutex
+	calls []SegmentingCall
+}
+
+var _ api.SegmentingModel = (*SegmentingModel)(nil)
+
+// NewSegmentingModel creates a mock segmenting model that returns the given
+// results in order as DoSegment is called. Calls beyond the scripted results
+// return an empty response.
+func NewSegmentingModel(results []SegmentingResult, opts ...ModelOption) *SegmentingModel {
+	return &SegmentingModel{
+		modelConfig: newModelConfig(opts),
+		results:     results,
+	}
+}
+
+func (m *SegmentingModel) SpecificationVersion() string { return "v1" }
+
+func (m *SegmentingModel) ProviderName() string { return m.providerName }
+
+func (m *SegmentingModel) ModelID() string { return m.modelID }
+
+func (m *SegmentingModel) SupportsParallelCalls() bool { return m.supportsParallelCalls }
+
+func (m *SegmentingModel) DoSegment(
+	ctx context.Context, texts []string, opts api.TransportOptions,
+) (api.SegmentingResponse, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, SegmentingCall{Texts: texts, Options: opts})
+	m.mu.Unlock()
+
+	index := int(m.callCount.Add(1) - 1)
+	if index < len(m.results) {
+		result := m.results[index]
+		return result.Response, result.Error
+	}
+	return api.SegmentingResponse{}, nil
+}
+
+// Calls returns the arguments of every DoSegment call, in the order the calls
+// were made.
+func (m *SegmentingModel) Calls() []SegmentingCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]SegmentingCall(nil), m.calls...)
+}
+
+// AssertCount verifies that DoSegment was called exactly once per scripted
+// result.
+func (m *SegmentingModel) AssertCount(t T) {
+	t.Helper()
+	callCount := int(m.callCount.Load())
+	assert.Equal(t, len(m.results), callCount,
+		"SegmentingModel: expected %d DoSegment calls, but got %d", len(m.results), callCount)
+}
@@ -0,0 +1,30 @@
+package mock
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai"
+	"go.jetify.com/ai/api"
+)
+
+func TestSegmentingModel(t *testing.T) {
+	segments := [][]api.Segment{{{Text: "hello"}, {Text: "world"}}}
+	model := NewSegmentingModel([]SegmentingResult{
+		{Response: api.SegmentingResponse{Segments: segments}},
+	}, WithModelID("segmenter"), WithParallelCalls())
+	assert.Equal(t, "segmenter", model.ModelID())
+	assert.True(t, model.SupportsParallelCalls())
+
+	mockT := &mockTestingT{}
+	model.AssertCount(mockT)
+	assert.True(t, mockT.failed)
+
+	resp, err := ai.SegmentMany(context.Background(), model, []string{"hello world"})
+	require.NoError(t, err)
+	assert.Equal(t, segments, resp.Segments)
+	assert.Equal(t, []SegmentingCall{{Texts: []string{"hello world"}}}, model.Calls())
+	model.AssertCount(t)
+}
@@ -0,0 +1,138 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+	"go.jetify.com/ai/builder"
+)
+
+func collect(stream *api.StreamResponse) []api.StreamEvent {
+	var events []api.StreamEvent
+	for event := range stream.Stream {
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestStreamModel(t *testing.T) {
+	ctx := context.Background()
+	first := []api.StreamEvent{
+		&api.TextDeltaEvent{TextDelta: "Hello"},
+		&api.TextDeltaEvent{TextDelta: " world"},
+		&api.FinishEvent{FinishReason: api.FinishReasonStop},
+	}
+	second := []api.StreamEvent{
+		&api.ToolCallDeltaEvent{ToolCallID: "call_1", ToolName: "weather", ArgsDelta: []byte(`{"city":`)},
+		&api.ToolCallDeltaEvent{ToolCallID: "call_1", ToolName: "weather", ArgsDelta: []byte(`"Paris"}`)},
+		&api.ErrorEvent{Err: errors.New("connection reset")},
+	}
+	streamErr := errors.New("rate limit exceeded")
+
+	model := NewStreamModel([]MockStream{
+		{Events: first},
+		{Events: second},
+		{Error: streamErr},
+	})
+
+	resp, err := model.Stream(ctx, nil, api.CallOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, first, collect(resp))
+
+	resp, err = model.Stream(ctx, nil, api.CallOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, second, collect(resp))
+
+	_, err = model.Stream(ctx, nil, api.CallOptions{})
+	assert.ErrorIs(t, err, streamErr)
+
+	mockT := &mockTestingT{}
+	model.AssertCount(mockT)
+	assert.False(t, mockT.failed, "AssertCount should pass once every stream was consumed")
+
+	// Calls beyond the script return an empty stream and fail AssertCount.
+	resp, err = model.Stream(ctx, nil, api.CallOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, collect(resp))
+	model.AssertCount(mockT)
+	assert.True(t, mockT.failed)
+}
+
+func TestStreamModel_AssertCountUnusedStreams(t *testing.T) {
+	model := NewStreamModel([]MockStream{{}, {}})
+	_, err := model.Stream(context.Background(), nil, api.CallOptions{})
+	require.NoError(t, err)
+
+	mockT := &mockTestingT{}
+	model.AssertCount(mockT)
+	assert.True(t, mockT.failed, "AssertCount should fail with unused streams")
+}
+
+func TestStreamModel_Builder(t *testing.T) {
+	model := NewStreamModel([]MockStream{{Events: []api.StreamEvent{
+		&api.TextDeltaEvent{TextDelta: "Hello"},
+		&api.TextDeltaEvent{TextDelta: " world"},
+		&api.FinishEvent{
+			FinishReason: api.FinishReasonStop,
+			Usage:        api.Usage{InputTokens: 2, OutputTokens: 2, TotalTokens: 4},
+		},
+	}}})
+
+	stream, err := model.Stream(context.Background(), nil, api.CallOptions{})
+	require.NoError(t, err)
+	resp, err := builder.StreamToResponse(stream)
+	require.NoError(t, err)
+	assert.Equal(t, []api.ContentBlock{&api.TextBlock{Text: "Hello world"}}, resp.Content)
+	assert.Equal(t, api.FinishReasonStop, resp.FinishReason)
+}
+
+func TestStreamModel_Delay(t *testing.T) {
+	events := []api.StreamEvent{
+		&api.TextDeltaEvent{TextDelta: "a"},
+		&api.TextDeltaEvent{TextDelta: "b"},
+	}
+	model := NewStreamModel([]MockStream{
+		{Events: events, Delay: 10 * time.Millisecond},
+		{Events: events, Delay: time.Hour},
+	})
+
+	start := time.Now()
+	resp, err := model.Stream(context.Background(), nil, api.CallOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, events, collect(resp))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resp, err = model.Stream(ctx, nil, api.CallOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []api.StreamEvent{&api.ErrorEvent{Err: context.Canceled}}, collect(resp))
+}
+
+func TestGenerateModel_Calls(t *testing.T) {
+	ctx := context.Background()
+	prompt := []api.Message{
+		&api.UserMessage{Content: []api.ContentBlock{&api.TextBlock{Text: "test"}}},
+	}
+	temperature := 0.5
+
+	model := NewGenerateModel(
+		[]MockResult{{Response: &api.Response{}}},
+		WithStreams(MockStream{}),
+	)
+	_, err := model.Generate(ctx, prompt, api.CallOptions{MaxOutputTokens: 10})
+	require.NoError(t, err)
+	_, err = model.Stream(ctx, prompt, api.CallOptions{Temperature: &temperature})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Call{
+		{Prompt: prompt, Options: api.CallOptions{MaxOutputTokens: 10}},
+		{Prompt: prompt, Options: api.CallOptions{Temperature: &temperature}, Stream: true},
+	}, model.Calls())
+	model.AssertCount(t)
+}
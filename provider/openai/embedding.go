@@ -70,7 +70,7 @@ func (m *EmbeddingModel) DoEmbed(
 
 	resp, err := m.pc.client.Embeddings.New(ctx, embeddingParams, openaiOpts...)
 	if err != nil {
-		return api.DenseEmbeddingResponse{}, err
+		return api.DenseEmbeddingResponse{}, codec.DecodeError(err)
 	}
 
 	return codec.DecodeEmbedding(resp)
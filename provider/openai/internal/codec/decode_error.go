@@ -0,0 +1,113 @@
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/openai/openai-go/v2"
+	"github.com/tidwall/gjson"
+	"go.jetify.com/ai/api"
+)
+
+// DecodeError converts an error returned by the OpenAI SDK for a failed HTTP
+// request into an *api.APICallError, so that it can be retried and inspected
+// with errors.As. Other errors are returned unchanged.
+func DecodeError(err error) error {
+	var openaiErr *openai.Error
+	if !errors.As(err, &openaiErr) {
+		return err
+	}
+
+	body := strings.TrimSpace(openaiErr.RawJSON())
+	status := http.StatusText(openaiErr.StatusCode)
+	if openaiErr.Response != nil {
+		status = openaiErr.Response.Status
+	}
+	message := status
+	if openaiErr.Message != "" {
+		message = fmt.Sprintf("%s: %s", status, openaiErr.Message)
+	} else if body != "" {
+		message = fmt.Sprintf("%s: %s", status, body)
+	}
+
+	apiErr := &api.APICallError{
+		AISDKError:   api.NewAISDKError("AI_APICallError", message, err),
+		Request:      openaiErr.Request,
+		StatusCode:   openaiErr.StatusCode,
+		Response:     openaiErr.Response,
+		ResponseBody: []byte(body),
+		Data:         openaiErr,
+	}
+	if openaiErr.Request != nil {
+		apiErr.URL = openaiErr.Request.URL
+		apiErr.RequestBody = requestBody(openaiErr.Request)
+	}
+	return apiErr
+}
+
+// streamErrorPrefix is how the OpenAI SDK reports an event with an error
+// field received in the middle of a stream, followed by the JSON of the error.
+const streamErrorPrefix = "received error while streaming: "
+
+// streamErrorStatus maps the error codes OpenAI reports in stream error events
+// to the status code the same error has when it is returned before the stream
+// starts, so that retryable errors are recognized as such.
+var streamErrorStatus = map[string]int{
+	"rate_limit_exceeded": http.StatusTooManyRequests,
+	"server_error":        http.StatusInternalServerError,
+}
+
+// decodeStreamError converts an error that ended a stream into an
+// *api.APICallError. Errors without a known error code, such as a broken
+// connection, have no status code.
+func decodeStreamError(err error) error {
+	var apiErr *api.APICallError
+	if errors.As(DecodeError(err), &apiErr) {
+		return apiErr
+	}
+
+	body, isErrorEvent := strings.CutPrefix(err.Error(), streamErrorPrefix)
+	if !isErrorEvent {
+		return &api.APICallError{
+			AISDKError: api.NewAISDKError("AI_APICallError", fmt.Sprintf("reading stream: %v", err), err),
+		}
+	}
+
+	code := gjson.Get(body, "code").String()
+	message := gjson.Get(body, "message").String()
+	if message == "" {
+		message = body
+	}
+	return newStreamAPICallError(code, message, body, err)
+}
+
+// newStreamAPICallError constructs an APICallError for an error with the
+// given code received after a streaming response has started.
+func newStreamAPICallError(code, message, body string, cause error) *api.APICallError {
+	if code != "" {
+		message = fmt.Sprintf("%s: %s", code, message)
+	}
+	return &api.APICallError{
+		AISDKError:   api.NewAISDKError("AI_APICallError", message, cause),
+		StatusCode:   streamErrorStatus[code],
+		ResponseBody: []byte(body),
+		Data:         code,
+	}
+}
+
+// requestBody returns a copy of the body of req, if it can be read again.
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+	content, _ := io.ReadAll(body)
+	return content
+}
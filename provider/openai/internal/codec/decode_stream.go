@@ -23,16 +23,23 @@ type StreamReader interface {
 
 // DecodeStream converts an OpenAI SSE stream to our API's StreamResponse.
 // This is the main entry point for decoding OpenAI streams.
-func DecodeStream(stream StreamReader) (*api.StreamResponse, error) {
-	decoder := &streamDecoder{}
+// The warnings are sent with the StreamStartEvent that opens the stream.
+func DecodeStream(stream StreamReader, warnings []api.CallWarning) (*api.StreamResponse, error) {
+	decoder := &streamDecoder{warnings: warnings}
 	return decoder.DecodeStream(stream)
 }
 
 // streamDecoder maintains state while decoding a stream of OpenAI events.
 type streamDecoder struct {
+	// Warnings for the call, sent with the StreamStartEvent
+	warnings []api.CallWarning
+
 	// Map from output index to tool call information
 	ongoingToolCalls map[int64]toolCallInfo
 
+	// Map from output index to the ID of the ongoing text or reasoning block
+	ongoingBlocks map[int64]string
+
 	// Tracking response metadata
 	responseID string
 
@@ -66,6 +73,9 @@ func (d *streamDecoder) DecodeStream(stream StreamReader) (*api.StreamResponse,
 	if d.ongoingToolCalls == nil {
 		d.ongoingToolCalls = make(map[int64]toolCallInfo)
 	}
+	if d.ongoingBlocks == nil {
+		d.ongoingBlocks = make(map[int64]string)
+	}
 
 	return &api.StreamResponse{
 		Stream: d.decodeEvents(stream),
@@ -75,17 +85,22 @@ func (d *streamDecoder) DecodeStream(stream StreamReader) (*api.StreamResponse,
 // decodeEvents returns an iterator that yields events from the OpenAI stream.
 func (d *streamDecoder) decodeEvents(stream StreamReader) iter.Seq[api.StreamEvent] {
 	return func(yield func(api.StreamEvent) bool) {
+		warnings := d.warnings
+		if warnings == nil {
+			warnings = []api.CallWarning{}
+		}
+		if !yield(&api.StreamStartEvent{Warnings: warnings}) {
+			return
+		}
+
 		// Process all events directly in the iterator function
 		for stream.Next() {
 			// Get the current event
 			event := stream.Current()
 
-			// Process the event
-			decodedEvent := d.decodeEvent(event)
-
-			// Only send non-nil events (this handles events that are processed internally but don't yield output,
-			// and also events that are explicitly decoded to an api.ErrorEvent)
-			if decodedEvent != nil {
+			// Process the event. Events that are processed internally but
+			// don't yield output are decoded to no events at all.
+			for _, decodedEvent := range d.decodeEvent(event) {
 				if !yield(decodedEvent) {
 					return
 				}
@@ -95,7 +110,7 @@ func (d *streamDecoder) decodeEvents(stream StreamReader) iter.Seq[api.StreamEve
 		// Check if we encountered an error from the underlying stream
 		if err := stream.Err(); err != nil && !errors.Is(err, io.EOF) {
 			// Yield this as a final error event
-			if !yield(&api.ErrorEvent{Err: err}) {
+			if !yield(&api.ErrorEvent{Err: decodeStreamError(err)}) {
 				// Consumer doesn't want more, even this error
 				return
 			}
@@ -133,31 +148,31 @@ func (d *streamDecoder) decodeEvents(stream StreamReader) iter.Seq[api.StreamEve
 }
 
 // decodeEvent translates an OpenAI event to our API event format.
-// It now returns a single api.StreamEvent, which can be an api.ErrorEvent
+// It returns the decoded events, which can include an api.ErrorEvent
 // if an internal processing error occurs or if an OpenAI error event is decoded.
-// It returns nil if the event is known but intentionally not exposed to clients.
-func (d *streamDecoder) decodeEvent(event responses.ResponseStreamEventUnion) api.StreamEvent {
+// It returns no events if the event is known but intentionally not exposed to clients.
+func (d *streamDecoder) decodeEvent(event responses.ResponseStreamEventUnion) []api.StreamEvent {
 	switch event.Type {
 	case "response.output_text.delta":
-		return d.decodeTextDelta(event)
+		return events(d.decodeTextDelta(event))
 	case "response.output_item.added":
 		return d.decodeOutputItemAdded(event)
 	case "response.function_call_arguments.delta":
-		return d.decodeFunctionCallArgumentsDelta(event)
+		return events(d.decodeFunctionCallArgumentsDelta(event))
 	case "response.output_item.done":
 		return d.decodeOutputItemDone(event)
 	case "response.created":
-		return d.decodeResponseCreated(event)
+		return events(d.decodeResponseCreated(event))
 	case "response.completed":
-		return d.decodeResponseCompleted(event)
+		return events(d.decodeResponseCompleted(event))
 	case "response.failed", "response.incomplete":
-		return d.decodeResponseFailedOrIncomplete(event)
+		return events(d.decodeResponseFailedOrIncomplete(event))
 	case "response.reasoning_summary_text.delta":
-		return d.decodeReasoningSummaryTextDelta(event)
+		return events(d.decodeReasoningSummaryTextDelta(event))
 	case "response.output_text.annotation.added":
-		return d.decodeOutputTextAnnotationAdded(event)
+		return events(d.decodeOutputTextAnnotationAdded(event))
 	case "error":
-		return d.decodeError(event)
+		return events(d.decodeError(event))
 	// Event types that we're aware of but don't yet expose to clients:
 	case "response.in_progress",
 		"response.content_part.done",
@@ -215,6 +230,14 @@ func (d *streamDecoder) decodeEvent(event responses.ResponseStreamEventUnion) ap
 	}
 }
 
+// events returns the given event as a slice, or nil if the event is nil.
+func events(event api.StreamEvent) []api.StreamEvent {
+	if event == nil {
+		return nil
+	}
+	return []api.StreamEvent{event}
+}
+
 // decodeTextDelta handles text delta events
 func (d *streamDecoder) decodeTextDelta(event responses.ResponseStreamEventUnion) api.StreamEvent {
 	textDelta := event.AsResponseOutputTextDelta()
@@ -223,12 +246,14 @@ func (d *streamDecoder) decodeTextDelta(event responses.ResponseStreamEventUnion
 	}
 }
 
-// decodeOutputItemAdded handles output item added events
-func (d *streamDecoder) decodeOutputItemAdded(event responses.ResponseStreamEventUnion) api.StreamEvent {
+// decodeOutputItemAdded handles output item added events, which start a text
+// block, a reasoning block or the input of a tool call.
+func (d *streamDecoder) decodeOutputItemAdded(event responses.ResponseStreamEventUnion) []api.StreamEvent {
 	itemAdded := event.AsResponseOutputItemAdded()
 	item := itemAdded.Item
 
-	if item.Type == "function_call" {
+	switch item.Type {
+	case "function_call":
 		funcCall := item.AsFunctionCall()
 
 		// Store the tool call information for later deltas
@@ -238,15 +263,24 @@ func (d *streamDecoder) decodeOutputItemAdded(event responses.ResponseStreamEven
 		}
 		d.hasToolCalls = true
 
-		return &api.ToolCallDeltaEvent{
+		decoded := []api.StreamEvent{&api.ToolInputStartEvent{
 			ToolCallID: funcCall.CallID,
 			ToolName:   funcCall.Name,
-			ArgsDelta:  []byte(funcCall.Arguments),
+		}}
+		if funcCall.Arguments != "" {
+			decoded = append(decoded, &api.ToolCallDeltaEvent{
+				ToolCallID: funcCall.CallID,
+				ToolName:   funcCall.Name,
+				ArgsDelta:  []byte(funcCall.Arguments),
+			})
 		}
-	}
-
-	if item.Type == "reasoning" {
-		return nil
+		return decoded
+	case "message":
+		d.ongoingBlocks[itemAdded.OutputIndex] = item.ID
+		return events(&api.TextStartEvent{ID: item.ID})
+	case "reasoning":
+		d.ongoingBlocks[itemAdded.OutputIndex] = item.ID
+		return events(&api.ReasoningStartEvent{ID: item.ID})
 	}
 
 	return nil
@@ -258,7 +292,8 @@ func (d *streamDecoder) decodeFunctionCallArgumentsDelta(event responses.Respons
 	toolCall, exists := d.ongoingToolCalls[argsDelta.OutputIndex]
 
 	if !exists {
-		return &api.ErrorEvent{Err: fmt.Errorf("received function call arguments delta for unknown output index: %d", argsDelta.OutputIndex)}
+		return &api.ErrorEvent{Err: api.NewInvalidResponseDataError(argsDelta.RawJSON(),
+			fmt.Sprintf("received function call arguments delta for unknown output index: %d", argsDelta.OutputIndex))}
 	}
 
 	return &api.ToolCallDeltaEvent{
@@ -268,23 +303,50 @@ func (d *streamDecoder) decodeFunctionCallArgumentsDelta(event responses.Respons
 	}
 }
 
-// decodeOutputItemDone handles output item done events
-func (d *streamDecoder) decodeOutputItemDone(event responses.ResponseStreamEventUnion) api.StreamEvent {
+// decodeOutputItemDone handles output item done events, which end the block
+// or tool call started by the matching output item added event.
+func (d *streamDecoder) decodeOutputItemDone(event responses.ResponseStreamEventUnion) []api.StreamEvent {
 	itemDone := event.AsResponseOutputItemDone()
 	item := itemDone.Item
 
-	if item.Type == "function_call" {
+	switch item.Type {
+	case "function_call":
 		funcCall := item.AsFunctionCall()
+		// End the tool input under the ID it was started with
+		inputID := funcCall.CallID
+		if toolCall, ok := d.ongoingToolCalls[itemDone.OutputIndex]; ok {
+			inputID = toolCall.toolCallID
+		}
 		delete(d.ongoingToolCalls, itemDone.OutputIndex)
-		return &api.ToolCallEvent{
-			ToolCallID: funcCall.CallID,
-			ToolName:   funcCall.Name,
-			Args:       json.RawMessage(funcCall.Arguments),
+		return []api.StreamEvent{
+			&api.ToolInputEndEvent{ToolCallID: inputID},
+			&api.ToolCallEvent{
+				ToolCallID: funcCall.CallID,
+				ToolName:   funcCall.Name,
+				Args:       json.RawMessage(funcCall.Arguments),
+			},
+		}
+	case "message":
+		if id, ok := d.endBlock(itemDone.OutputIndex); ok {
+			return events(&api.TextEndEvent{ID: id})
+		}
+	case "reasoning":
+		if id, ok := d.endBlock(itemDone.OutputIndex); ok {
+			return events(&api.ReasoningEndEvent{ID: id})
 		}
 	}
 	return nil
 }
 
+// endBlock returns the ID of the block started at the given output index, so
+// that start and end events always share an ID, and forgets the block. It
+// reports false if no block was started at that index.
+func (d *streamDecoder) endBlock(outputIndex int64) (string, bool) {
+	id, ok := d.ongoingBlocks[outputIndex]
+	delete(d.ongoingBlocks, outputIndex)
+	return id, ok
+}
+
 // decodeResponseCreated handles response created events
 func (d *streamDecoder) decodeResponseCreated(event responses.ResponseStreamEventUnion) api.StreamEvent {
 	created := event.AsResponseCreated()
@@ -405,6 +467,6 @@ func (d *streamDecoder) decodeOutputTextAnnotationAdded(event responses.Response
 func (d *streamDecoder) decodeError(event responses.ResponseStreamEventUnion) api.StreamEvent {
 	errorEvent := event.AsError()
 	return &api.ErrorEvent{
-		Err: fmt.Errorf("%s: %s", errorEvent.Code, errorEvent.Message),
+		Err: newStreamAPICallError(errorEvent.Code, errorEvent.Message, errorEvent.RawJSON(), nil),
 	}
 }
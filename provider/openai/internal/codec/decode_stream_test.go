@@ -2,6 +2,7 @@ package codec
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
@@ -25,6 +26,7 @@ func TestDecodeStreamEvents(t *testing.T) {
 				`{"type": "response.completed", "response": {"usage": {"input_tokens": 10, "output_tokens": 5}}}`,
 			},
 			want: []api.StreamEvent{
+				&api.StreamStartEvent{Warnings: []api.CallWarning{}},
 				&api.ResponseMetadataEvent{
 					ID:        "resp_123",
 					Timestamp: time.Date(2025, 3, 6, 13, 50, 19, 0, time.UTC),
@@ -60,11 +62,16 @@ func TestDecodeStreamEvents(t *testing.T) {
 				`{"type": "response.completed", "response": {"usage": {"input_tokens": 15, "output_tokens": 8}}}`,
 			},
 			want: []api.StreamEvent{
+				&api.StreamStartEvent{Warnings: []api.CallWarning{}},
 				&api.ResponseMetadataEvent{
 					ID:        "resp_456",
 					Timestamp: time.Date(2025, 3, 6, 13, 50, 19, 0, time.UTC),
 					ModelID:   "gpt-4",
 				},
+				&api.ToolInputStartEvent{
+					ToolCallID: "call_123",
+					ToolName:   "get_weather",
+				},
 				&api.ToolCallDeltaEvent{
 					ToolCallID: "call_123",
 					ToolName:   "get_weather",
@@ -89,6 +96,32 @@ func TestDecodeStreamEvents(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "reasoning and text blocks",
+			eventJSONs: []string{
+				`{"type": "response.output_item.added", "output_index": 0, "item": {"id": "rs_1", "type": "reasoning", "summary": []}}`,
+				`{"type": "response.reasoning_summary_text.delta", "item_id": "rs_1", "output_index": 0, "delta": "Thinking"}`,
+				`{"type": "response.output_item.done", "output_index": 0, "item": {"id": "rs_1", "type": "reasoning", "summary": []}}`,
+				`{"type": "response.output_item.added", "output_index": 1, "item": {"id": "msg_1", "type": "message", "role": "assistant", "content": []}}`,
+				`{"type": "response.output_text.delta", "item_id": "msg_1", "output_index": 1, "delta": "Hello"}`,
+				`{"type": "response.output_item.done", "output_index": 1, "item": {"id": "msg_1", "type": "message", "role": "assistant", "content": []}}`,
+			},
+			want: []api.StreamEvent{
+				&api.StreamStartEvent{Warnings: []api.CallWarning{}},
+				&api.ReasoningStartEvent{ID: "rs_1"},
+				&api.ReasoningEvent{TextDelta: "Thinking"},
+				&api.ReasoningEndEvent{ID: "rs_1"},
+				&api.TextStartEvent{ID: "msg_1"},
+				&api.TextDeltaEvent{TextDelta: "Hello"},
+				&api.TextEndEvent{ID: "msg_1"},
+				&api.FinishEvent{
+					FinishReason: api.FinishReasonStop,
+					ProviderMetadata: api.NewProviderMetadata(map[string]any{
+						"openai": &Metadata{},
+					}),
+				},
+			},
+		},
 	}
 
 	for _, testCase := range tests {
@@ -106,7 +139,7 @@ func TestDecodeStreamEvents(t *testing.T) {
 			stream := newMockStreamReader(events)
 
 			// Decode the stream
-			result, err := DecodeStream(stream)
+			result, err := DecodeStream(stream, nil)
 			require.NoError(t, err)
 
 			// Collect all events from the stream
@@ -121,6 +154,79 @@ func TestDecodeStreamEvents(t *testing.T) {
 	}
 }
 
+func TestDecodeStreamEvents_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventJSONs []string
+		err        error
+		check      func(t *testing.T, err error)
+	}{
+		{
+			name:       "error event",
+			eventJSONs: []string{`{"type": "error", "code": "rate_limit_exceeded", "message": "Slow down", "param": ""}`},
+			check: func(t *testing.T, err error) {
+				var apiErr *api.APICallError
+				require.ErrorAs(t, err, &apiErr)
+				assert.Equal(t, "rate_limit_exceeded: Slow down", apiErr.Message)
+				assert.Equal(t, 429, apiErr.StatusCode)
+				assert.True(t, apiErr.IsRetryable())
+			},
+		},
+		{
+			name: "error reported by the stream reader",
+			err:  errors.New(`received error while streaming: {"code":"server_error","message":"Boom"}`),
+			check: func(t *testing.T, err error) {
+				var apiErr *api.APICallError
+				require.ErrorAs(t, err, &apiErr)
+				assert.Equal(t, "server_error: Boom", apiErr.Message)
+				assert.Equal(t, 500, apiErr.StatusCode)
+			},
+		},
+		{
+			name: "connection error",
+			err:  errors.New("connection reset"),
+			check: func(t *testing.T, err error) {
+				var apiErr *api.APICallError
+				require.ErrorAs(t, err, &apiErr)
+				assert.Equal(t, "reading stream: connection reset", apiErr.Message)
+				assert.False(t, apiErr.IsRetryable())
+			},
+		},
+		{
+			name:       "arguments delta for unknown output index",
+			eventJSONs: []string{`{"type": "response.function_call_arguments.delta", "output_index": 3, "delta": "{}"}`},
+			check: func(t *testing.T, err error) {
+				var dataErr *api.InvalidResponseDataError
+				require.ErrorAs(t, err, &dataErr)
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			var events []responses.ResponseStreamEventUnion
+			for _, jsonStr := range testCase.eventJSONs {
+				var event responses.ResponseStreamEventUnion
+				require.NoError(t, json.Unmarshal([]byte(jsonStr), &event))
+				events = append(events, event)
+			}
+			stream := newMockStreamReader(events)
+			stream.err = testCase.err
+
+			result, err := DecodeStream(stream, nil)
+			require.NoError(t, err)
+
+			var streamErr error
+			for event := range result.Stream {
+				if errEvent, ok := event.(*api.ErrorEvent); ok {
+					streamErr = errEvent.Err
+				}
+			}
+			testCase.check(t, streamErr)
+		})
+	}
+}
+
 // mockStreamReader implements the StreamReader interface for testing
 type mockStreamReader struct {
 	events []responses.ResponseStreamEventUnion
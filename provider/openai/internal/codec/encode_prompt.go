@@ -329,6 +329,9 @@ func EncodeAssistantMessage(message *api.AssistantMessage) ([]responses.Response
 			if item != nil {
 				items = append(items, *item)
 			}
+		case *api.ReasoningBlock:
+			// Reasoning summaries cannot be sent back to the Responses API
+			// without the reasoning item they came from, so they are dropped.
 		default:
 			return nil, fmt.Errorf("unsupported content block type in assistant message: %T", block)
 		}
@@ -776,6 +776,17 @@ var assistantMessageTests = []testCase{
 		},
 		expectedError: "encoding text block: text block cannot be nil",
 	},
+	{
+		name: "assistant message with reasoning block",
+		input: []api.Message{
+			&api.AssistantMessage{
+				Content: []api.ContentBlock{
+					&api.ReasoningBlock{Text: "thinking"},
+				},
+			},
+		},
+		expectedMessages: []string{},
+	},
 	{
 		name: "assistant message with unsupported block type",
 		input: []api.Message{
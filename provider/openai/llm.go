@@ -38,6 +38,12 @@ func (m *LanguageModel) ModelID() string {
 	return m.modelID
 }
 
+// DefaultObjectGenerationMode returns the default mode for object generation.
+// OpenAI supports JSON schema response formats natively.
+func (m *LanguageModel) DefaultObjectGenerationMode() api.ObjectGenerationMode {
+	return api.ObjectGenerationModeJSON
+}
+
 func (m *LanguageModel) SupportedUrls() []api.SupportedURL {
 	// TODO: Make configurable via the constructor.
 	return []api.SupportedURL{
@@ -60,7 +66,7 @@ func (m *LanguageModel) Generate(
 
 	openaiResponse, err := m.pc.client.Responses.New(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, codec.DecodeError(err)
 	}
 
 	response, err := codec.DecodeResponse(openaiResponse)
@@ -75,15 +81,13 @@ func (m *LanguageModel) Generate(
 func (m *LanguageModel) Stream(
 	ctx context.Context, prompt []api.Message, opts api.CallOptions,
 ) (*api.StreamResponse, error) {
-	// TODO: add warnings to the stream response by adding an initial StreamStart event
-	// (it could happen inside of codec.Encode)
-	params, _, err := codec.Encode(m.modelID, prompt, opts)
+	params, warnings, err := codec.Encode(m.modelID, prompt, opts)
 	if err != nil {
 		return nil, err
 	}
 
 	stream := m.pc.client.Responses.NewStreaming(ctx, params)
-	response, err := codec.DecodeStream(stream)
+	response, err := codec.DecodeStream(stream, warnings)
 	if err != nil {
 		return nil, err
 	}
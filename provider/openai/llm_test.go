@@ -1858,17 +1858,20 @@ func TestStream(t *testing.T) {
 				},
 			},
 			expectedEvents: []api.StreamEvent{
+				&api.StreamStartEvent{Warnings: []api.CallWarning{}},
 				&api.ResponseMetadataEvent{
 					ID:        "resp_67c9a81b6a048190a9ee441c5755a4e8",
 					ModelID:   "gpt-4o-2024-07-18",
 					Timestamp: time.Date(2025, 3, 6, 13, 50, 19, 0, time.UTC),
 				},
+				&api.TextStartEvent{ID: "msg_67c9a81dea8c8190b79651a2b3adf91e"},
 				&api.TextDeltaEvent{
 					TextDelta: "Hello,",
 				},
 				&api.TextDeltaEvent{
 					TextDelta: " World!",
 				},
+				&api.TextEndEvent{ID: "msg_67c9a81dea8c8190b79651a2b3adf91e"},
 				&api.FinishEvent{
 					FinishReason: api.FinishReasonStop,
 					Usage: api.Usage{
@@ -2044,14 +2047,17 @@ func TestStream(t *testing.T) {
 				},
 			},
 			expectedEvents: []api.StreamEvent{
+				&api.StreamStartEvent{Warnings: []api.CallWarning{}},
 				&api.ResponseMetadataEvent{
 					ID:        "resp_67c9a81b6a048190a9ee441c5755a4e8",
 					ModelID:   "gpt-4o-2024-07-18",
 					Timestamp: time.Date(2025, 3, 6, 13, 50, 19, 0, time.UTC),
 				},
+				&api.TextStartEvent{ID: "msg_67c9a81dea8c8190b79651a2b3adf91e"},
 				&api.TextDeltaEvent{
 					TextDelta: "Hello,",
 				},
+				&api.TextEndEvent{ID: "msg_67c9a81dea8c8190b79651a2b3adf91e"},
 				&api.FinishEvent{
 					FinishReason: api.FinishReasonLength,
 					Usage: api.Usage{
@@ -2325,30 +2331,30 @@ func TestStream(t *testing.T) {
 				},
 			},
 			expectedEvents: []api.StreamEvent{
+				&api.StreamStartEvent{Warnings: []api.CallWarning{}},
 				&api.ResponseMetadataEvent{
 					ID:        "resp_67cb13a755c08190acbe3839a49632fc",
 					ModelID:   "gpt-4o-2024-07-18",
 					Timestamp: time.Date(2025, 3, 7, 15, 41, 27, 0, time.UTC),
 				},
-				&api.ToolCallDeltaEvent{
+				&api.ToolInputStartEvent{
 					ToolCallID: "call_6KxSghkb4MVnunFH2TxPErLP",
 					ToolName:   "currentLocation",
-					ArgsDelta:  []byte(""),
 				},
 				&api.ToolCallDeltaEvent{
 					ToolCallID: "call_6KxSghkb4MVnunFH2TxPErLP",
 					ToolName:   "currentLocation",
 					ArgsDelta:  []byte("{}"),
 				},
+				&api.ToolInputEndEvent{ToolCallID: "call_6KxSghkb4MVnunFH2TxPErLP"},
 				&api.ToolCallEvent{
 					ToolCallID: "call_pgjcAI4ZegMkP6bsAV7sfrJA",
 					ToolName:   "currentLocation",
 					Args:       json.RawMessage(`{}`),
 				},
-				&api.ToolCallDeltaEvent{
+				&api.ToolInputStartEvent{
 					ToolCallID: "call_Dg6WUmFHNeR5JxX1s53s1G4b",
 					ToolName:   "weather",
-					ArgsDelta:  []byte(""),
 				},
 				&api.ToolCallDeltaEvent{
 					ToolCallID: "call_Dg6WUmFHNeR5JxX1s53s1G4b",
@@ -2375,6 +2381,7 @@ func TestStream(t *testing.T) {
 					ToolName:   "weather",
 					ArgsDelta:  []byte("\"}\""),
 				},
+				&api.ToolInputEndEvent{ToolCallID: "call_Dg6WUmFHNeR5JxX1s53s1G4b"},
 				&api.ToolCallEvent{
 					ToolCallID: "call_X2PAkDJInno9VVnNkDrfhboW",
 					ToolName:   "weather",
@@ -2751,11 +2758,13 @@ func TestStream(t *testing.T) {
 				},
 			},
 			expectedEvents: []api.StreamEvent{
+				&api.StreamStartEvent{Warnings: []api.CallWarning{}},
 				&api.ResponseMetadataEvent{
 					ID:        "resp_67cf3390786881908b27489d7e8cfb6b",
 					ModelID:   "gpt-4o-mini-2024-07-18",
 					Timestamp: time.Date(2025, 3, 10, 18, 46, 40, 0, time.UTC),
 				},
+				&api.TextStartEvent{ID: "msg_67cf33924ea88190b8c12bf68c1f6416"},
 				&api.TextDeltaEvent{
 					TextDelta: "Last week",
 				},
@@ -2787,6 +2796,7 @@ func TestStream(t *testing.T) {
 				&api.TextDeltaEvent{
 					TextDelta: ".",
 				},
+				&api.TextEndEvent{ID: "msg_67cf33924ea88190b8c12bf68c1f6416"},
 				&api.FinishEvent{
 					FinishReason: api.FinishReasonStop,
 					Usage: api.Usage{
@@ -2912,6 +2922,7 @@ func TestStream(t *testing.T) {
 				},
 			},
 			expectedEvents: []api.StreamEvent{
+				&api.StreamStartEvent{Warnings: []api.CallWarning{}},
 				&api.ResponseMetadataEvent{
 					ID:        "resp_67c9a81b6a048190a9ee441c5755a4e8",
 					ModelID:   "o3-mini-2025-01-31",
@@ -2926,6 +2937,7 @@ func TestStream(t *testing.T) {
 				&api.ReasoningEvent{
 					TextDelta: "**Investigating burrito origins**\n\nThere's a fascinating debate about who created the Mission burrito.",
 				},
+				&api.TextStartEvent{ID: "msg_67c9a81dea8c8190b79651a2b3adf91e"},
 				&api.TextDeltaEvent{
 					TextDelta: "Taqueria La Cumbre",
 				},
@@ -2,8 +2,8 @@ package openrouter
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
+	"strconv"
 
 	"go.jetify.com/ai/api"
 )
@@ -27,8 +27,8 @@ func parseOpenRouterErrorJSON(body []byte) (*openRouterErrorData, error) {
 	return &parsed, nil
 }
 
-// OpenRouterFailedResponseHandler constructs an APICallError from a non-2xx OpenRouter response.
-func OpenRouterFailedResponseHandler(resp *http.Response, rawBody []byte, requestBody any) error {
+// failedResponseHandler constructs an APICallError from a non-2xx OpenRouter response.
+func failedResponseHandler(resp *http.Response, rawBody []byte, requestBody any) error {
 	parsed, err := parseOpenRouterErrorJSON(rawBody)
 	if err == nil {
 		return &api.APICallError{
@@ -43,10 +43,35 @@ func OpenRouterFailedResponseHandler(resp *http.Response, rawBody []byte, reques
 
 	// Fallback if we cannot parse the error JSON
 	return &api.APICallError{
-		AISDKError: api.NewAISDKError("AI_APICallError", fmt.Sprintf("%d %s", resp.StatusCode, resp.Status), err),
+		AISDKError: api.NewAISDKError("AI_APICallError", resp.Status, err),
 		URL:        resp.Request.URL,
 		Request:    resp.Request,
 		StatusCode: resp.StatusCode,
 		Response:   resp,
 	}
 }
+
+// newStreamAPICallError constructs an APICallError from an error chunk received
+// after a streaming response has started. The status code of the response is
+// 200 by then, so the error code of the chunk is used when it is a status code.
+func newStreamAPICallError(resp *http.Response, streamErr *chatStreamError, rawChunk []byte) *api.APICallError {
+	statusCode := resp.StatusCode
+	switch code := streamErr.Code.(type) {
+	case float64:
+		statusCode = int(code)
+	case string:
+		if parsed, err := strconv.Atoi(code); err == nil {
+			statusCode = parsed
+		}
+	}
+
+	return &api.APICallError{
+		AISDKError:   api.NewAISDKError("AI_APICallError", streamErr.Message, nil),
+		URL:          resp.Request.URL,
+		Request:      resp.Request,
+		StatusCode:   statusCode,
+		Response:     resp,
+		ResponseBody: rawChunk,
+		Data:         streamErr,
+	}
+}
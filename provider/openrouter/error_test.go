@@ -3,6 +3,7 @@ package openrouter
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -92,7 +93,7 @@ func TestParseOpenRouterErrorJSON(t *testing.T) {
 	}
 }
 
-func TestOpenRouterFailedResponseHandler(t *testing.T) {
+func TestFailedResponseHandler(t *testing.T) {
 	tests := []struct {
 		name        string
 		statusCode  int
@@ -146,13 +147,13 @@ func TestOpenRouterFailedResponseHandler(t *testing.T) {
 			req := &http.Request{URL: url}
 			resp := &http.Response{
 				StatusCode: tt.statusCode,
-				Status:     http.StatusText(tt.statusCode),
+				Status:     fmt.Sprintf("%d %s", tt.statusCode, http.StatusText(tt.statusCode)),
 				Request:    req,
 				Body:       io.NopCloser(bytes.NewBufferString(tt.body)),
 			}
 
 			// Call the handler
-			err := OpenRouterFailedResponseHandler(resp, []byte(tt.body), nil)
+			err := failedResponseHandler(resp, []byte(tt.body), nil)
 
 			// Use errors.As instead of type assertion
 			var apiErr *api.APICallError
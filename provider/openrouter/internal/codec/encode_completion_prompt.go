@@ -1,4 +1,4 @@
-package openrouter
+package codec
 
 import (
 	"strings"
@@ -20,9 +20,9 @@ type CompletionPromptOptions struct {
 	Assistant   string // defaults to "assistant" if empty
 }
 
-// ConvertToOpenRouterCompletionPrompt converts an AI SDK prompt into OpenRouter's completion format.
+// EncodeCompletionPrompt converts an AI SDK prompt into OpenRouter's completion format.
 // It returns the formatted prompt string and optional stop sequences.
-func ConvertToOpenRouterCompletionPrompt(opts CompletionPromptOptions) (string, []string, error) {
+func EncodeCompletionPrompt(opts CompletionPromptOptions) (string, []string, error) {
 	if opts.User == "" {
 		opts.User = "user"
 	}
@@ -151,6 +151,8 @@ func gatherAssistantText(msg *api.AssistantMessage) (string, error) {
 			b.WriteString(block.Text)
 		case *api.ToolCallBlock:
 			return "", api.NewUnsupportedFunctionalityError("tool-call messages", "")
+		case *api.ReasoningBlock:
+			// Reasoning is not part of the completion prompt.
 		default:
 			return "", api.NewUnsupportedFunctionalityError("unknown content type", "")
 		}
@@ -8,7 +8,7 @@ import (
 	"fmt"
 
 	"go.jetify.com/ai/api"
-	"go.jetify.com/ai/provider/internal/openrouter/client"
+	"go.jetify.com/ai/provider/openrouter/client"
 )
 
 // EncodePrompt converts an AI SDK prompt into OpenRouter's chat message format
@@ -172,6 +172,8 @@ func encodeAssistantMessage(msg *api.AssistantMessage) (*client.AssistantMessage
 				return nil, err
 			}
 			toolCalls = append(toolCalls, toolCall)
+		case *api.ReasoningBlock:
+			// Reasoning is not part of the chat completions prompt.
 		default:
 			return nil, fmt.Errorf("unsupported assistant content block type: %T", block)
 		}
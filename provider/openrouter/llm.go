@@ -10,50 +10,67 @@ import (
 	"strings"
 
 	"go.jetify.com/ai/api"
-	"go.jetify.com/ai/provider/internal/openrouter/client"
-	"go.jetify.com/ai/provider/internal/openrouter/codec"
+	"go.jetify.com/ai/provider/openrouter/client"
+	"go.jetify.com/ai/provider/openrouter/internal/codec"
 )
 
-// OpenRouterChatLanguageModel implements the chat-based language model for OpenRouter.
-type OpenRouterChatLanguageModel struct {
-	provider *OpenRouterProvider
-	modelID  string
-	settings *client.ChatSettings
+// LanguageModel implements the chat-based language model for OpenRouter.
+type LanguageModel struct {
+	modelID string
+	pc      ProviderConfig
 }
 
-// NewOpenRouterChatLanguageModel creates a new OpenRouter chat language model.
-func NewOpenRouterChatLanguageModel(
-	provider *OpenRouterProvider,
-	modelID string,
-	settings *client.ChatSettings,
-) *OpenRouterChatLanguageModel {
-	return &OpenRouterChatLanguageModel{
-		provider: provider,
-		modelID:  modelID,
-		settings: settings,
+var _ api.LanguageModel = &LanguageModel{}
+
+// LanguageModel creates a new OpenRouter chat language model.
+func (p *Provider) LanguageModel(modelID string) (api.LanguageModel, error) {
+	model := &LanguageModel{
+		modelID: modelID,
+		pc: ProviderConfig{
+			providerName: fmt.Sprintf("%s.chat", p.name),
+			baseURL:      p.baseURL,
+			apiKey:       p.apiKey,
+			client:       p.client,
+			headers:      p.headers,
+			settings:     p.settings,
+		},
 	}
+
+	return model, nil
 }
 
 // SpecificationVersion returns the specification version of the language model.
-func (m *OpenRouterChatLanguageModel) SpecificationVersion() string {
+func (m *LanguageModel) SpecificationVersion() string {
 	return "v1"
 }
 
 // ProviderName returns the name of the provider.
-func (m *OpenRouterChatLanguageModel) ProviderName() string {
-	return "openrouter.chat"
+func (m *LanguageModel) ProviderName() string {
+	return m.pc.providerName
 }
 
 // ModelID returns the model identifier.
-func (m *OpenRouterChatLanguageModel) ModelID() string {
+func (m *LanguageModel) ModelID() string {
 	return m.modelID
 }
 
 // DefaultObjectGenerationMode returns the default mode for object generation.
-func (m *OpenRouterChatLanguageModel) DefaultObjectGenerationMode() api.ObjectGenerationMode {
+func (m *LanguageModel) DefaultObjectGenerationMode() api.ObjectGenerationMode {
 	return api.ObjectGenerationModeTool
 }
 
+// SupportedUrls returns the URL patterns supported by the model.
+func (m *LanguageModel) SupportedUrls() []api.SupportedURL {
+	return []api.SupportedURL{
+		{
+			MediaType: "image/*",
+			URLPatterns: []string{
+				"^https?://.*",
+			},
+		},
+	}
+}
+
 // TODO: we need to double check that each of these private structs:
 // - is not a duplicate of a struct we might have already defined
 // - that all the data returned by openrouter is something we are able to
@@ -77,9 +94,18 @@ type chatResponse struct {
 	} `json:"usage"`
 }
 
+// chatStreamError is the error sent in a stream chunk when the request fails
+// after the response has started. Code is usually an HTTP status code.
+type chatStreamError struct {
+	Message  string         `json:"message"`
+	Code     any            `json:"code,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
 // chatStreamResponse represents a streaming response chunk.
 type chatStreamResponse struct {
-	ID      string `json:"id,omitempty"`
+	ID      string           `json:"id,omitempty"`
+	Error   *chatStreamError `json:"error,omitempty"`
 	Choices []struct {
 		Delta struct {
 			Role      string          `json:"role,omitempty"`
@@ -110,7 +136,7 @@ type toolCallDelta struct {
 // buildRequestBody builds the request body for the OpenRouter API.
 //
 //nolint:revive // TODO: Refactor to reduce cognitive complexity (currently 41 > max 30)
-func (m *OpenRouterChatLanguageModel) buildRequestBody(
+func (m *LanguageModel) buildRequestBody(
 	prompt []api.Message,
 	options api.CallOptions,
 	stream bool,
@@ -135,27 +161,27 @@ func (m *OpenRouterChatLanguageModel) buildRequestBody(
 	}
 
 	// Add settings if present
-	if m.settings != nil {
-		if m.settings.LogitBias != nil {
-			body["logit_bias"] = m.settings.LogitBias
+	if settings := m.pc.settings; settings != nil {
+		if settings.LogitBias != nil {
+			body["logit_bias"] = settings.LogitBias
 		}
-		if m.settings.Logprobs.Enabled {
+		if settings.Logprobs != nil && settings.Logprobs.Enabled {
 			body["logprobs"] = true
-			if m.settings.Logprobs.TopK > 0 {
-				body["top_logprobs"] = m.settings.Logprobs.TopK
+			if settings.Logprobs.TopK > 0 {
+				body["top_logprobs"] = settings.Logprobs.TopK
 			}
 		}
-		if m.settings.User != nil {
-			body["user"] = *m.settings.User
+		if settings.User != nil {
+			body["user"] = *settings.User
 		}
-		if m.settings.ParallelToolCalls != nil {
-			body["parallel_tool_calls"] = *m.settings.ParallelToolCalls
+		if settings.ParallelToolCalls != nil {
+			body["parallel_tool_calls"] = *settings.ParallelToolCalls
 		}
-		if m.settings.IncludeReasoning != nil {
-			body["include_reasoning"] = *m.settings.IncludeReasoning
+		if settings.IncludeReasoning != nil {
+			body["include_reasoning"] = *settings.IncludeReasoning
 		}
-		if len(m.settings.Models) > 0 {
-			body["models"] = m.settings.Models
+		if len(settings.Models) > 0 {
+			body["models"] = settings.Models
 		}
 	}
 
@@ -223,8 +249,8 @@ func (m *OpenRouterChatLanguageModel) buildRequestBody(
 	return body, nil
 }
 
-// DoGenerate implements the non-streaming generation method.
-func (m *OpenRouterChatLanguageModel) DoGenerate(
+// Generate implements the non-streaming generation method.
+func (m *LanguageModel) Generate(
 	ctx context.Context,
 	prompt []api.Message,
 	opts api.CallOptions,
@@ -235,7 +261,7 @@ func (m *OpenRouterChatLanguageModel) DoGenerate(
 	}
 
 	// Make request
-	resp, err := m.provider.doJSONRequest(
+	resp, err := m.pc.doJSONRequest(
 		ctx,
 		http.MethodPost,
 		"/chat/completions",
@@ -255,7 +281,7 @@ func (m *OpenRouterChatLanguageModel) DoGenerate(
 
 	// Handle non-200 responses
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, OpenRouterFailedResponseHandler(resp, body, requestBody)
+		return nil, failedResponseHandler(resp, body, requestBody)
 	}
 
 	// Parse response
@@ -319,10 +345,10 @@ func (m *OpenRouterChatLanguageModel) DoGenerate(
 	return result, nil
 }
 
-// DoStream implements the streaming generation method.
+// Stream implements the streaming generation method.
 //
 //nolint:revive // TODO: Refactor to reduce cognitive complexity (currently 66 > max 30)
-func (m *OpenRouterChatLanguageModel) DoStream(
+func (m *LanguageModel) Stream(
 	ctx context.Context,
 	prompt []api.Message,
 	opts api.CallOptions,
@@ -339,7 +365,7 @@ func (m *OpenRouterChatLanguageModel) DoStream(
 	// - github.com/donovanhide/eventsource
 
 	// Make request
-	resp, err := m.provider.doJSONRequest(
+	resp, err := m.pc.doJSONRequest(
 		ctx,
 		http.MethodPost,
 		"/chat/completions",
@@ -350,16 +376,51 @@ func (m *OpenRouterChatLanguageModel) DoStream(
 		return nil, err
 	}
 
+	// Handle non-200 responses
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("read response body: %w", err)
+		}
+		return nil, failedResponseHandler(resp, body, requestBody)
+	}
+
 	// Create sequence for events
 	stream := func(yield func(api.StreamEvent) bool) {
 		defer func() { _ = resp.Body.Close() }()
 
 		scanner := bufio.NewScanner(resp.Body)
 		var toolCalls []client.ToolCall
+		var finishReason string
+		var usage api.Usage
 		// TODO: Add logprobs support
 		// var logprobs []api.LogProb
-		// TODO: Add usage support
-		// var usage api.Usage
+
+		// OpenRouter does not delimit blocks, so a block is open from its
+		// first delta until the other kind of block starts or the stream
+		// ends. Every block gets a new ID from blockCount.
+		var blockCount int
+		var textID, reasoningID string
+		var textOpen, reasoningOpen bool
+		endText := func() bool {
+			if !textOpen {
+				return true
+			}
+			textOpen = false
+			return yield(&api.TextEndEvent{ID: textID})
+		}
+		endReasoning := func() bool {
+			if !reasoningOpen {
+				return true
+			}
+			reasoningOpen = false
+			return yield(&api.ReasoningEndEvent{ID: reasoningID})
+		}
+
+		if !yield(&api.StreamStartEvent{Warnings: []api.CallWarning{}}) {
+			return
+		}
 
 		for scanner.Scan() {
 			line := scanner.Text()
@@ -374,15 +435,29 @@ func (m *OpenRouterChatLanguageModel) DoStream(
 
 			var chunk chatStreamResponse
 			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
-				if !yield(&api.ErrorEvent{
+				yield(&api.ErrorEvent{
 					Err: api.NewJSONParseError(data, err),
-				}) {
-					return
-				}
+				})
 				return
 			}
 
 			// Handle error chunks
+			if chunk.Error != nil {
+				yield(&api.ErrorEvent{
+					Err: newStreamAPICallError(resp, chunk.Error, []byte(data)),
+				})
+				return
+			}
+
+			// Usage is sent with the last chunk, which might not contain any choices
+			if chunk.Usage != nil {
+				usage = api.Usage{
+					InputTokens:  chunk.Usage.PromptTokens,
+					OutputTokens: chunk.Usage.CompletionTokens,
+					TotalTokens:  chunk.Usage.PromptTokens + chunk.Usage.CompletionTokens,
+				}
+			}
+
 			if len(chunk.Choices) == 0 {
 				continue
 			}
@@ -391,7 +466,15 @@ func (m *OpenRouterChatLanguageModel) DoStream(
 			delta := choice.Delta
 
 			// Handle text delta
-			if delta.Content != nil {
+			if delta.Content != nil && *delta.Content != "" {
+				if !textOpen {
+					textID = fmt.Sprintf("text-%d", blockCount)
+					blockCount++
+					if !endReasoning() || !yield(&api.TextStartEvent{ID: textID}) {
+						return
+					}
+					textOpen = true
+				}
 				if !yield(&api.TextDeltaEvent{
 					TextDelta: *delta.Content,
 				}) {
@@ -400,7 +483,15 @@ func (m *OpenRouterChatLanguageModel) DoStream(
 			}
 
 			// Handle reasoning delta
-			if delta.Reasoning != nil {
+			if delta.Reasoning != nil && *delta.Reasoning != "" {
+				if !reasoningOpen {
+					reasoningID = fmt.Sprintf("reasoning-%d", blockCount)
+					blockCount++
+					if !endText() || !yield(&api.ReasoningStartEvent{ID: reasoningID}) {
+						return
+					}
+					reasoningOpen = true
+				}
 				if !yield(&api.ReasoningEvent{
 					TextDelta: *delta.Reasoning,
 				}) {
@@ -409,44 +500,37 @@ func (m *OpenRouterChatLanguageModel) DoStream(
 			}
 
 			// Handle tool calls
-			if len(delta.ToolCalls) > 0 {
-				for _, tc := range delta.ToolCalls {
-					if len(toolCalls) <= tc.Index {
-						toolCalls = append(toolCalls, client.ToolCall{
-							ID:   tc.ID,
-							Type: tc.Type,
-							Function: struct {
-								Name      string `json:"name"`
-								Arguments string `json:"arguments"`
-							}{
-								Name:      tc.Function.Name,
-								Arguments: "",
-							},
-						})
+			for _, tc := range delta.ToolCalls {
+				if len(toolCalls) <= tc.Index {
+					toolCalls = append(toolCalls, client.ToolCall{
+						ID:   tc.ID,
+						Type: tc.Type,
+						Function: struct {
+							Name      string `json:"name"`
+							Arguments string `json:"arguments"`
+						}{
+							Name:      tc.Function.Name,
+							Arguments: "",
+						},
+					})
+					if !yield(&api.ToolInputStartEvent{
+						ToolCallID: tc.ID,
+						ToolName:   tc.Function.Name,
+					}) {
+						return
 					}
+				}
 
-					toolCall := &toolCalls[tc.Index]
-
-					if tc.Function.Arguments != "" {
-						toolCall.Function.Arguments += tc.Function.Arguments
-						if !yield(&api.ToolCallDeltaEvent{
-							ToolCallID: toolCall.ID,
-							ToolName:   toolCall.Function.Name,
-							ArgsDelta:  []byte(tc.Function.Arguments),
-						}) {
-							return
-						}
-
-						// TODO: Add tool call support
-						// // If arguments form valid JSON, emit complete tool call
-						// if json.Valid([]byte(toolCall.Function.Arguments)) {
-						// 	stream <- api.StreamPart{
-						// 		Type:       "tool-call",
-						// 		ToolCallID: toolCall.ID,
-						// 		ToolName:   toolCall.Function.Name,
-						// 		Args:       toolCall.Function.Arguments,
-						// 	}
-						// }
+				toolCall := &toolCalls[tc.Index]
+
+				if tc.Function.Arguments != "" {
+					toolCall.Function.Arguments += tc.Function.Arguments
+					if !yield(&api.ToolCallDeltaEvent{
+						ToolCallID: toolCall.ID,
+						ToolName:   toolCall.Function.Name,
+						ArgsDelta:  []byte(tc.Function.Arguments),
+					}) {
+						return
 					}
 				}
 			}
@@ -459,43 +543,60 @@ func (m *OpenRouterChatLanguageModel) DoStream(
 			//     }
 			// }
 
-			// Handle finish
 			if choice.FinishReason != "" {
-				// TODO: Add usage support
-				// if chunk.Usage != nil {
-				// 	usage = api.Usage{
-				// 		PromptTokens:     chunk.Usage.PromptTokens,
-				// 		CompletionTokens: chunk.Usage.CompletionTokens,
-				// 	}
-				// }
-
-				finishReason := codec.DecodeFinishReason(choice.FinishReason)
-				var usage api.Usage
-				if chunk.Usage != nil {
-					usage = api.Usage{
-						InputTokens:  chunk.Usage.PromptTokens,
-						OutputTokens: chunk.Usage.CompletionTokens,
-						TotalTokens:  chunk.Usage.PromptTokens + chunk.Usage.CompletionTokens,
-					}
-				}
-
-				if !yield(&api.FinishEvent{
-					FinishReason: finishReason,
-					Usage:        usage,
-					// TODO: Add logprobs support and usage support
-					// LogProbs:     logprobs,
-				}) {
-					return
-				}
+				finishReason = choice.FinishReason
 			}
 		}
 
 		if err := scanner.Err(); err != nil {
 			yield(&api.ErrorEvent{
-				Err: fmt.Errorf("scanner error: %w", err),
+				Err: &api.APICallError{
+					AISDKError: api.NewAISDKError("AI_APICallError", fmt.Sprintf("reading stream: %v", err), err),
+					URL:        resp.Request.URL,
+					Request:    resp.Request,
+					StatusCode: resp.StatusCode,
+					Response:   resp,
+				},
 			})
+			return
+		}
+
+		if !endText() || !endReasoning() {
+			return
+		}
+
+		// Emit the complete tool calls once all their deltas have been received
+		for _, toolCall := range toolCalls {
+			args := toolCall.Function.Arguments
+			if args == "" {
+				args = "{}"
+			}
+			if !yield(&api.ToolInputEndEvent{ToolCallID: toolCall.ID}) {
+				return
+			}
+			if !yield(&api.ToolCallEvent{
+				ToolCallID: toolCall.ID,
+				ToolName:   toolCall.Function.Name,
+				Args:       json.RawMessage(args),
+			}) {
+				return
+			}
 		}
+
+		yield(&api.FinishEvent{
+			FinishReason: codec.DecodeFinishReason(finishReason),
+			Usage:        usage,
+			// TODO: Add logprobs support
+			// LogProbs:     logprobs,
+		})
 	}
 
-	return &api.StreamResponse{Stream: stream}, nil
+	return &api.StreamResponse{
+		Stream: stream,
+		ResponseInfo: &api.ResponseInfo{
+			Headers:    resp.Header,
+			Status:     resp.Status,
+			StatusCode: resp.StatusCode,
+		},
+	}, nil
 }
@@ -7,7 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.jetify.com/ai/aitesting"
 	"go.jetify.com/ai/api"
-	"go.jetify.com/ai/provider/internal/openrouter/client"
+	"go.jetify.com/ai/provider/openrouter/client"
 	"go.jetify.com/pkg/httpmock"
 )
 
@@ -251,9 +251,10 @@ func TestDoGenerate(t *testing.T) {
 			})
 			defer server.Close()
 
-			provider := NewOpenRouterProvider(
-				server.BaseURL(),
-				"test-api-key",
+			provider := NewProvider(
+				WithBaseURL(server.BaseURL()+"/api/v1"),
+				WithAPIKey("test-api-key"),
+				WithChatSettings(tt.settings),
 			)
 
 			modelID := defaultModel
@@ -261,9 +262,10 @@ func TestDoGenerate(t *testing.T) {
 				modelID = tt.modelID
 			}
 
-			model := NewOpenRouterChatLanguageModel(provider, modelID, tt.settings)
+			model, err := provider.LanguageModel(modelID)
+			require.NoError(t, err)
 
-			got, err := model.DoGenerate(t.Context(), testPrompt, api.CallOptions{})
+			got, err := model.Generate(t.Context(), testPrompt, api.CallOptions{})
 			if tt.wantErr {
 				require.Error(t, err)
 				return
@@ -342,3 +344,196 @@ func boolPtr(b bool) *bool {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestGenerate(t *testing.T) {
+	server := httpmock.NewServer(t, []httpmock.Exchange{
+		{
+			Request: httpmock.Request{
+				Method: http.MethodPost,
+				Path:   "/chat/completions",
+				Headers: map[string]string{
+					"Authorization": "Bearer test-api-key",
+					"X-Title":       "test-app",
+				},
+				Body: map[string]any{
+					"model": "openai/gpt-4o",
+					"messages": []map[string]any{
+						{"role": "user", "content": "Hello"},
+					},
+				},
+			},
+			Response: newResponse(responseValues{Content: "Hi there!"}),
+		},
+	})
+	defer server.Close()
+
+	provider := NewProvider(
+		WithBaseURL(server.BaseURL()),
+		WithAPIKey("test-api-key"),
+		WithHeaders(http.Header{"X-Title": []string{"test-app"}}),
+	)
+	model, err := provider.LanguageModel("openai/gpt-4o")
+	require.NoError(t, err)
+	require.Equal(t, "openrouter.chat", model.ProviderName())
+
+	got, err := model.Generate(t.Context(), testPrompt, api.CallOptions{})
+	require.NoError(t, err)
+	aitesting.ResponseContains(t, &api.Response{
+		Content:      []api.ContentBlock{&api.TextBlock{Text: "Hi there!"}},
+		FinishReason: api.FinishReasonStop,
+		Usage:        api.Usage{InputTokens: 4, OutputTokens: 30, TotalTokens: 34},
+	}, got)
+}
+
+func TestGenerate_Error(t *testing.T) {
+	server := httpmock.NewServer(t, []httpmock.Exchange{
+		{
+			Request: httpmock.Request{Method: http.MethodPost, Path: "/chat/completions"},
+			Response: httpmock.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Body:       `{"error":{"message":"Rate limit exceeded","code":"rate_limited"}}`,
+			},
+		},
+	})
+	defer server.Close()
+
+	model, err := NewProvider(WithBaseURL(server.BaseURL())).LanguageModel("openai/gpt-4o")
+	require.NoError(t, err)
+
+	_, err = model.Generate(t.Context(), testPrompt, api.CallOptions{})
+	var apiErr *api.APICallError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	require.True(t, apiErr.IsRetryable())
+}
+
+func TestStream(t *testing.T) {
+	chunks := []string{
+		`{"id":"gen-1","choices":[{"delta":{"role":"assistant","reasoning":"Thinking"}}]}`,
+		`{"id":"gen-1","choices":[{"delta":{"content":"Hel"}}]}`,
+		`{"id":"gen-1","choices":[{"delta":{"content":"lo"}}]}`,
+		`{"id":"gen-1","choices":[{"delta":{"tool_calls":[{"index":0,"id":"call-1","type":"function","function":{"name":"weather","arguments":"{\"city\":"}}]}}]}`,
+		`{"id":"gen-1","choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]}}]}`,
+		`{"id":"gen-1","choices":[{"delta":{},"finish_reason":"tool_calls"}]}`,
+		`{"id":"gen-1","choices":[],"usage":{"prompt_tokens":10,"completion_tokens":5}}`,
+	}
+	var body string
+	for _, chunk := range chunks {
+		body += "data: " + chunk + "\n\n"
+	}
+	body += "data: [DONE]\n\n"
+
+	server := httpmock.NewServer(t, []httpmock.Exchange{
+		{
+			Request: httpmock.Request{
+				Method: http.MethodPost,
+				Path:   "/chat/completions",
+				Body: map[string]any{
+					"model":          "openai/gpt-4o",
+					"messages":       []map[string]any{{"role": "user", "content": "Hello"}},
+					"stream":         true,
+					"stream_options": map[string]any{"include_usage": true},
+				},
+			},
+			Response: httpmock.Response{
+				StatusCode: http.StatusOK,
+				Headers:    map[string]string{"Content-Type": "text/event-stream"},
+				Body:       body,
+			},
+		},
+	})
+	defer server.Close()
+
+	model, err := NewProvider(WithBaseURL(server.BaseURL())).LanguageModel("openai/gpt-4o")
+	require.NoError(t, err)
+
+	resp, err := model.Stream(t.Context(), testPrompt, api.CallOptions{})
+	require.NoError(t, err)
+
+	var events []api.StreamEvent
+	for event := range resp.Stream {
+		events = append(events, event)
+	}
+	require.Equal(t, []api.StreamEvent{
+		&api.StreamStartEvent{Warnings: []api.CallWarning{}},
+		&api.ReasoningStartEvent{ID: "reasoning-0"},
+		&api.ReasoningEvent{TextDelta: "Thinking"},
+		&api.ReasoningEndEvent{ID: "reasoning-0"},
+		&api.TextStartEvent{ID: "text-1"},
+		&api.TextDeltaEvent{TextDelta: "Hel"},
+		&api.TextDeltaEvent{TextDelta: "lo"},
+		&api.ToolInputStartEvent{ToolCallID: "call-1", ToolName: "weather"},
+		&api.ToolCallDeltaEvent{ToolCallID: "call-1", ToolName: "weather", ArgsDelta: []byte(`{"city":`)},
+		&api.ToolCallDeltaEvent{ToolCallID: "call-1", ToolName: "weather", ArgsDelta: []byte(`"Paris"}`)},
+		&api.TextEndEvent{ID: "text-1"},
+		&api.ToolInputEndEvent{ToolCallID: "call-1"},
+		&api.ToolCallEvent{ToolCallID: "call-1", ToolName: "weather", Args: []byte(`{"city":"Paris"}`)},
+		&api.FinishEvent{
+			FinishReason: api.FinishReasonToolCalls,
+			Usage:        api.Usage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15},
+		},
+	}, events)
+}
+
+func TestStream_Error(t *testing.T) {
+	server := httpmock.NewServer(t, []httpmock.Exchange{
+		{
+			Request: httpmock.Request{Method: http.MethodPost, Path: "/chat/completions"},
+			Response: httpmock.Response{
+				StatusCode: http.StatusUnauthorized,
+				Body:       `{"error":{"message":"No auth credentials found","code":"unauthorized"}}`,
+			},
+		},
+	})
+	defer server.Close()
+
+	model, err := NewProvider(WithBaseURL(server.BaseURL())).LanguageModel("openai/gpt-4o")
+	require.NoError(t, err)
+
+	_, err = model.Stream(t.Context(), testPrompt, api.CallOptions{})
+	var apiErr *api.APICallError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	require.Equal(t, "No auth credentials found", apiErr.Message)
+}
+
+func TestStream_ErrorChunk(t *testing.T) {
+	body := "data: " + `{"id":"gen-1","choices":[{"delta":{"content":"Hel"}}]}` + "\n\n" +
+		"data: " + `{"error":{"message":"Provider returned error","code":502,"metadata":{"provider_name":"OpenAI"}}}` + "\n\n"
+
+	server := httpmock.NewServer(t, []httpmock.Exchange{
+		{
+			Request: httpmock.Request{Method: http.MethodPost, Path: "/chat/completions"},
+			Response: httpmock.Response{
+				StatusCode: http.StatusOK,
+				Headers:    map[string]string{"Content-Type": "text/event-stream"},
+				Body:       body,
+			},
+		},
+	})
+	defer server.Close()
+
+	model, err := NewProvider(WithBaseURL(server.BaseURL())).LanguageModel("openai/gpt-4o")
+	require.NoError(t, err)
+
+	resp, err := model.Stream(t.Context(), testPrompt, api.CallOptions{})
+	require.NoError(t, err)
+
+	var last api.StreamEvent
+	for event := range resp.Stream {
+		last = event
+	}
+	errEvent, ok := last.(*api.ErrorEvent)
+	require.True(t, ok, "last event is %T", last)
+
+	var apiErr *api.APICallError
+	require.ErrorAs(t, errEvent.Err, &apiErr)
+	require.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	require.Equal(t, "Provider returned error", apiErr.Message)
+	require.True(t, apiErr.IsRetryable())
+	require.Equal(t, &chatStreamError{
+		Message:  "Provider returned error",
+		Code:     float64(502),
+		Metadata: map[string]any{"provider_name": "OpenAI"},
+	}, apiErr.Data)
+}
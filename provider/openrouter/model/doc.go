@@ -15,7 +15,7 @@
 //
 // Example usage:
 //
-//	import "github.com/your-org/aisdk/provider/openrouter/model"
+//	import "go.jetify.com/ai/provider/openrouter/model"
 //
 //	// Use a predefined model constant
 //	modelID := model.O3MiniHigh
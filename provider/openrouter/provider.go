@@ -0,0 +1,171 @@
+package openrouter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"go.jetify.com/ai/api"
+	"go.jetify.com/ai/provider/openrouter/client"
+)
+
+// DefaultBaseURL is the base URL of the OpenRouter API.
+const DefaultBaseURL = "https://openrouter.ai/api/v1"
+
+type Provider struct {
+	// name is the name of the provider, overrides the default "openrouter".
+	name string
+
+	// baseURL is the base URL of the OpenRouter API.
+	baseURL string
+
+	// apiKey is the API key used for authentication.
+	apiKey string
+
+	// client is the HTTP client used to make API calls.
+	client *http.Client
+
+	// headers are sent with every request.
+	headers http.Header
+
+	// settings are applied to every chat model created by the provider.
+	settings *client.ChatSettings
+}
+
+var _ api.Provider = &Provider{}
+
+type ProviderOption func(*Provider)
+
+// WithClient sets a custom HTTP client.
+func WithClient(c *http.Client) ProviderOption {
+	return func(p *Provider) { p.client = c }
+}
+
+func WithName(name string) ProviderOption {
+	return func(p *Provider) { p.name = name }
+}
+
+// WithAPIKey sets the API key. Defaults to the OPENROUTER_API_KEY environment variable.
+func WithAPIKey(apiKey string) ProviderOption {
+	return func(p *Provider) { p.apiKey = apiKey }
+}
+
+// WithBaseURL sets the base URL of the API. Defaults to the OPENROUTER_BASE_URL
+// environment variable, or DefaultBaseURL.
+func WithBaseURL(baseURL string) ProviderOption {
+	return func(p *Provider) { p.baseURL = baseURL }
+}
+
+// WithHeaders sets custom headers for API requests.
+func WithHeaders(headers http.Header) ProviderOption {
+	return func(p *Provider) {
+		for k, values := range headers {
+			for _, v := range values {
+				p.headers.Add(k, v)
+			}
+		}
+	}
+}
+
+// WithChatSettings sets OpenRouter-specific settings applied to every
+// language model created by the provider.
+func WithChatSettings(settings *client.ChatSettings) ProviderOption {
+	return func(p *Provider) { p.settings = settings }
+}
+
+func NewProvider(opts ...ProviderOption) api.Provider {
+	p := &Provider{
+		client:  http.DefaultClient,
+		headers: make(http.Header),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if p.name == "" {
+		p.name = "openrouter"
+	}
+	if p.baseURL == "" {
+		p.baseURL = DefaultBaseURL
+		if o, ok := os.LookupEnv("OPENROUTER_BASE_URL"); ok {
+			p.baseURL = o
+		}
+	}
+	if p.apiKey == "" {
+		p.apiKey = os.Getenv("OPENROUTER_API_KEY")
+	}
+
+	return p
+}
+
+// TextEmbeddingModel is not supported by the OpenRouter provider.
+func (p *Provider) TextEmbeddingModel(modelID string) (api.EmbeddingModel[string, api.Embedding], error) {
+	return nil, api.NewUnsupportedFunctionalityError(p.name, "TextEmbeddingModel")
+}
+
+// MultimodalEmbeddingModel is not supported by the OpenRouter provider.
+func (p *Provider) MultimodalEmbeddingModel(modelID string) (api.EmbeddingModel[api.MultimodalEmbeddingInput, api.Embedding], error) {
+	return nil, api.NewUnsupportedFunctionalityError(p.name, "MultimodalEmbeddingModel")
+}
+
+// SparseEmbeddingModel is not supported by the OpenRouter provider.
+func (p *Provider) SparseEmbeddingModel(modelID string) (api.EmbeddingModel[string, api.SparseEmbedding], error) {
+	return nil, api.NewUnsupportedFunctionalityError(p.name, "SparseEmbeddingModel")
+}
+
+// SegmentingModel is not supported by the OpenRouter provider.
+func (p *Provider) SegmentingModel(modelID string) (api.SegmentingModel, error) {
+	return nil, api.NewUnsupportedFunctionalityError(p.name, "SegmentingModel")
+}
+
+// RankingModel is not supported by the OpenRouter provider.
+func (p *Provider) RankingModel(modelID string) (api.RankingModel, error) {
+	return nil, api.NewUnsupportedFunctionalityError(p.name, "RankingModel")
+}
+
+// doJSONRequest makes a JSON request to the OpenRouter API.
+func (pc ProviderConfig) doJSONRequest(ctx context.Context, method, path string, body any, extraHeaders http.Header) (*http.Response, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		jsonBytes, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request body: %w", err)
+		}
+		bodyReader = bytes.NewReader(jsonBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, pc.baseURL+path, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	// Set default headers
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+pc.apiKey)
+
+	// Set provider headers
+	for k, values := range pc.headers {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+
+	// Set request-specific headers
+	for k, values := range extraHeaders {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+
+	resp, err := pc.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,16 @@
+package openrouter
+
+import (
+	"net/http"
+
+	"go.jetify.com/ai/provider/openrouter/client"
+)
+
+type ProviderConfig struct {
+	providerName string
+	baseURL      string
+	apiKey       string
+	client       *http.Client
+	headers      http.Header
+	settings     *client.ChatSettings
+}
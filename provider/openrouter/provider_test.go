@@ -0,0 +1,50 @@
+package openrouter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+)
+
+func TestNewProvider(t *testing.T) {
+	t.Setenv("OPENROUTER_API_KEY", "env-api-key")
+	t.Setenv("OPENROUTER_BASE_URL", "")
+
+	p, ok := NewProvider().(*Provider)
+	require.True(t, ok)
+	assert.Equal(t, "openrouter", p.name)
+	assert.Equal(t, "env-api-key", p.apiKey)
+
+	p, ok = NewProvider(WithName("router"), WithAPIKey("explicit"), WithBaseURL("http://localhost")).(*Provider)
+	require.True(t, ok)
+	assert.Equal(t, "router", p.name)
+	assert.Equal(t, "explicit", p.apiKey)
+	assert.Equal(t, "http://localhost", p.baseURL)
+
+	model, err := p.LanguageModel("openai/gpt-4o")
+	require.NoError(t, err)
+	assert.Equal(t, "router.chat", model.ProviderName())
+	assert.Equal(t, "openai/gpt-4o", model.ModelID())
+}
+
+func TestProvider_Unsupported(t *testing.T) {
+	p := NewProvider()
+
+	_, err := p.TextEmbeddingModel("model")
+	var unsupported *api.UnsupportedFunctionalityError
+	assert.ErrorAs(t, err, &unsupported)
+
+	_, err = p.MultimodalEmbeddingModel("model")
+	assert.ErrorAs(t, err, &unsupported)
+
+	_, err = p.SparseEmbeddingModel("model")
+	assert.ErrorAs(t, err, &unsupported)
+
+	_, err = p.RankingModel("model")
+	assert.ErrorAs(t, err, &unsupported)
+
+	_, err = p.SegmentingModel("model")
+	assert.ErrorAs(t, err, &unsupported)
+}
@@ -15,7 +15,7 @@ type Client struct {
 // DefaultClientOptions read from the environment (TEI_BASE_URL, TEI_API_KEY).
 // This should be used to initialize new clients.
 func DefaultClientOptions() []requesterx.RequestOption {
-	defaults := []requesterx.RequestOption{}
+	defaults := []requesterx.RequestOption{requesterx.WithErrorDecoder(decodeError)}
 	if o, ok := os.LookupEnv("TEI_BASE_URL"); ok {
 		defaults = append(defaults, requesterx.WithBaseURL(o))
 	}
@@ -0,0 +1,24 @@
+package tei
+
+import "encoding/json"
+
+// ErrorResponse represents the body of a failed TEI request.
+// This matches the TEI OpenAPI ErrorResponse schema
+type ErrorResponse struct {
+	// Error is the error message
+	Error string `json:"error"`
+	// ErrorType is the kind of error: "Unhealthy", "Backend", "Overloaded",
+	// "Validation" or "Tokenizer"
+	ErrorType string `json:"error_type"`
+}
+
+// decodeError decodes a TEI error body. It is used as the client's
+// requesterx.ErrorDecoder so that the returned api.APICallError carries an
+// *ErrorResponse as its Data.
+func decodeError(body []byte) (string, any, bool) {
+	var res ErrorResponse
+	if err := json.Unmarshal(body, &res); err != nil || res.Error == "" {
+		return "", nil, false
+	}
+	return res.Error, &res, true
+}
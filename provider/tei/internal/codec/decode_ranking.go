@@ -26,21 +26,31 @@ func DecodeRank(resp *tei.RankResponse) (api.RankingResponse, error) {
 
 	if maxIndex < 0 {
 		return api.RankingResponse{
-			Scores: []float64{},
+			Scores:  []float64{},
+			Results: []api.RankingResult{},
 		}, nil
 	}
 
 	// Create scores array with the right size
 	scores := make([]float64, maxIndex+1)
 
-	// Fill in the scores at their respective indices
+	// Fill in the scores at their respective indices, and keep the results
+	// with the text TEI echoes when ReturnText is set
+	results := make([]api.RankingResult, 0, len(rankResults))
 	for _, result := range rankResults {
 		if result.Index >= 0 && result.Index < len(scores) {
 			scores[result.Index] = result.Score
+			ranked := api.RankingResult{Index: result.Index, Score: result.Score}
+			if result.Text != nil {
+				ranked.Document = *result.Text
+			}
+			results = append(results, ranked)
 		}
 	}
+	api.SortRankingResults(results)
 
 	return api.RankingResponse{
-		Scores: scores,
+		Scores:  scores,
+		Results: results,
 	}, nil
 }
@@ -31,6 +31,10 @@ func EncodeEmbedding(
 		reqOpts = append(reqOpts, requesterx.WithBaseURL(opts.BaseURL))
 	}
 
+	if opts.Timeout > 0 {
+		reqOpts = append(reqOpts, requesterx.WithTimeout(opts.Timeout))
+	}
+
 	applyProviderMetadata(&params, opts)
 
 	var warnings []api.CallWarning
@@ -30,6 +30,15 @@ func EncodeRank(
 		reqOpts = append(reqOpts, requesterx.WithBaseURL(opts.BaseURL))
 	}
 
+	if opts.Timeout > 0 {
+		reqOpts = append(reqOpts, requesterx.WithTimeout(opts.Timeout))
+	}
+
+	// TEI has no top N parameter, so TopN is left to the caller.
+	if opts.ReturnDocuments {
+		params.ReturnText = &opts.ReturnDocuments
+	}
+
 	applyRankProviderMetadata(&params, opts)
 
 	var warnings []api.CallWarning
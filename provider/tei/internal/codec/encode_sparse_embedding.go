@@ -29,6 +29,10 @@ func EncodeSparseEmbedding(
 		reqOpts = append(reqOpts, requesterx.WithBaseURL(opts.BaseURL))
 	}
 
+	if opts.Timeout > 0 {
+		reqOpts = append(reqOpts, requesterx.WithTimeout(opts.Timeout))
+	}
+
 	applySparseProviderMetadata(&params, opts)
 
 	var warnings []api.CallWarning
@@ -1,7 +1,12 @@
 package tei
 
 import (
+	"net/http"
+	"slices"
+	"time"
+
 	"go.jetify.com/ai/api"
+	"go.jetify.com/ai/provider/internal/requesterx"
 	tei "go.jetify.com/ai/provider/tei/client"
 )
 
@@ -10,6 +15,9 @@ type Provider struct {
 	client tei.Client
 	name   string
 	apiKey string
+
+	httpClient *http.Client
+	httpConfig requesterx.HTTPClientConfig
 }
 
 var _ api.Provider = &Provider{}
@@ -38,6 +46,38 @@ func WithAPIKey(apiKey string) ProviderOption {
 	}
 }
 
+// WithHTTPClient sets the HTTP client used to send every request of the
+// provider. It takes precedence over WithTimeout and the connection pool
+// options, and can be used to share one tuned client between providers.
+func WithHTTPClient(c *http.Client) ProviderOption {
+	return func(p *Provider) { p.httpClient = c }
+}
+
+// WithTimeout bounds the time taken by each request of the provider,
+// including reading the response body. Use api.TransportOptions.Timeout to
+// bound a single call instead.
+func WithTimeout(d time.Duration) ProviderOption {
+	return func(p *Provider) { p.httpConfig.Timeout = d }
+}
+
+// WithMaxIdleConns limits the number of idle connections the provider keeps
+// open across all hosts.
+func WithMaxIdleConns(n int) ProviderOption {
+	return func(p *Provider) { p.httpConfig.MaxIdleConns = n }
+}
+
+// WithMaxIdleConnsPerHost limits the number of idle connections the provider
+// keeps open to each host.
+func WithMaxIdleConnsPerHost(n int) ProviderOption {
+	return func(p *Provider) { p.httpConfig.MaxIdleConnsPerHost = n }
+}
+
+// WithIdleConnTimeout sets how long an idle connection is kept open before
+// it is closed.
+func WithIdleConnTimeout(d time.Duration) ProviderOption {
+	return func(p *Provider) { p.httpConfig.IdleConnTimeout = d }
+}
+
 // NewProvider creates a new TEI provider with the given options.
 func NewProvider(opts ...ProviderOption) *Provider {
 	p := &Provider{
@@ -48,6 +88,13 @@ func NewProvider(opts ...ProviderOption) *Provider {
 		opt(p)
 	}
 
+	if p.httpClient == nil && !p.httpConfig.IsZero() {
+		p.httpClient = requesterx.NewHTTPClient(p.httpConfig)
+	}
+	if p.httpClient != nil {
+		p.client = tei.NewClient(append(slices.Clone(p.client.Options), requesterx.WithHTTPClient(p.httpClient))...)
+	}
+
 	if p.name == "" {
 		p.name = "text-embedding-inference"
 	}
@@ -43,6 +43,7 @@ func DecodeRank(resp *cliniaclient.RankResponse) (api.RankingResponse, error) {
 
 	return api.RankingResponse{
 		Scores:    scores,
+		Results:   api.RankingResultsFromScores(scores),
 		RequestID: resp.ID,
 	}, nil
 }
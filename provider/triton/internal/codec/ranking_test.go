@@ -74,9 +74,22 @@ func TestDecodeRank(t *testing.T) {
 			},
 			want: api.RankingResponse{
 				Scores:    []float64{0.9, 0.1},
+				Results:   []api.RankingResult{{Index: 0, Score: 0.9}, {Index: 1, Score: 0.1}},
 				RequestID: "abc",
 			},
 		},
+		{
+			name: "results sorted by score",
+			input: &cliniaclient.RankResponse{
+				ID:     "def",
+				Scores: []float32{0.2, 0.7, 0.5},
+			},
+			want: api.RankingResponse{
+				Scores:    []float64{0.2, 0.7, 0.5},
+				Results:   []api.RankingResult{{Index: 1, Score: 0.7}, {Index: 2, Score: 0.5}, {Index: 0, Score: 0.2}},
+				RequestID: "def",
+			},
+		},
 		{
 			name:    "nil response",
 			input:   nil,
@@ -94,6 +107,11 @@ func TestDecodeRank(t *testing.T) {
 			require.NoError(t, err)
 			require.Equal(t, tt.want.RequestID, resp.RequestID)
 			require.InDeltaSlice(t, tt.want.Scores, resp.Scores, 1e-6)
+			require.Len(t, resp.Results, len(tt.want.Results))
+			for i, want := range tt.want.Results {
+				require.Equal(t, want.Index, resp.Results[i].Index)
+				require.InDelta(t, want.Score, resp.Results[i].Score, 1e-6)
+			}
 		})
 	}
 }
@@ -0,0 +1,88 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+	"go.jetify.com/ai/provider/mock"
+)
+
+func TestRankMany(t *testing.T) {
+	texts := []string{"a", "bb", "ccc"}
+
+	tests := []struct {
+		name     string
+		response api.RankingResponse
+		opts     []TransportOption
+		want     []api.RankingResult
+	}{
+		{
+			name:     "results built from scores",
+			response: api.RankingResponse{Scores: []float64{0.2, 0.9, 0.5}},
+			want: []api.RankingResult{
+				{Index: 1, Score: 0.9},
+				{Index: 2, Score: 0.5},
+				{Index: 0, Score: 0.2},
+			},
+		},
+		{
+			name:     "top N",
+			response: api.RankingResponse{Scores: []float64{0.2, 0.9, 0.5}},
+			opts:     []TransportOption{WithTopN(2)},
+			want: []api.RankingResult{
+				{Index: 1, Score: 0.9},
+				{Index: 2, Score: 0.5},
+			},
+		},
+		{
+			name:     "top N larger than the input",
+			response: api.RankingResponse{Scores: []float64{0.2, 0.9}},
+			opts:     []TransportOption{WithTopN(5)},
+			want: []api.RankingResult{
+				{Index: 1, Score: 0.9},
+				{Index: 0, Score: 0.2},
+			},
+		},
+		{
+			name:     "return documents",
+			response: api.RankingResponse{Scores: []float64{0.2, 0.9, 0.5}},
+			opts:     []TransportOption{WithTopN(1), WithReturnDocuments()},
+			want: []api.RankingResult{
+				{Index: 1, Score: 0.9, Document: "bb"},
+			},
+		},
+		{
+			name: "provider results are kept",
+			response: api.RankingResponse{
+				Scores: []float64{0.2, 0.9, 0},
+				Results: []api.RankingResult{
+					{Index: 1, Score: 0.9, Document: "echoed"},
+					{Index: 0, Score: 0.2},
+				},
+			},
+			opts: []TransportOption{WithReturnDocuments()},
+			want: []api.RankingResult{
+				{Index: 1, Score: 0.9, Document: "echoed"},
+				{Index: 0, Score: 0.2, Document: "a"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := mock.NewRankingModel([]mock.RankingResult{{Response: tt.response}})
+
+			resp, err := RankMany(t.Context(), model, "query", texts, tt.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.response.Scores, resp.Scores, "scores are not truncated")
+			assert.Equal(t, tt.want, resp.Results)
+			assert.Equal(t, buildTransportConfig(tt.opts), model.Calls()[0].Options,
+				"options are forwarded to the model")
+			if tt.response.Results != nil {
+				assert.Empty(t, tt.response.Results[1].Document, "the model response is not modified")
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package ai
+
+import (
+	"strings"
+	"sync"
+
+	"go.jetify.com/ai/api"
+)
+
+// registrySeparator separates the provider name from the model ID in the IDs
+// resolved by a Registry.
+const registrySeparator = ":"
+
+// Registry resolves model IDs of the form "provider:model" to models of
+// registered providers.
+//
+// Everything after the first separator is passed to the provider as the model
+// ID, so IDs that contain the separator themselves are supported:
+//
+//	registry := NewRegistry()
+//	registry.Register("openai", openai.NewProvider())
+//	registry.Register("triton", tritonProvider)
+//
+//	model, err := registry.LanguageModel("openai:gpt-5")
+//	embedder, err := registry.TextEmbeddingModel("triton:embedder:1")
+//
+// A Registry is itself an [api.Provider] and is safe for concurrent use.
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]api.Provider
+}
+
+var _ api.Provider = (*Registry)(nil)
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{providers: map[string]api.Provider{}}
+}
+
+// Register makes a provider available under the given name, replacing any
+// provider previously registered under that name.
+func (r *Registry) Register(name string, provider api.Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[name] = provider
+}
+
+// Provider returns the provider registered under the given name.
+func (r *Registry) Provider(name string) (api.Provider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	provider, ok := r.providers[name]
+	return provider, ok
+}
+
+// LanguageModel returns the language model with the given "provider:model" ID.
+func (r *Registry) LanguageModel(id string) (api.LanguageModel, error) {
+	provider, modelID, err := r.resolve(id, api.LanguageModelType)
+	if err != nil {
+		return nil, err
+	}
+	return provider.LanguageModel(modelID)
+}
+
+// TextEmbeddingModel returns the text embedding model with the given
+// "provider:model" ID.
+func (r *Registry) TextEmbeddingModel(id string) (api.EmbeddingModel[string, api.Embedding], error) {
+	provider, modelID, err := r.resolve(id, api.TextEmbeddingModelType)
+	if err != nil {
+		return nil, err
+	}
+	return provider.TextEmbeddingModel(modelID)
+}
+
+// MultimodalEmbeddingModel returns the multimodal embedding model with the
+// given "provider:model" ID.
+func (r *Registry) MultimodalEmbeddingModel(id string) (api.EmbeddingModel[api.MultimodalEmbeddingInput, api.Embedding], error) {
+	provider, modelID, err := r.resolve(id, api.MultimodalEmbeddingModelType)
+	if err != nil {
+		return nil, err
+	}
+	return provider.MultimodalEmbeddingModel(modelID)
+}
+
+// SparseEmbeddingModel returns the sparse embedding model with the given
+// "provider:model" ID.
+func (r *Registry) SparseEmbeddingModel(id string) (api.EmbeddingModel[string, api.SparseEmbedding], error) {
+	provider, modelID, err := r.resolve(id, api.SparseEmbeddingModelType)
+	if err != nil {
+		return nil, err
+	}
+	return provider.SparseEmbeddingModel(modelID)
+}
+
+// SegmentingModel returns the segmenting model with the given
+// "provider:model" ID.
+func (r *Registry) SegmentingModel(id string) (api.SegmentingModel, error) {
+	provider, modelID, err := r.resolve(id, api.SegmentingModelType)
+	if err != nil {
+		return nil, err
+	}
+	return provider.SegmentingModel(modelID)
+}
+
+// RankingModel returns the ranking model with the given "provider:model" ID.
+func (r *Registry) RankingModel(id string) (api.RankingModel, error) {
+	provider, modelID, err := r.resolve(id, api.RankingModelType)
+	if err != nil {
+		return nil, err
+	}
+	return provider.RankingModel(modelID)
+}
+
+// resolve splits id into a provider name and a model ID and looks up the
+// provider. It returns a NoSuchModelError if the ID is malformed or no
+// provider is registered under that name.
+func (r *Registry) resolve(id string, modelType api.ModelType) (api.Provider, string, error) {
+	name, modelID, ok := strings.Cut(id, registrySeparator)
+	if !ok || name == "" || modelID == "" {
+		return nil, "", api.NewNoSuchModelError(id, modelType)
+	}
+	provider, ok := r.Provider(name)
+	if !ok {
+		return nil, "", api.NewNoSuchModelError(id, modelType)
+	}
+	return provider, modelID, nil
+}
@@ -0,0 +1,131 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+)
+
+// recordingProvider records the model IDs it is asked for.
+type recordingProvider struct {
+	modelIDs []string
+}
+
+func (p *recordingProvider) record(modelID string) { p.modelIDs = append(p.modelIDs, modelID) }
+
+func (p *recordingProvider) LanguageModel(modelID string) (api.LanguageModel, error) {
+	p.record(modelID)
+	return &mockLanguageModel{name: modelID}, nil
+}
+
+func (p *recordingProvider) TextEmbeddingModel(modelID string) (api.EmbeddingModel[string, api.Embedding], error) {
+	p.record(modelID)
+	return &fakeEmbeddingModel{}, nil
+}
+
+func (p *recordingProvider) MultimodalEmbeddingModel(modelID string) (api.EmbeddingModel[api.MultimodalEmbeddingInput, api.Embedding], error) {
+	p.record(modelID)
+	return nil, api.NewUnsupportedFunctionalityError("recording", "MultimodalEmbeddingModel")
+}
+
+func (p *recordingProvider) SparseEmbeddingModel(modelID string) (api.EmbeddingModel[string, api.SparseEmbedding], error) {
+	p.record(modelID)
+	return nil, api.NewUnsupportedFunctionalityError("recording", "SparseEmbeddingModel")
+}
+
+func (p *recordingProvider) SegmentingModel(modelID string) (api.SegmentingModel, error) {
+	p.record(modelID)
+	return nil, api.NewUnsupportedFunctionalityError("recording", "SegmentingModel")
+}
+
+func (p *recordingProvider) RankingModel(modelID string) (api.RankingModel, error) {
+	p.record(modelID)
+	return &flakyRankingModel{}, nil
+}
+
+func TestRegistry(t *testing.T) {
+	openai := &recordingProvider{}
+	triton := &recordingProvider{}
+
+	registry := NewRegistry()
+	registry.Register("openai", openai)
+	registry.Register("triton", triton)
+
+	model, err := registry.LanguageModel("openai:gpt-5")
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-5", model.ModelID())
+
+	_, err = registry.TextEmbeddingModel("triton:embedder:1")
+	require.NoError(t, err)
+
+	_, err = registry.RankingModel("triton:reranker:2")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"gpt-5"}, openai.modelIDs)
+	assert.Equal(t, []string{"embedder:1", "reranker:2"}, triton.modelIDs)
+
+	_, err = registry.SegmentingModel("triton:chunker")
+	var unsupported *api.UnsupportedFunctionalityError
+	assert.ErrorAs(t, err, &unsupported)
+}
+
+func TestRegistry_NoSuchModel(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("tei", &recordingProvider{})
+
+	tests := []struct {
+		name      string
+		resolve   func(id string) error
+		id        string
+		modelType api.ModelType
+	}{
+		{
+			name:      "unknown provider",
+			resolve:   func(id string) error { _, err := registry.LanguageModel(id); return err },
+			id:        "openai:gpt-5",
+			modelType: api.LanguageModelType,
+		},
+		{
+			name:      "missing separator",
+			resolve:   func(id string) error { _, err := registry.TextEmbeddingModel(id); return err },
+			id:        "bge-m3",
+			modelType: api.TextEmbeddingModelType,
+		},
+		{
+			name:      "empty model id",
+			resolve:   func(id string) error { _, err := registry.MultimodalEmbeddingModel(id); return err },
+			id:        "tei:",
+			modelType: api.MultimodalEmbeddingModelType,
+		},
+		{
+			name:      "sparse embedding",
+			resolve:   func(id string) error { _, err := registry.SparseEmbeddingModel(id); return err },
+			id:        "jina:splade",
+			modelType: api.SparseEmbeddingModelType,
+		},
+		{
+			name:      "ranking",
+			resolve:   func(id string) error { _, err := registry.RankingModel(id); return err },
+			id:        "jina:reranker",
+			modelType: api.RankingModelType,
+		},
+		{
+			name:      "segmenting",
+			resolve:   func(id string) error { _, err := registry.SegmentingModel(id); return err },
+			id:        "chonkie:recursive",
+			modelType: api.SegmentingModelType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.resolve(tt.id)
+			var noSuchModel *api.NoSuchModelError
+			require.ErrorAs(t, err, &noSuchModel)
+			assert.Equal(t, tt.id, noSuchModel.ModelID)
+			assert.Equal(t, tt.modelType, noSuchModel.ModelType)
+		})
+	}
+}
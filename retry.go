@@ -0,0 +1,218 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"runtime"
+	"strconv"
+	"time"
+
+	"go.jetify.com/ai/api"
+)
+
+// RetryPolicy controls how calls that fail with a retryable
+// [api.APICallError] are retried.
+//
+// Zero-valued fields (other than MaxRetries) take their value from
+// [DefaultRetryPolicy].
+type RetryPolicy = api.RetryPolicy
+
+// DefaultRetryPolicy returns the policy used by [WithMaxRetries] and
+// [WithTransportMaxRetries]. Its MaxRetries is zero.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		InitialDelay:  2 * time.Second,
+		MaxDelay:      60 * time.Second,
+		BackoffFactor: 2,
+		Jitter:        0.2,
+	}
+}
+
+// withRetryDefaults returns a copy of the policy with zero-valued fields
+// replaced by the values of [DefaultRetryPolicy].
+func withRetryDefaults(p RetryPolicy) RetryPolicy {
+	defaults := DefaultRetryPolicy()
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = defaults.InitialDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaults.MaxDelay
+	}
+	if p.BackoffFactor < 1 {
+		p.BackoffFactor = defaults.BackoffFactor
+	}
+	if p.Jitter == 0 {
+		p.Jitter = defaults.Jitter
+	}
+	return p
+}
+
+// backoff returns the delay before the given retry attempt (starting at 0),
+// honoring the provider's Retry-After hint when err carries one.
+func backoff(p RetryPolicy, attempt int, err error) time.Duration {
+	if delay, ok := retryAfter(err); ok {
+		return min(delay, p.MaxDelay)
+	}
+
+	delay := float64(p.InitialDelay) * math.Pow(p.BackoffFactor, float64(attempt))
+	if p.Jitter > 0 {
+		delay *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return min(time.Duration(delay), p.MaxDelay)
+}
+
+// retry calls fn until it succeeds, fails with an error that is not
+// retryable, or the policy's retry budget is exhausted.
+func retry[T any](ctx context.Context, policy RetryPolicy, fn func() (T, error)) (T, error) {
+	result, err := fn()
+	if err == nil || policy.MaxRetries <= 0 || !isRetryable(ctx, err) {
+		return result, err
+	}
+
+	policy = withRetryDefaults(policy)
+	errs := []error{err}
+	for attempt := 0; attempt < policy.MaxRetries; attempt++ {
+		if err := sleep(ctx, backoff(policy, attempt, err)); err != nil {
+			var zero T
+			return zero, err
+		}
+
+		result, err = fn()
+		if err == nil {
+			return result, nil
+		}
+		errs = append(errs, err)
+		if !isRetryable(ctx, err) {
+			return result, api.NewRetryError(api.RetryReasonErrorNotRetryable, errs)
+		}
+	}
+	return result, api.NewRetryError(api.RetryReasonMaxRetriesExceeded, errs)
+}
+
+// retryStream opens a stream and retries when opening it fails, or when the
+// first event of the stream that follows the StreamStartEvent is a retryable
+// error. Once any other event has been received the stream is never retried.
+func retryStream(
+	ctx context.Context, policy RetryPolicy, open func() (*api.StreamResponse, error),
+) (*api.StreamResponse, error) {
+	if policy.MaxRetries <= 0 {
+		return open()
+	}
+	return retry(ctx, policy, func() (*api.StreamResponse, error) {
+		resp, err := open()
+		if err != nil {
+			return nil, err
+		}
+		return peekStream(ctx, resp)
+	})
+}
+
+// peekStream reads the first event of the stream, past any StreamStartEvent.
+// If it is a retryable error event, the stream is closed and the error is
+// returned so that it can be retried. Otherwise a response that replays the
+// events read so far, followed by the rest of the stream, is returned.
+func peekStream(ctx context.Context, resp *api.StreamResponse) (*api.StreamResponse, error) {
+	next, stop := iter.Pull(resp.Stream)
+	var peekedEvents []api.StreamEvent
+	for {
+		event, ok := next()
+		if !ok {
+			break
+		}
+		if errEvent, isErr := event.(*api.ErrorEvent); isErr {
+			if err := streamError(errEvent); isRetryable(ctx, err) {
+				stop()
+				return nil, err
+			}
+		}
+		peekedEvents = append(peekedEvents, event)
+		if _, isStart := event.(*api.StreamStartEvent); !isStart {
+			break
+		}
+	}
+
+	s := &pulledStream{peeked: peekedEvents, next: next, stop: stop}
+	// A caller that never iterates the stream would otherwise leave the
+	// pulled stream suspended forever, along with the connection behind it.
+	runtime.AddCleanup(s, func(stop func()) { stop() }, stop)
+
+	peeked := *resp
+	peeked.Stream = s.all
+	return &peeked, nil
+}
+
+// pulledStream resumes a stream that was partially read with iter.Pull.
+type pulledStream struct {
+	peeked []api.StreamEvent
+	next   func() (api.StreamEvent, bool)
+	stop   func()
+}
+
+// all yields the events that were already read, then the rest of the stream.
+func (s *pulledStream) all(yield func(api.StreamEvent) bool) {
+	defer s.stop()
+	for _, event := range s.peeked {
+		if !yield(event) {
+			return
+		}
+	}
+	for {
+		event, ok := s.next()
+		if !ok || !yield(event) {
+			return
+		}
+	}
+}
+
+// isRetryable reports whether err is an API call error that is worth
+// retrying. Errors are never retried once the context is done.
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *api.APICallError
+	return errors.As(err, &apiErr) && apiErr.IsRetryable()
+}
+
+// retryAfter extracts the delay requested by the provider through the
+// retry-after-ms or Retry-After response headers.
+func retryAfter(err error) (time.Duration, bool) {
+	var apiErr *api.APICallError
+	if !errors.As(err, &apiErr) || apiErr.Response == nil {
+		return 0, false
+	}
+	return parseRetryAfter(apiErr.Response.Header, time.Now())
+}
+
+func parseRetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	if ms, err := strconv.ParseFloat(header.Get("retry-after-ms"), 64); err == nil && ms >= 0 {
+		return time.Duration(ms * float64(time.Millisecond)), true
+	}
+
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,458 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime"
+	"testing"
+	"time"
+
+	anthropicsdk "github.com/anthropics/anthropic-sdk-go"
+	anthropicoption "github.com/anthropics/anthropic-sdk-go/option"
+	openaisdk "github.com/openai/openai-go/v2"
+	openaioption "github.com/openai/openai-go/v2/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+	"go.jetify.com/ai/provider/anthropic"
+	"go.jetify.com/ai/provider/mock"
+	"go.jetify.com/ai/provider/openai"
+	"go.jetify.com/pkg/httpmock"
+)
+
+var fastRetryPolicy = RetryPolicy{
+	MaxRetries:   2,
+	InitialDelay: time.Millisecond,
+	MaxDelay:     5 * time.Millisecond,
+	Jitter:       -1,
+}
+
+func apiCallError(statusCode int) *api.APICallError {
+	return &api.APICallError{
+		AISDKError: api.NewAISDKError("AI_APICallError", http.StatusText(statusCode), nil),
+		StatusCode: statusCode,
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name          string
+		errs          []error
+		expectedCalls int
+		expectedErr   func(t *testing.T, err error)
+	}{
+		{
+			name:          "success",
+			errs:          []error{nil},
+			expectedCalls: 1,
+		},
+		{
+			name:          "retryable then success",
+			errs:          []error{apiCallError(429), apiCallError(503), nil},
+			expectedCalls: 3,
+		},
+		{
+			name:          "not retryable",
+			errs:          []error{apiCallError(400)},
+			expectedCalls: 1,
+			expectedErr: func(t *testing.T, err error) {
+				var apiErr *api.APICallError
+				require.ErrorAs(t, err, &apiErr)
+				var retryErr *api.RetryError
+				assert.False(t, errors.As(err, &retryErr))
+			},
+		},
+		{
+			name:          "plain error",
+			errs:          []error{errors.New("boom")},
+			expectedCalls: 1,
+			expectedErr: func(t *testing.T, err error) {
+				assert.EqualError(t, err, "boom")
+			},
+		},
+		{
+			name:          "max retries exceeded",
+			errs:          []error{apiCallError(500), apiCallError(500), apiCallError(502)},
+			expectedCalls: 3,
+			expectedErr: func(t *testing.T, err error) {
+				var retryErr *api.RetryError
+				require.ErrorAs(t, err, &retryErr)
+				assert.Equal(t, api.RetryReasonMaxRetriesExceeded, retryErr.Reason)
+				assert.Len(t, retryErr.Errors, 3)
+				var apiErr *api.APICallError
+				require.ErrorAs(t, err, &apiErr)
+				assert.Equal(t, 502, apiErr.StatusCode)
+			},
+		},
+		{
+			name:          "not retryable after retry",
+			errs:          []error{apiCallError(500), apiCallError(401)},
+			expectedCalls: 2,
+			expectedErr: func(t *testing.T, err error) {
+				var retryErr *api.RetryError
+				require.ErrorAs(t, err, &retryErr)
+				assert.Equal(t, api.RetryReasonErrorNotRetryable, retryErr.Reason)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			result, err := retry(t.Context(), fastRetryPolicy, func() (int, error) {
+				err := tt.errs[calls]
+				calls++
+				return calls, err
+			})
+			assert.Equal(t, tt.expectedCalls, calls)
+			if tt.expectedErr == nil {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedCalls, result)
+				return
+			}
+			require.Error(t, err)
+			tt.expectedErr(t, err)
+		})
+	}
+}
+
+func TestRetry_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	policy := RetryPolicy{MaxRetries: 3, InitialDelay: time.Hour}
+
+	calls := 0
+	_, err := retry(ctx, policy, func() (int, error) {
+		calls++
+		cancel()
+		return 0, apiCallError(500)
+	})
+	assert.Equal(t, 1, calls)
+	var apiErr *api.APICallError
+	assert.ErrorAs(t, err, &apiErr)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialDelay: time.Second, MaxDelay: 5 * time.Second, BackoffFactor: 2, Jitter: -1}
+	assert.Equal(t, time.Second, backoff(policy, 0, apiCallError(500)))
+	assert.Equal(t, 2*time.Second, backoff(policy, 1, apiCallError(500)))
+	assert.Equal(t, 4*time.Second, backoff(policy, 2, apiCallError(500)))
+	assert.Equal(t, 5*time.Second, backoff(policy, 3, apiCallError(500)))
+
+	withHeader := apiCallError(429)
+	withHeader.Response = &http.Response{Header: http.Header{"Retry-After": []string{"3"}}}
+	assert.Equal(t, 3*time.Second, backoff(policy, 0, withHeader))
+
+	withHeader.Response.Header.Set("Retry-After", "120")
+	assert.Equal(t, 5*time.Second, backoff(policy, 0, withHeader))
+
+	jittered := RetryPolicy{InitialDelay: time.Second, MaxDelay: time.Minute, BackoffFactor: 2, Jitter: 0.5}
+	for range 20 {
+		delay := backoff(jittered, 0, apiCallError(500))
+		assert.GreaterOrEqual(t, delay, 500*time.Millisecond)
+		assert.LessOrEqual(t, delay, 1500*time.Millisecond)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		header   http.Header
+		expected time.Duration
+		ok       bool
+	}{
+		{name: "none", header: http.Header{}},
+		{name: "milliseconds", header: http.Header{"Retry-After-Ms": []string{"1500"}}, expected: 1500 * time.Millisecond, ok: true},
+		{name: "seconds", header: http.Header{"Retry-After": []string{"2"}}, expected: 2 * time.Second, ok: true},
+		{
+			name:     "http date",
+			header:   http.Header{"Retry-After": []string{now.Add(10 * time.Second).Format(http.TimeFormat)}},
+			expected: 10 * time.Second,
+			ok:       true,
+		},
+		{name: "invalid", header: http.Header{"Retry-After": []string{"soon"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, ok := parseRetryAfter(tt.header, now)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, delay)
+		})
+	}
+}
+
+func TestGenerateText_Retry(t *testing.T) {
+	model := mock.NewGenerateModel([]mock.MockResult{
+		{Error: apiCallError(429)},
+		{Response: &api.Response{Content: []api.ContentBlock{&api.TextBlock{Text: "Hello"}}}},
+	})
+
+	resp, err := GenerateTextStr(t.Context(), "Hi", WithModel(model), WithRetryPolicy(fastRetryPolicy))
+	require.NoError(t, err)
+	assert.Equal(t, []api.ContentBlock{&api.TextBlock{Text: "Hello"}}, resp.Content)
+	model.AssertCount(t)
+}
+
+func TestGenerateText_RetryProviders(t *testing.T) {
+	rateLimited := httpmock.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Headers:    map[string]string{"Retry-After-Ms": "1"},
+		Body:       `{"type":"error","error":{"type":"rate_limit_error","message":"Rate limit exceeded"}}`,
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		response any
+		model    func(baseURL string) api.LanguageModel
+	}{
+		{
+			name: "openai",
+			path: "/responses",
+			response: map[string]any{
+				"id":     "resp_1",
+				"object": "response",
+				"status": "completed",
+				"model":  "gpt-4o",
+				"output": []any{map[string]any{
+					"id":      "msg_1",
+					"type":    "message",
+					"status":  "completed",
+					"role":    "assistant",
+					"content": []any{map[string]any{"type": "output_text", "text": "Hello", "annotations": []any{}}},
+				}},
+			},
+			model: func(baseURL string) api.LanguageModel {
+				client := openaisdk.NewClient(
+					openaioption.WithBaseURL(baseURL),
+					openaioption.WithAPIKey("test-key"),
+					openaioption.WithMaxRetries(0),
+				)
+				model, err := openai.NewProvider(openai.WithClient(client)).LanguageModel("gpt-4o")
+				require.NoError(t, err)
+				return model
+			},
+		},
+		{
+			name: "anthropic",
+			path: "/v1/messages",
+			response: map[string]any{
+				"id":      "msg_1",
+				"type":    "message",
+				"role":    "assistant",
+				"model":   "claude-3",
+				"content": []any{map[string]any{"type": "text", "text": "Hello"}},
+			},
+			model: func(baseURL string) api.LanguageModel {
+				client := anthropicsdk.NewClient(
+					anthropicoption.WithBaseURL(baseURL),
+					anthropicoption.WithAPIKey("test-key"),
+					anthropicoption.WithMaxRetries(0),
+				)
+				return anthropic.NewLanguageModel("claude-3", anthropic.WithClient(client))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httpmock.Request{Method: http.MethodPost, Path: tt.path}
+			server := httpmock.NewServer(t, []httpmock.Exchange{
+				{Request: request, Response: rateLimited},
+				{Request: request, Response: httpmock.Response{StatusCode: http.StatusOK, Body: tt.response}},
+			})
+			defer server.Close()
+
+			// The delay requested by the server is much shorter than the policy's,
+			// so the test only completes in time if Retry-After is honored.
+			ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+			defer cancel()
+			resp, err := GenerateTextStr(ctx, "Hi",
+				WithModel(tt.model(server.BaseURL())),
+				WithRetryPolicy(RetryPolicy{MaxRetries: 1, InitialDelay: time.Minute}),
+			)
+			require.NoError(t, err)
+			assert.Equal(t, []api.ContentBlock{&api.TextBlock{Text: "Hello"}}, resp.Content)
+		})
+	}
+}
+
+func TestGenerateText_ProviderAPICallError(t *testing.T) {
+	server := httpmock.NewServer(t, []httpmock.Exchange{{
+		Request: httpmock.Request{Method: http.MethodPost, Path: "/v1/messages"},
+		Response: httpmock.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       `{"type":"error","error":{"type":"invalid_request_error","message":"max_tokens is required"}}`,
+		},
+	}})
+	defer server.Close()
+
+	client := anthropicsdk.NewClient(
+		anthropicoption.WithBaseURL(server.BaseURL()),
+		anthropicoption.WithAPIKey("test-key"),
+		anthropicoption.WithMaxRetries(0),
+	)
+	_, err := GenerateTextStr(t.Context(), "Hi",
+		WithModel(anthropic.NewLanguageModel("claude-3", anthropic.WithClient(client))),
+		WithRetryPolicy(fastRetryPolicy),
+	)
+
+	var apiErr *api.APICallError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "400 Bad Request: max_tokens is required", apiErr.Message)
+	assert.Contains(t, string(apiErr.ResponseBody), "invalid_request_error")
+	assert.Contains(t, string(apiErr.RequestBody), "claude-3")
+}
+
+// streamingLanguageModel returns one scripted event sequence per Stream call.
+type streamingLanguageModel struct {
+	mockLanguageModel
+	streams [][]api.StreamEvent
+	calls   int
+}
+
+func (m *streamingLanguageModel) Stream(
+	ctx context.Context, prompt []api.Message, opts api.CallOptions,
+) (*api.StreamResponse, error) {
+	events := m.streams[m.calls]
+	m.calls++
+	return &api.StreamResponse{
+		Stream: func(yield func(api.StreamEvent) bool) {
+			for _, event := range events {
+				if !yield(event) {
+					return
+				}
+			}
+		},
+	}, nil
+}
+
+func TestStreamText_Retry(t *testing.T) {
+	t.Run("error before first event", func(t *testing.T) {
+		model := &streamingLanguageModel{streams: [][]api.StreamEvent{
+			{&api.ErrorEvent{Err: apiCallError(503)}},
+			{&api.TextDeltaEvent{TextDelta: "Hello"}, &api.FinishEvent{FinishReason: api.FinishReasonStop}},
+		}}
+
+		resp, err := StreamTextStr(t.Context(), "Hi", WithModel(model), WithRetryPolicy(fastRetryPolicy))
+		require.NoError(t, err)
+
+		var events []api.StreamEvent
+		for event := range resp.Stream {
+			events = append(events, event)
+		}
+		assert.Equal(t, model.streams[1], events)
+		assert.Equal(t, 2, model.calls)
+	})
+
+	t.Run("error after stream start", func(t *testing.T) {
+		model := &streamingLanguageModel{streams: [][]api.StreamEvent{
+			{&api.StreamStartEvent{}, &api.ErrorEvent{Err: apiCallError(503)}},
+			{&api.StreamStartEvent{}, &api.TextDeltaEvent{TextDelta: "Hello"}, &api.FinishEvent{FinishReason: api.FinishReasonStop}},
+		}}
+
+		resp, err := StreamTextStr(t.Context(), "Hi", WithModel(model), WithRetryPolicy(fastRetryPolicy))
+		require.NoError(t, err)
+
+		var events []api.StreamEvent
+		for event := range resp.Stream {
+			events = append(events, event)
+		}
+		assert.Equal(t, model.streams[1], events)
+		assert.Equal(t, 2, model.calls)
+	})
+
+	t.Run("error after first event", func(t *testing.T) {
+		model := &streamingLanguageModel{streams: [][]api.StreamEvent{
+			{&api.TextDeltaEvent{TextDelta: "Hel"}, &api.ErrorEvent{Err: apiCallError(503)}},
+		}}
+
+		resp, err := StreamTextStr(t.Context(), "Hi", WithModel(model), WithRetryPolicy(fastRetryPolicy))
+		require.NoError(t, err)
+
+		var events []api.StreamEvent
+		for event := range resp.Stream {
+			events = append(events, event)
+		}
+		assert.Equal(t, model.streams[0], events)
+		assert.Equal(t, 1, model.calls)
+	})
+
+	t.Run("non-retryable error", func(t *testing.T) {
+		model := &streamingLanguageModel{streams: [][]api.StreamEvent{
+			{&api.StreamStartEvent{}, &api.ErrorEvent{Err: apiCallError(400)}},
+		}}
+
+		resp, err := StreamTextStr(t.Context(), "Hi", WithModel(model), WithRetryPolicy(fastRetryPolicy))
+		require.NoError(t, err)
+
+		var events []api.StreamEvent
+		for event := range resp.Stream {
+			events = append(events, event)
+		}
+		assert.Equal(t, model.streams[0], events)
+		assert.Equal(t, 1, model.calls)
+	})
+}
+
+func TestRetryStream_StopsUnreadStream(t *testing.T) {
+	stopped := make(chan struct{})
+	open := func() (*api.StreamResponse, error) {
+		return &api.StreamResponse{
+			Stream: func(yield func(api.StreamEvent) bool) {
+				defer close(stopped)
+				for yield(&api.TextDeltaEvent{TextDelta: "Hello"}) {
+				}
+			},
+		}, nil
+	}
+
+	// Open the stream without ever iterating it, then drop the response.
+	func() {
+		resp, err := retryStream(t.Context(), fastRetryPolicy, open)
+		require.NoError(t, err)
+		require.NotNil(t, resp.Stream)
+	}()
+
+	require.Eventually(t, func() bool {
+		runtime.GC()
+		select {
+		case <-stopped:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestRankMany_Retry(t *testing.T) {
+	model := &flakyRankingModel{failures: 1}
+	resp, err := RankMany(t.Context(), model, "query", []string{"a"}, WithTransportRetryPolicy(fastRetryPolicy))
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1}, resp.Scores)
+	assert.Equal(t, 2, model.calls)
+}
+
+type flakyRankingModel struct {
+	failures int
+	calls    int
+}
+
+func (m *flakyRankingModel) SpecificationVersion() string { return "v1" }
+func (m *flakyRankingModel) ProviderName() string         { return "fake" }
+func (m *flakyRankingModel) ModelID() string              { return "fake-ranker" }
+func (m *flakyRankingModel) SupportsParallelCalls() bool  { return false }
+
+func (m *flakyRankingModel) DoRank(
+	ctx context.Context, query string, texts []string, opts api.TransportOptions,
+) (api.RankingResponse, error) {
+	m.calls++
+	if m.calls <= m.failures {
+		return api.RankingResponse{}, apiCallError(500)
+	}
+	return api.RankingResponse{Scores: []float64{1}}, nil
+}
@@ -0,0 +1,433 @@
+// Package telemetry records OpenTelemetry spans and metrics for model calls,
+// following the semantic conventions for generative AI systems.
+//
+// Telemetry is added to a model as middleware. Passing the same tracer
+// provider to the ai entry point groups the spans of its model calls under a
+// span of the entry point:
+//
+//	t := telemetry.New(tracerProvider, meterProvider)
+//	model := telemetry.WrapLanguageModel(openai.NewLanguageModel("gpt-4o"), t)
+//	resp, err := ai.GenerateTextStr(ctx, "Hi",
+//		ai.WithModel(model),
+//		ai.WithTracerProvider(tracerProvider),
+//	)
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"runtime"
+	"sync"
+	"time"
+
+	"go.jetify.com/ai"
+	"go.jetify.com/ai/api"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies this package as the source of spans and
+// metrics.
+const instrumentationName = "go.jetify.com/ai"
+
+// Operation names recorded in the gen_ai.operation.name attribute. Chat and
+// embeddings are defined by the GenAI semantic conventions; the conventions
+// do not cover ranking or segmenting yet.
+const (
+	operationChat       = "chat"
+	operationEmbeddings = "embeddings"
+	operationRerank     = "rerank"
+	operationSegment    = "segment"
+)
+
+// Attribute keys from the OpenTelemetry semantic conventions for generative
+// AI systems. The conventions do not define a time to first chunk yet, so it
+// is recorded under the com.jetify.ai namespace.
+const (
+	attrOperationName            = attribute.Key("gen_ai.operation.name")
+	attrProviderName             = attribute.Key("gen_ai.provider.name")
+	attrRequestModel             = attribute.Key("gen_ai.request.model")
+	attrRequestMaxTokens         = attribute.Key("gen_ai.request.max_tokens")
+	attrRequestTemperature       = attribute.Key("gen_ai.request.temperature")
+	attrRequestTopP              = attribute.Key("gen_ai.request.top_p")
+	attrRequestTopK              = attribute.Key("gen_ai.request.top_k")
+	attrRequestStopSequences     = attribute.Key("gen_ai.request.stop_sequences")
+	attrRequestSeed              = attribute.Key("gen_ai.request.seed")
+	attrRequestPresencePenalty   = attribute.Key("gen_ai.request.presence_penalty")
+	attrRequestFrequencyPenalty  = attribute.Key("gen_ai.request.frequency_penalty")
+	attrResponseID               = attribute.Key("gen_ai.response.id")
+	attrResponseModel            = attribute.Key("gen_ai.response.model")
+	attrResponseFinishReasons    = attribute.Key("gen_ai.response.finish_reasons")
+	attrResponseTimeToFirstChunk = attribute.Key("com.jetify.ai.response.time_to_first_chunk")
+	attrUsageInputTokens         = attribute.Key("gen_ai.usage.input_tokens")
+	attrUsageOutputTokens        = attribute.Key("gen_ai.usage.output_tokens")
+	attrTokenType                = attribute.Key("gen_ai.token.type")
+	attrErrorType                = attribute.Key("error.type")
+)
+
+// Telemetry emits OpenTelemetry spans and metrics for the calls made to the
+// models it wraps. A single Telemetry can be shared by any number of models
+// and concurrent calls.
+//
+// Every call to a wrapped model creates a client span named
+// "{operation} {model}" that records the provider name, model ID, request
+// parameters, token usage and finish reason. Since the span wraps the model,
+// each retry, tool step and embedding chunk gets a span of its own. When the
+// ai entry point is given a tracer provider (see [ai.WithTracerProvider] and
+// [ai.WithTransportTracerProvider]), these spans are children of the span of
+// the entry point, which records the totals across them. The span of a Stream
+// call ends when its stream has been fully consumed, the consumer stops
+// iterating, or the stream is garbage collected without being read.
+//
+// The following metrics are recorded as well:
+//
+//   - gen_ai.client.operation.duration: the duration of each call, in seconds.
+//   - gen_ai.client.token.usage: the input and output tokens of each call.
+//   - com.jetify.ai.client.operation.time_to_first_chunk: for Stream, the time
+//     until the first content event is received, in seconds.
+type Telemetry struct {
+	tracer           trace.Tracer
+	duration         metric.Float64Histogram
+	tokenUsage       metric.Int64Histogram
+	timeToFirstChunk metric.Float64Histogram
+}
+
+// New creates a Telemetry that records spans with tp and metrics with mp. A
+// nil provider falls back to the global provider registered with the otel
+// package.
+func New(tp trace.TracerProvider, mp metric.MeterProvider) *Telemetry {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+
+	meter := mp.Meter(instrumentationName)
+	t := &Telemetry{tracer: tp.Tracer(instrumentationName)}
+
+	// Instrument creation only fails on invalid names or units; the returned
+	// instrument is still usable, so report the error to otel and carry on.
+	var err error
+	t.duration, err = meter.Float64Histogram(
+		"gen_ai.client.operation.duration",
+		metric.WithDescription("GenAI operation duration."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	t.tokenUsage, err = meter.Int64Histogram(
+		"gen_ai.client.token.usage",
+		metric.WithDescription("Number of input and output tokens used."),
+		metric.WithUnit("{token}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	t.timeToFirstChunk, err = meter.Float64Histogram(
+		"com.jetify.ai.client.operation.time_to_first_chunk",
+		metric.WithDescription("Time to receive the first chunk of a streaming response."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	return t
+}
+
+// WrapLanguageModel returns a language model that records a chat span and
+// metrics for every Generate and Stream call made to model. A nil t returns
+// model unchanged.
+func WrapLanguageModel(model api.LanguageModel, t *Telemetry) api.LanguageModel {
+	if t == nil {
+		return model
+	}
+	return ai.WrapLanguageModel(model, ai.LanguageModelMiddleware{
+		WrapGenerate: func(
+			ctx context.Context, prompt []api.Message, opts api.CallOptions, next ai.GenerateFunc,
+		) (*api.Response, error) {
+			ctx, span := t.startChat(ctx, model, opts)
+			resp, err := next(ctx, prompt, opts)
+			if err == nil {
+				span.recordResponse(resp)
+			}
+			span.end(err)
+			return resp, err
+		},
+		WrapStream: func(
+			ctx context.Context, prompt []api.Message, opts api.CallOptions, next ai.StreamFunc,
+		) (*api.StreamResponse, error) {
+			ctx, span := t.startChat(ctx, model, opts)
+			resp, err := next(ctx, prompt, opts)
+			if err != nil {
+				span.end(err)
+				return nil, err
+			}
+			traced := *resp
+			traced.Stream = span.traceStream(resp.Stream)
+			return &traced, nil
+		},
+	})
+}
+
+// WrapEmbeddingModel returns an embedding model that records an embeddings
+// span and metrics for every DoEmbed call made to model. A nil t returns model
+// unchanged.
+func WrapEmbeddingModel[T api.EmbeddingInput, E api.EmbeddingVector](
+	model api.EmbeddingModel[T, E], t *Telemetry,
+) api.EmbeddingModel[T, E] {
+	if t == nil {
+		return model
+	}
+	return ai.WrapEmbeddingModel(model, ai.EmbeddingModelMiddleware[T, E]{
+		WrapDoEmbed: func(
+			ctx context.Context, values []T, opts api.TransportOptions, next ai.DoEmbedFunc[T, E],
+		) (api.EmbeddingResponse[E], error) {
+			ctx, span := t.start(ctx, operationEmbeddings, model.ProviderName(), model.ModelID())
+			resp, err := next(ctx, values, opts)
+			if err == nil && resp.Usage != nil {
+				span.recordUsage(int(resp.Usage.PromptTokens), 0)
+			}
+			span.end(err)
+			return resp, err
+		},
+	})
+}
+
+// WrapRankingModel returns a ranking model that records a rerank span and
+// metrics for every DoRank call made to model. A nil t returns model
+// unchanged.
+func WrapRankingModel(model api.RankingModel, t *Telemetry) api.RankingModel {
+	if t == nil {
+		return model
+	}
+	return ai.WrapRankingModel(model, ai.RankingModelMiddleware{
+		WrapDoRank: func(
+			ctx context.Context, query string, texts []string, opts api.TransportOptions, next ai.DoRankFunc,
+		) (api.RankingResponse, error) {
+			ctx, span := t.start(ctx, operationRerank, model.ProviderName(), model.ModelID())
+			resp, err := next(ctx, query, texts, opts)
+			if err == nil && resp.Usage != nil {
+				span.recordUsage(int(resp.Usage.TotalTokens), 0)
+			}
+			span.end(err)
+			return resp, err
+		},
+	})
+}
+
+// WrapSegmentingModel returns a segmenting model that records a segment span
+// and metrics for every DoSegment call made to model. A nil t returns model
+// unchanged.
+func WrapSegmentingModel(model api.SegmentingModel, t *Telemetry) api.SegmentingModel {
+	if t == nil {
+		return model
+	}
+	return ai.WrapSegmentingModel(model, ai.SegmentingModelMiddleware{
+		WrapDoSegment: func(
+			ctx context.Context, texts []string, opts api.TransportOptions, next ai.DoSegmentFunc,
+		) (api.SegmentingResponse, error) {
+			ctx, span := t.start(ctx, operationSegment, model.ProviderName(), model.ModelID())
+			resp, err := next(ctx, texts, opts)
+			span.end(err)
+			return resp, err
+		},
+	})
+}
+
+// callSpan tracks a single instrumented call.
+type callSpan struct {
+	telemetry *Telemetry
+	span      trace.Span
+	start     time.Time
+
+	// ctx is the context of the call. Metrics are recorded with it so that
+	// exemplars and baggage are attached to the right call.
+	ctx context.Context
+
+	// attrs are the low-cardinality attributes shared by the span and all
+	// metrics of the call.
+	attrs []attribute.KeyValue
+
+	firstChunk bool
+	endOnce    sync.Once
+}
+
+// start begins a span for operation against the given provider and model.
+func (t *Telemetry) start(
+	ctx context.Context, operation, providerName, modelID string, attrs ...attribute.KeyValue,
+) (context.Context, *callSpan) {
+	common := []attribute.KeyValue{
+		attrOperationName.String(operation),
+		attrProviderName.String(providerName),
+		attrRequestModel.String(modelID),
+	}
+	ctx, span := t.tracer.Start(ctx, operation+" "+modelID,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(common...),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx, &callSpan{telemetry: t, span: span, start: time.Now(), ctx: ctx, attrs: common}
+}
+
+// startChat begins a span for a language model call made with opts.
+func (t *Telemetry) startChat(
+	ctx context.Context, model api.LanguageModel, opts api.CallOptions,
+) (context.Context, *callSpan) {
+	return t.start(ctx, operationChat, model.ProviderName(), model.ModelID(), callOptionsAttributes(opts)...)
+}
+
+// callOptionsAttributes returns the request attributes for the parameters
+// that are set in opts.
+func callOptionsAttributes(opts api.CallOptions) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if opts.MaxOutputTokens > 0 {
+		attrs = append(attrs, attrRequestMaxTokens.Int(opts.MaxOutputTokens))
+	}
+	if opts.Temperature != nil {
+		attrs = append(attrs, attrRequestTemperature.Float64(*opts.Temperature))
+	}
+	if opts.TopP != 0 {
+		attrs = append(attrs, attrRequestTopP.Float64(opts.TopP))
+	}
+	if opts.TopK != 0 {
+		attrs = append(attrs, attrRequestTopK.Int(opts.TopK))
+	}
+	if len(opts.StopSequences) > 0 {
+		attrs = append(attrs, attrRequestStopSequences.StringSlice(opts.StopSequences))
+	}
+	if opts.Seed != 0 {
+		attrs = append(attrs, attrRequestSeed.Int(opts.Seed))
+	}
+	if opts.PresencePenalty != 0 {
+		attrs = append(attrs, attrRequestPresencePenalty.Float64(opts.PresencePenalty))
+	}
+	if opts.FrequencyPenalty != 0 {
+		attrs = append(attrs, attrRequestFrequencyPenalty.Float64(opts.FrequencyPenalty))
+	}
+	return attrs
+}
+
+// traceStream returns a stream that forwards the events of stream and ends
+// the span once the stream is exhausted or the consumer stops reading.
+func (s *callSpan) traceStream(stream iter.Seq[api.StreamEvent]) iter.Seq[api.StreamEvent] {
+	if stream == nil {
+		s.end(nil)
+		return nil
+	}
+	traced := &tracedStream{span: s, stream: stream}
+	// A caller that never iterates the stream would otherwise leave the span
+	// open forever.
+	runtime.AddCleanup(traced, func(s *callSpan) { s.end(nil) }, s)
+	return traced.all
+}
+
+// tracedStream records the events of a stream on the span of its call.
+type tracedStream struct {
+	span   *callSpan
+	stream iter.Seq[api.StreamEvent]
+}
+
+func (t *tracedStream) all(yield func(api.StreamEvent) bool) {
+	s := t.span
+	var streamErr error
+	defer func() { s.end(streamErr) }()
+
+	for event := range t.stream {
+		switch event := event.(type) {
+		case *api.TextDeltaEvent, *api.ReasoningEvent, *api.ToolCallDeltaEvent,
+			*api.ToolCallEvent, *api.FileEvent:
+			s.recordFirstChunk()
+		case *api.ResponseMetadataEvent:
+			s.recordResponseInfo(event.ID, event.ModelID)
+		case *api.FinishEvent:
+			s.recordFinish(event.FinishReason, event.Usage)
+		case *api.ErrorEvent:
+			streamErr = event
+			if event.Err != nil {
+				streamErr = event.Err
+			}
+		}
+		if !yield(event) {
+			return
+		}
+	}
+}
+
+func (s *callSpan) recordResponse(resp *api.Response) {
+	if resp == nil {
+		return
+	}
+	if resp.ResponseInfo != nil {
+		s.recordResponseInfo(resp.ResponseInfo.ID, resp.ResponseInfo.ModelID)
+	}
+	s.recordFinish(resp.FinishReason, resp.Usage)
+}
+
+func (s *callSpan) recordResponseInfo(id, modelID string) {
+	if id != "" {
+		s.span.SetAttributes(attrResponseID.String(id))
+	}
+	if modelID != "" {
+		s.span.SetAttributes(attrResponseModel.String(modelID))
+	}
+}
+
+func (s *callSpan) recordFinish(reason api.FinishReason, usage api.Usage) {
+	if reason != "" {
+		s.span.SetAttributes(attrResponseFinishReasons.StringSlice([]string{string(reason)}))
+	}
+	s.recordUsage(usage.InputTokens, usage.OutputTokens)
+}
+
+// recordUsage records token counts on the span and the token usage metric.
+// Zero counts are treated as not reported by the provider.
+func (s *callSpan) recordUsage(inputTokens, outputTokens int) {
+	if inputTokens > 0 {
+		s.span.SetAttributes(attrUsageInputTokens.Int(inputTokens))
+		s.telemetry.tokenUsage.Record(s.ctx, int64(inputTokens),
+			metric.WithAttributes(s.attrs...), metric.WithAttributes(attrTokenType.String("input")))
+	}
+	if outputTokens > 0 {
+		s.span.SetAttributes(attrUsageOutputTokens.Int(outputTokens))
+		s.telemetry.tokenUsage.Record(s.ctx, int64(outputTokens),
+			metric.WithAttributes(s.attrs...), metric.WithAttributes(attrTokenType.String("output")))
+	}
+}
+
+// recordFirstChunk records the time to first chunk the first time it is
+// called.
+func (s *callSpan) recordFirstChunk() {
+	if s.firstChunk {
+		return
+	}
+	s.firstChunk = true
+	elapsed := time.Since(s.start).Seconds()
+	s.span.SetAttributes(attrResponseTimeToFirstChunk.Float64(elapsed))
+	s.telemetry.timeToFirstChunk.Record(s.ctx, elapsed, metric.WithAttributes(s.attrs...))
+}
+
+// end records the outcome and duration of the call and ends the span. Only
+// the first call has an effect.
+func (s *callSpan) end(err error) {
+	s.endOnce.Do(func() { s.finish(err) })
+}
+
+func (s *callSpan) finish(err error) {
+	attrs := s.attrs
+	if err != nil {
+		errorType := attrErrorType.String(fmt.Sprintf("%T", err))
+		attrs = append(attrs[:len(attrs):len(attrs)], errorType)
+		s.span.SetAttributes(errorType)
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.telemetry.duration.Record(s.ctx, time.Since(s.start).Seconds(),
+		metric.WithAttributes(attrs...))
+	s.span.End()
+}
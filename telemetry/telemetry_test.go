@@ -0,0 +1,489 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai"
+	"go.jetify.com/ai/api"
+	"go.jetify.com/ai/provider/mock"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// recorder keeps the spans and measurements recorded by a Telemetry in
+// memory, so that tests do not depend on the OpenTelemetry SDK.
+type recorder struct {
+	mu           sync.Mutex
+	spans        []*recordedSpan
+	measurements []measurement
+}
+
+type recordedSpan struct {
+	tracenoop.Span
+	recorder *recorder
+
+	name       string
+	kind       trace.SpanKind
+	parent     *recordedSpan
+	attrs      map[attribute.Key]attribute.Value
+	statusCode codes.Code
+	statusDesc string
+	ended      bool
+}
+
+type measurement struct {
+	name  string
+	value float64
+	attrs attribute.Set
+	ctx   context.Context
+}
+
+func newTestTelemetry() (*Telemetry, *recorder) {
+	r := &recorder{}
+	return New(&tracerProvider{recorder: r}, &meterProvider{recorder: r}), r
+}
+
+// ended returns the spans that have ended, in the order they were started.
+func (r *recorder) ended() []*recordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var spans []*recordedSpan
+	for _, span := range r.spans {
+		if span.ended {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// recorded returns the measurements of the named instrument.
+func (r *recorder) recorded(name string) []measurement {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var measurements []measurement
+	for _, m := range r.measurements {
+		if m.name == name {
+			measurements = append(measurements, m)
+		}
+	}
+	return measurements
+}
+
+func (r *recorder) record(name string, value float64, ctx context.Context, opts []metric.RecordOption) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attrs := metric.NewRecordConfig(opts).Attributes()
+	r.measurements = append(r.measurements, measurement{name: name, value: value, attrs: attrs, ctx: ctx})
+}
+
+type tracerProvider struct {
+	tracenoop.TracerProvider
+	recorder *recorder
+}
+
+func (p *tracerProvider) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return &tracer{recorder: p.recorder}
+}
+
+type tracer struct {
+	tracenoop.Tracer
+	recorder *recorder
+}
+
+func (t *tracer) Start(
+	ctx context.Context, name string, opts ...trace.SpanStartOption,
+) (context.Context, trace.Span) {
+	config := trace.NewSpanStartConfig(opts...)
+	span := &recordedSpan{
+		recorder: t.recorder,
+		name:     name,
+		kind:     config.SpanKind(),
+		attrs:    map[attribute.Key]attribute.Value{},
+	}
+	span.parent, _ = trace.SpanFromContext(ctx).(*recordedSpan)
+	span.SetAttributes(config.Attributes()...)
+
+	t.recorder.mu.Lock()
+	t.recorder.spans = append(t.recorder.spans, span)
+	t.recorder.mu.Unlock()
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...attribute.KeyValue) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	for _, kv := range attrs {
+		s.attrs[kv.Key] = kv.Value
+	}
+}
+
+func (s *recordedSpan) SetStatus(code codes.Code, description string) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.statusCode, s.statusDesc = code, description
+}
+
+func (s *recordedSpan) IsRecording() bool {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	return !s.ended
+}
+
+func (s *recordedSpan) End(...trace.SpanEndOption) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.ended = true
+}
+
+type meterProvider struct {
+	metricnoop.MeterProvider
+	recorder *recorder
+}
+
+func (p *meterProvider) Meter(string, ...metric.MeterOption) metric.Meter {
+	return &meter{recorder: p.recorder}
+}
+
+type meter struct {
+	metricnoop.Meter
+	recorder *recorder
+}
+
+func (m *meter) Float64Histogram(name string, _ ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	return &float64Histogram{name: name, recorder: m.recorder}, nil
+}
+
+func (m *meter) Int64Histogram(name string, _ ...metric.Int64HistogramOption) (metric.Int64Histogram, error) {
+	return &int64Histogram{name: name, recorder: m.recorder}, nil
+}
+
+type float64Histogram struct {
+	metricnoop.Float64Histogram
+	name     string
+	recorder *recorder
+}
+
+func (h *float64Histogram) Record(ctx context.Context, value float64, opts ...metric.RecordOption) {
+	h.recorder.record(h.name, value, ctx, opts)
+}
+
+type int64Histogram struct {
+	metricnoop.Int64Histogram
+	name     string
+	recorder *recorder
+}
+
+func (h *int64Histogram) Record(ctx context.Context, value int64, opts ...metric.RecordOption) {
+	h.recorder.record(h.name, float64(value), ctx, opts)
+}
+
+type ctxKey struct{}
+
+func TestWrapLanguageModel_Generate(t *testing.T) {
+	telemetry, recorder := newTestTelemetry()
+	model := WrapLanguageModel(mock.NewGenerateModel([]mock.MockResult{{Response: &api.Response{
+		Content:      []api.ContentBlock{&api.TextBlock{Text: "Hello"}},
+		FinishReason: api.FinishReasonStop,
+		Usage:        api.Usage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15},
+		ResponseInfo: &api.ResponseInfo{ID: "resp-1", ModelID: "test-model-2025"},
+	}}}, mock.WithModelID("test-model")), telemetry)
+
+	ctx := context.WithValue(t.Context(), ctxKey{}, "call")
+	_, err := ai.GenerateTextStr(ctx, "Hi",
+		ai.WithModel(model), ai.WithMaxOutputTokens(100), ai.WithTemperature(0.5))
+	require.NoError(t, err)
+
+	spans := recorder.ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "chat test-model", span.name)
+	assert.Equal(t, trace.SpanKindClient, span.kind)
+	assert.Equal(t, map[attribute.Key]attribute.Value{
+		"gen_ai.operation.name":          attribute.StringValue("chat"),
+		"gen_ai.provider.name":           attribute.StringValue("mock-provider"),
+		"gen_ai.request.model":           attribute.StringValue("test-model"),
+		"gen_ai.request.max_tokens":      attribute.IntValue(100),
+		"gen_ai.request.temperature":     attribute.Float64Value(0.5),
+		"gen_ai.response.id":             attribute.StringValue("resp-1"),
+		"gen_ai.response.model":          attribute.StringValue("test-model-2025"),
+		"gen_ai.response.finish_reasons": attribute.StringSliceValue([]string{"stop"}),
+		"gen_ai.usage.input_tokens":      attribute.IntValue(10),
+		"gen_ai.usage.output_tokens":     attribute.IntValue(5),
+	}, span.attrs)
+
+	require.Len(t, recorder.recorded("gen_ai.client.operation.duration"), 1)
+	usage := map[string]float64{}
+	for _, m := range recorder.recorded("gen_ai.client.token.usage") {
+		tokenType, _ := m.attrs.Value("gen_ai.token.type")
+		usage[tokenType.AsString()] += m.value
+		assert.Equal(t, "call", m.ctx.Value(ctxKey{}), "metrics should be recorded with the call context")
+		assert.Equal(t, span, trace.SpanFromContext(m.ctx), "metrics should be recorded within the span")
+	}
+	assert.Equal(t, map[string]float64{"input": 10, "output": 5}, usage)
+}
+
+func TestWrapLanguageModel_GenerateError(t *testing.T) {
+	telemetry, recorder := newTestTelemetry()
+	model := WrapLanguageModel(mock.NewGenerateModel([]mock.MockResult{
+		{Error: errors.New("unexpected call")},
+	}), telemetry)
+
+	_, err := ai.GenerateTextStr(t.Context(), "Hi", ai.WithModel(model))
+	require.Error(t, err)
+
+	spans := recorder.ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].statusCode)
+	assert.Equal(t, "unexpected call", spans[0].statusDesc)
+	assert.Equal(t, attribute.StringValue("*errors.errorString"), spans[0].attrs["error.type"])
+}
+
+func TestWrapLanguageModel_Stream(t *testing.T) {
+	telemetry, recorder := newTestTelemetry()
+	model := WrapLanguageModel(mock.NewStreamModel([]mock.MockStream{{Events: []api.StreamEvent{
+		&api.ResponseMetadataEvent{ID: "resp-1"},
+		&api.TextDeltaEvent{TextDelta: "Hello"},
+		&api.TextDeltaEvent{TextDelta: " world"},
+		&api.FinishEvent{
+			FinishReason: api.FinishReasonStop,
+			Usage:        api.Usage{InputTokens: 3, OutputTokens: 2},
+		},
+	}}}), telemetry)
+
+	resp, err := ai.StreamTextStr(t.Context(), "Hi", ai.WithModel(model))
+	require.NoError(t, err)
+	assert.Empty(t, recorder.ended(), "span should stay open until the stream is consumed")
+
+	for range resp.Stream {
+	}
+
+	spans := recorder.ended()
+	require.Len(t, spans, 1)
+	attrs := spans[0].attrs
+	assert.Equal(t, attribute.StringValue("resp-1"), attrs["gen_ai.response.id"])
+	assert.Equal(t, attribute.StringSliceValue([]string{"stop"}), attrs["gen_ai.response.finish_reasons"])
+	assert.Equal(t, attribute.IntValue(3), attrs["gen_ai.usage.input_tokens"])
+	assert.Equal(t, attribute.IntValue(2), attrs["gen_ai.usage.output_tokens"])
+	assert.Contains(t, attrs, attribute.Key("com.jetify.ai.response.time_to_first_chunk"))
+	assert.Len(t, recorder.recorded("com.jetify.ai.client.operation.time_to_first_chunk"), 1)
+}
+
+func TestWrapLanguageModel_StreamError(t *testing.T) {
+	telemetry, recorder := newTestTelemetry()
+	model := WrapLanguageModel(mock.NewStreamModel([]mock.MockStream{{Events: []api.StreamEvent{
+		&api.TextDeltaEvent{TextDelta: "Hello"},
+		&api.ErrorEvent{Err: errors.New("connection reset")},
+	}}}), telemetry)
+
+	resp, err := ai.StreamTextStr(t.Context(), "Hi", ai.WithModel(model))
+	require.NoError(t, err)
+	for range resp.Stream {
+	}
+
+	spans := recorder.ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].statusCode)
+	assert.Equal(t, "connection reset", spans[0].statusDesc)
+}
+
+func TestWrapLanguageModel_StreamNeverRead(t *testing.T) {
+	telemetry, recorder := newTestTelemetry()
+	model := WrapLanguageModel(mock.NewStreamModel([]mock.MockStream{{Events: []api.StreamEvent{
+		&api.TextDeltaEvent{TextDelta: "Hello"},
+	}}}), telemetry)
+
+	func() {
+		_, err := model.Stream(t.Context(), nil, api.CallOptions{})
+		require.NoError(t, err)
+	}()
+
+	assert.Eventually(t, func() bool {
+		runtime.GC()
+		return len(recorder.ended()) == 1
+	}, 5*time.Second, 10*time.Millisecond, "span of a dropped stream should end")
+}
+
+func TestWrapEmbeddingModel(t *testing.T) {
+	telemetry, recorder := newTestTelemetry()
+	model := WrapEmbeddingModel(mock.NewEmbeddingModel[string]([]mock.EmbeddingResult[api.Embedding]{{
+		Response: api.EmbeddingResponse[api.Embedding]{
+			Embeddings: []api.Embedding{{1}, {2}},
+			Usage:      &api.EmbeddingUsage{PromptTokens: 3},
+		},
+	}}, mock.WithModelID("test-embedding")), telemetry)
+
+	_, err := ai.EmbedMany(t.Context(), model, []string{"a", "b"})
+	require.NoError(t, err)
+
+	spans := recorder.ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "embeddings test-embedding", spans[0].name)
+	assert.Equal(t, attribute.StringValue("mock-provider"), spans[0].attrs["gen_ai.provider.name"])
+	assert.Equal(t, attribute.IntValue(3), spans[0].attrs["gen_ai.usage.input_tokens"])
+}
+
+func TestWrapRankingAndSegmentingModel(t *testing.T) {
+	telemetry, recorder := newTestTelemetry()
+	ranker := WrapRankingModel(mock.NewRankingModel(nil, mock.WithModelID("test-ranker")), telemetry)
+	segmenter := WrapSegmentingModel(mock.NewSegmentingModel(nil, mock.WithModelID("test-segmenter")), telemetry)
+
+	_, err := ai.RankMany(t.Context(), ranker, "query", []string{"a"})
+	require.NoError(t, err)
+	_, err = ai.SegmentMany(t.Context(), segmenter, []string{"a b"})
+	require.NoError(t, err)
+
+	spans := recorder.ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "rerank test-ranker", spans[0].name)
+	assert.Equal(t, "segment test-segmenter", spans[1].name)
+}
+
+func TestWrap_NilTelemetry(t *testing.T) {
+	model := mock.NewGenerateModel(nil)
+	assert.Same(t, model, WrapLanguageModel(model, nil))
+}
+
+func TestEntryPointSpan_GenerateText(t *testing.T) {
+	telemetry, recorder := newTestTelemetry()
+	model := WrapLanguageModel(mock.NewGenerateModel([]mock.MockResult{
+		{Response: &api.Response{
+			Content: []api.ContentBlock{
+				&api.ToolCallBlock{ToolCallID: "call-1", ToolName: "echo", Args: []byte(`{}`)},
+			},
+			FinishReason: api.FinishReasonToolCalls,
+			Usage:        api.Usage{InputTokens: 10, OutputTokens: 5},
+		}},
+		{Response: &api.Response{
+			Content:      []api.ContentBlock{&api.TextBlock{Text: "done"}},
+			FinishReason: api.FinishReasonStop,
+			Usage:        api.Usage{InputTokens: 20, OutputTokens: 2},
+		}},
+	}, mock.WithModelID("test-model")), telemetry)
+	echo := ai.ExecutableTool{
+		Definition: &api.FunctionTool{Name: "echo"},
+		Execute: func(ctx context.Context, args json.RawMessage) (any, error) {
+			return "ok", nil
+		},
+	}
+
+	_, err := ai.GenerateTextStr(t.Context(), "Hi",
+		ai.WithModel(model),
+		ai.WithExecutableTools(echo),
+		ai.WithMaxSteps(2),
+		ai.WithTracerProvider(&tracerProvider{recorder: recorder}),
+	)
+	require.NoError(t, err)
+
+	spans := recorder.ended()
+	require.Len(t, spans, 3)
+	entry := spans[0]
+	assert.Equal(t, "ai.GenerateText", entry.name)
+	assert.Equal(t, trace.SpanKindInternal, entry.kind)
+	assert.Nil(t, entry.parent)
+	assert.Equal(t, map[attribute.Key]attribute.Value{
+		"gen_ai.operation.name":          attribute.StringValue("chat"),
+		"gen_ai.provider.name":           attribute.StringValue("mock-provider"),
+		"gen_ai.request.model":           attribute.StringValue("test-model"),
+		"gen_ai.response.finish_reasons": attribute.StringSliceValue([]string{"stop"}),
+		"gen_ai.usage.input_tokens":      attribute.IntValue(30),
+		"gen_ai.usage.output_tokens":     attribute.IntValue(7),
+		"com.jetify.ai.steps":            attribute.IntValue(2),
+	}, entry.attrs)
+	for _, step := range spans[1:] {
+		assert.Equal(t, "chat test-model", step.name)
+		assert.Same(t, entry, step.parent, "model calls should be children of the entry point")
+	}
+}
+
+func TestEntryPointSpan_StreamText(t *testing.T) {
+	telemetry, recorder := newTestTelemetry()
+	model := WrapLanguageModel(mock.NewStreamModel([]mock.MockStream{{Events: []api.StreamEvent{
+		&api.TextDeltaEvent{TextDelta: "Hello"},
+		&api.FinishEvent{
+			FinishReason: api.FinishReasonStop,
+			Usage:        api.Usage{InputTokens: 3, OutputTokens: 2},
+		},
+	}}}), telemetry)
+
+	resp, err := ai.StreamTextStr(t.Context(), "Hi",
+		ai.WithModel(model),
+		ai.WithTracerProvider(&tracerProvider{recorder: recorder}),
+	)
+	require.NoError(t, err)
+	assert.Empty(t, recorder.ended(), "spans should stay open until the stream is consumed")
+
+	for range resp.Stream {
+	}
+
+	spans := recorder.ended()
+	require.Len(t, spans, 2)
+	entry, call := spans[0], spans[1]
+	assert.Equal(t, "ai.StreamText", entry.name)
+	assert.Same(t, entry, call.parent)
+	assert.Equal(t, attribute.StringSliceValue([]string{"stop"}), entry.attrs["gen_ai.response.finish_reasons"])
+	assert.Equal(t, attribute.IntValue(3), entry.attrs["gen_ai.usage.input_tokens"])
+	assert.Equal(t, attribute.IntValue(2), entry.attrs["gen_ai.usage.output_tokens"])
+}
+
+func TestEntryPointSpan_EmbedMany(t *testing.T) {
+	telemetry, recorder := newTestTelemetry()
+	chunk := api.EmbeddingResponse[api.Embedding]{
+		Embeddings: []api.Embedding{{1}},
+		Usage:      &api.EmbeddingUsage{PromptTokens: 3},
+	}
+	model := WrapEmbeddingModel(mock.NewEmbeddingModel[string](
+		[]mock.EmbeddingResult[api.Embedding]{{Response: chunk}, {Response: chunk}},
+		mock.WithModelID("test-embedding"), mock.WithMaxEmbeddingsPerCall(1),
+	), telemetry)
+
+	_, err := ai.EmbedMany(t.Context(), model, []string{"a", "b"},
+		ai.WithTransportTracerProvider(&tracerProvider{recorder: recorder}))
+	require.NoError(t, err)
+
+	spans := recorder.ended()
+	require.Len(t, spans, 3)
+	entry := spans[0]
+	assert.Equal(t, "ai.EmbedMany", entry.name)
+	assert.Equal(t, attribute.StringValue("embeddings"), entry.attrs["gen_ai.operation.name"])
+	assert.Equal(t, attribute.IntValue(2), entry.attrs["com.jetify.ai.chunks"])
+	assert.Equal(t, attribute.IntValue(6), entry.attrs["gen_ai.usage.input_tokens"])
+	for _, chunk := range spans[1:] {
+		assert.Equal(t, "embeddings test-embedding", chunk.name)
+		assert.Same(t, entry, chunk.parent)
+	}
+}
+
+func TestEntryPointSpan_RankManyAndSegmentMany(t *testing.T) {
+	telemetry, recorder := newTestTelemetry()
+	tp := ai.WithTransportTracerProvider(&tracerProvider{recorder: recorder})
+	ranker := WrapRankingModel(mock.NewRankingModel(nil, mock.WithModelID("test-ranker")), telemetry)
+	segmenter := WrapSegmentingModel(mock.NewSegmentingModel([]mock.SegmentingResult{
+		{Error: errors.New("segmenter down")},
+	}, mock.WithModelID("test-segmenter")), telemetry)
+
+	_, err := ai.RankMany(t.Context(), ranker, "query", []string{"a"}, tp)
+	require.NoError(t, err)
+	_, err = ai.SegmentMany(t.Context(), segmenter, []string{"a b"}, tp)
+	require.Error(t, err)
+
+	spans := recorder.ended()
+	require.Len(t, spans, 4)
+	assert.Equal(t, "ai.RankMany", spans[0].name)
+	assert.Same(t, spans[0], spans[1].parent)
+	assert.Equal(t, "ai.SegmentMany", spans[2].name)
+	assert.Same(t, spans[2], spans[3].parent)
+	assert.Equal(t, codes.Error, spans[2].statusCode)
+	assert.Equal(t, "segmenter down", spans[2].statusDesc)
+}
@@ -0,0 +1,160 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"go.jetify.com/ai/api"
+)
+
+// ToolExecuteFunc executes a tool call requested by the model.
+//
+// args contains the raw JSON arguments generated by the model. The returned
+// value must be JSON-serializable; it is sent back to the model as the tool
+// result. A returned error is reported to the model as an error result instead
+// of aborting the generation, and so is a panic.
+type ToolExecuteFunc func(ctx context.Context, args json.RawMessage) (any, error)
+
+// ExecutableTool binds a function tool definition to the Go function that
+// executes it.
+type ExecutableTool struct {
+	// Definition describes the tool to the model.
+	Definition *api.FunctionTool
+
+	// Execute is called with the model-generated arguments whenever the model
+	// calls the tool.
+	Execute ToolExecuteFunc
+}
+
+// generateSteps runs the model in a loop: it executes the tools requested in
+// each response, appends the tool calls and their results to the prompt, and
+// calls the model again until it stops calling tools, calls a tool that has
+// no executor, or the step limit is reached.
+func generateSteps(ctx context.Context, prompt []api.Message, opts GenerateOptions) (*api.Response, error) {
+	maxSteps := max(opts.MaxSteps, 1)
+	messages := slices.Clone(prompt)
+
+	var (
+		steps []api.Step
+		usage api.Usage
+	)
+	for {
+		resp, err := generateOnce(ctx, messages, opts)
+		if err != nil {
+			return nil, err
+		}
+		addUsage(&usage, resp.Usage)
+
+		step := api.Step{Response: resp}
+		calls := toolCalls(resp.Content)
+		step.ToolResults = executeTools(ctx, calls, opts.ToolExecutors)
+		steps = append(steps, step)
+
+		done := len(calls) == 0 || len(step.ToolResults) != len(calls)
+		if done || len(steps) >= maxSteps {
+			break
+		}
+
+		messages = append(messages,
+			&api.AssistantMessage{Content: assistantContent(resp.Content)},
+			&api.ToolMessage{Content: step.ToolResults},
+		)
+	}
+
+	final := *steps[len(steps)-1].Response
+	final.Usage = usage
+	final.Steps = steps
+	return &final, nil
+}
+
+// toolCalls returns the tool call blocks contained in content, in order.
+func toolCalls(content []api.ContentBlock) []*api.ToolCallBlock {
+	var calls []*api.ToolCallBlock
+	for _, block := range content {
+		if call, ok := block.(*api.ToolCallBlock); ok {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// executeTools runs the executors for the given tool calls concurrently and
+// returns their results in call order. Calls without a registered executor
+// are skipped.
+func executeTools(
+	ctx context.Context, calls []*api.ToolCallBlock, executors map[string]ToolExecuteFunc,
+) []api.ToolResultBlock {
+	results := make([]*api.ToolResultBlock, len(calls))
+
+	var wg sync.WaitGroup
+	for i, call := range calls {
+		execute, ok := executors[call.ToolName]
+		if !ok || execute == nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = executeTool(ctx, call, execute)
+		}()
+	}
+	wg.Wait()
+
+	var blocks []api.ToolResultBlock
+	for _, result := range results {
+		if result != nil {
+			blocks = append(blocks, *result)
+		}
+	}
+	return blocks
+}
+
+// executeTool runs a single executor. Errors and panics are both reported as
+// error results, since a panic in an executor's goroutine would otherwise
+// crash the program.
+func executeTool(ctx context.Context, call *api.ToolCallBlock, execute ToolExecuteFunc) (result *api.ToolResultBlock) {
+	result = &api.ToolResultBlock{
+		ToolCallID: call.ToolCallID,
+		ToolName:   call.ToolName,
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			result.Result = fmt.Sprintf("tool %q panicked: %v", call.ToolName, r)
+			result.IsError = true
+		}
+	}()
+	value, err := execute(ctx, call.Args)
+	if err != nil {
+		result.Result = err.Error()
+		result.IsError = true
+		return result
+	}
+	result.Result = value
+	return result
+}
+
+// assistantContent returns the blocks of a response that are sent back to the
+// model as the assistant message of the next step. Reasoning blocks are kept:
+// Anthropic requires the thinking blocks that preceded a tool call to be sent
+// back along with its result.
+func assistantContent(content []api.ContentBlock) []api.ContentBlock {
+	var blocks []api.ContentBlock
+	for _, block := range content {
+		switch block.(type) {
+		case *api.TextBlock, *api.ReasoningBlock, *api.ToolCallBlock:
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+func addUsage(total *api.Usage, usage api.Usage) {
+	total.InputTokens += usage.InputTokens
+	total.OutputTokens += usage.OutputTokens
+	total.TotalTokens += usage.TotalTokens
+	total.ReasoningTokens += usage.ReasoningTokens
+	total.CachedInputTokens += usage.CachedInputTokens
+}
@@ -0,0 +1,232 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/ai/api"
+)
+
+// scriptedLanguageModel returns the scripted responses in order and records
+// the prompt and options of every call.
+type scriptedLanguageModel struct {
+	mockLanguageModel
+	responses []*api.Response
+	prompts   [][]api.Message
+	options   []api.CallOptions
+}
+
+func (m *scriptedLanguageModel) Generate(
+	ctx context.Context, prompt []api.Message, opts api.CallOptions,
+) (*api.Response, error) {
+	m.prompts = append(m.prompts, prompt)
+	m.options = append(m.options, opts)
+	if len(m.prompts) > len(m.responses) {
+		return nil, errors.New("unexpected call")
+	}
+	return m.responses[len(m.prompts)-1], nil
+}
+
+func weatherTool() ExecutableTool {
+	return ExecutableTool{
+		Definition: &api.FunctionTool{Name: "weather"},
+		Execute: func(ctx context.Context, args json.RawMessage) (any, error) {
+			var input struct {
+				City string `json:"city"`
+			}
+			if err := json.Unmarshal(args, &input); err != nil {
+				return nil, err
+			}
+			if input.City == "" {
+				return nil, errors.New("missing city")
+			}
+			return map[string]any{"city": input.City, "temperature": 21}, nil
+		},
+	}
+}
+
+func toolCallResponse(calls ...*api.ToolCallBlock) *api.Response {
+	content := make([]api.ContentBlock, len(calls))
+	for i, call := range calls {
+		content[i] = call
+	}
+	return &api.Response{
+		Content:      content,
+		FinishReason: api.FinishReasonToolCalls,
+		Usage:        api.Usage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15},
+	}
+}
+
+func TestGenerateText_ToolLoop(t *testing.T) {
+	call := &api.ToolCallBlock{ToolCallID: "call-1", ToolName: "weather", Args: json.RawMessage(`{"city":"Paris"}`)}
+	final := &api.Response{
+		Content:      []api.ContentBlock{&api.TextBlock{Text: "It is 21 degrees in Paris."}},
+		FinishReason: api.FinishReasonStop,
+		Usage:        api.Usage{InputTokens: 20, OutputTokens: 8, TotalTokens: 28},
+	}
+	reasoning := &api.ReasoningBlock{Text: "I should look up the weather.", Signature: "sig"}
+	first := toolCallResponse(call)
+	first.Content = append([]api.ContentBlock{reasoning}, first.Content...)
+	model := &scriptedLanguageModel{responses: []*api.Response{first, final}}
+
+	resp, err := GenerateTextStr(t.Context(), "What's the weather in Paris?",
+		WithModel(model),
+		WithExecutableTools(weatherTool()),
+		WithMaxSteps(5),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, final.Content, resp.Content)
+	assert.Equal(t, api.FinishReasonStop, resp.FinishReason)
+	assert.Equal(t, api.Usage{InputTokens: 30, OutputTokens: 13, TotalTokens: 43}, resp.Usage)
+
+	require.Len(t, resp.Steps, 2)
+	assert.Equal(t, api.FinishReasonToolCalls, resp.Steps[0].Response.FinishReason)
+	assert.Equal(t, []api.ToolResultBlock{{
+		ToolCallID: "call-1",
+		ToolName:   "weather",
+		Result:     map[string]any{"city": "Paris", "temperature": 21},
+	}}, resp.Steps[0].ToolResults)
+	assert.Same(t, final, resp.Steps[1].Response)
+	assert.Empty(t, resp.Steps[1].ToolResults)
+
+	require.Len(t, model.prompts, 2)
+	second := model.prompts[1]
+	require.Len(t, second, 3)
+	assert.Equal(t, &api.AssistantMessage{Content: []api.ContentBlock{reasoning, call}}, second[1])
+	assert.Equal(t, &api.ToolMessage{Content: resp.Steps[0].ToolResults}, second[2])
+}
+
+func TestGenerateText_ToolLoopStops(t *testing.T) {
+	weatherCall := &api.ToolCallBlock{ToolCallID: "call-1", ToolName: "weather", Args: json.RawMessage(`{"city":"Paris"}`)}
+	unknownCall := &api.ToolCallBlock{ToolCallID: "call-2", ToolName: "unknown", Args: json.RawMessage(`{}`)}
+
+	tests := []struct {
+		name          string
+		responses     []*api.Response
+		maxSteps      int
+		expectedSteps int
+	}{
+		{
+			name:          "max steps reached",
+			responses:     []*api.Response{toolCallResponse(weatherCall), toolCallResponse(weatherCall)},
+			maxSteps:      2,
+			expectedSteps: 2,
+		},
+		{
+			name:          "default single step",
+			responses:     []*api.Response{toolCallResponse(weatherCall)},
+			expectedSteps: 1,
+		},
+		{
+			name:          "tool without executor",
+			responses:     []*api.Response{toolCallResponse(weatherCall, unknownCall)},
+			maxSteps:      5,
+			expectedSteps: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := &scriptedLanguageModel{responses: tt.responses}
+			resp, err := GenerateTextStr(t.Context(), "prompt",
+				WithModel(model),
+				WithExecutableTools(weatherTool()),
+				WithMaxSteps(tt.maxSteps),
+			)
+			require.NoError(t, err)
+			assert.Len(t, resp.Steps, tt.expectedSteps)
+			assert.Len(t, model.prompts, tt.expectedSteps)
+			assert.Len(t, resp.Steps[0].ToolResults, 1)
+		})
+	}
+}
+
+func TestGenerateText_ToolError(t *testing.T) {
+	calls := []*api.ToolCallBlock{
+		{ToolCallID: "call-1", ToolName: "weather", Args: json.RawMessage(`{}`)},
+		{ToolCallID: "call-2", ToolName: "weather", Args: json.RawMessage(`{"city":"Rome"}`)},
+	}
+	model := &scriptedLanguageModel{responses: []*api.Response{
+		toolCallResponse(calls...),
+		{Content: []api.ContentBlock{&api.TextBlock{Text: "done"}}},
+	}}
+
+	resp, err := GenerateTextStr(t.Context(), "prompt",
+		WithModel(model),
+		WithExecutableTools(weatherTool()),
+		WithMaxSteps(3),
+	)
+	require.NoError(t, err)
+	require.Len(t, resp.Steps, 2)
+	assert.Equal(t, []api.ToolResultBlock{
+		{ToolCallID: "call-1", ToolName: "weather", Result: "missing city", IsError: true},
+		{ToolCallID: "call-2", ToolName: "weather", Result: map[string]any{"city": "Rome", "temperature": 21}},
+	}, resp.Steps[0].ToolResults)
+}
+
+func TestGenerateText_ToolPanic(t *testing.T) {
+	calls := []*api.ToolCallBlock{
+		{ToolCallID: "call-1", ToolName: "broken", Args: json.RawMessage(`{}`)},
+		{ToolCallID: "call-2", ToolName: "weather", Args: json.RawMessage(`{"city":"Rome"}`)},
+	}
+	model := &scriptedLanguageModel{responses: []*api.Response{
+		toolCallResponse(calls...),
+		{Content: []api.ContentBlock{&api.TextBlock{Text: "done"}}},
+	}}
+	broken := ExecutableTool{
+		Definition: &api.FunctionTool{Name: "broken"},
+		Execute: func(ctx context.Context, args json.RawMessage) (any, error) {
+			panic("boom")
+		},
+	}
+
+	resp, err := GenerateTextStr(t.Context(), "prompt",
+		WithModel(model),
+		WithExecutableTools(broken, weatherTool()),
+		WithMaxSteps(3),
+	)
+	require.NoError(t, err)
+	require.Len(t, resp.Steps, 2)
+	assert.Equal(t, []api.ToolResultBlock{
+		{ToolCallID: "call-1", ToolName: "broken", Result: `tool "broken" panicked: boom`, IsError: true},
+		{ToolCallID: "call-2", ToolName: "weather", Result: map[string]any{"city": "Rome", "temperature": 21}},
+	}, resp.Steps[0].ToolResults)
+}
+
+func TestWithExecutableTools(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []GenerateOption
+	}{
+		{
+			name: "after WithTools",
+			opts: []GenerateOption{
+				WithTools(&api.FunctionTool{Name: "other"}),
+				WithExecutableTools(weatherTool()),
+			},
+		},
+		{
+			name: "before WithTools",
+			opts: []GenerateOption{
+				WithExecutableTools(weatherTool()),
+				WithTools(&api.FunctionTool{Name: "other"}),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := buildGenerateConfig(tt.opts)
+			assert.Equal(t, []api.ToolDefinition{
+				&api.FunctionTool{Name: "other"},
+				&api.FunctionTool{Name: "weather"},
+			}, config.CallOptions.Tools)
+			assert.Contains(t, config.ToolExecutors, "weather")
+		})
+	}
+}
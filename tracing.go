@@ -0,0 +1,169 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"runtime"
+	"sync"
+
+	"go.jetify.com/ai/api"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// instrumentationName identifies this package as the source of the spans of
+// its entry points.
+const instrumentationName = "go.jetify.com/ai"
+
+// Operation names recorded in the gen_ai.operation.name attribute. They match
+// the ones recorded by the telemetry package for the calls made to a model.
+const (
+	operationChat       = "chat"
+	operationEmbeddings = "embeddings"
+	operationRerank     = "rerank"
+	operationSegment    = "segment"
+)
+
+// Attribute keys from the OpenTelemetry semantic conventions for generative
+// AI systems. The conventions do not cover the number of model calls made by
+// an entry point, so those are recorded under the com.jetify.ai namespace.
+const (
+	attrOperationName         = attribute.Key("gen_ai.operation.name")
+	attrProviderName          = attribute.Key("gen_ai.provider.name")
+	attrRequestModel          = attribute.Key("gen_ai.request.model")
+	attrResponseFinishReasons = attribute.Key("gen_ai.response.finish_reasons")
+	attrUsageInputTokens      = attribute.Key("gen_ai.usage.input_tokens")
+	attrUsageOutputTokens     = attribute.Key("gen_ai.usage.output_tokens")
+	attrSteps                 = attribute.Key("com.jetify.ai.steps")
+	attrChunks                = attribute.Key("com.jetify.ai.chunks")
+	attrErrorType             = attribute.Key("error.type")
+)
+
+// entrySpan is the span of a call to one of the entry points of the package.
+//
+// It wraps the retries, tool steps and embedding chunks of the call: its
+// context is the one passed to the model, so the span of every model call
+// recorded by the telemetry package is one of its children. The entry span
+// records totals across those calls.
+type entrySpan struct {
+	span    trace.Span
+	endOnce sync.Once
+}
+
+// describedModel is implemented by every kind of model.
+type describedModel interface {
+	ProviderName() string
+	ModelID() string
+}
+
+// startSpan begins the span of the named entry point. A nil tp records
+// nothing.
+func startSpan(
+	ctx context.Context, tp trace.TracerProvider, name, operation string, model describedModel,
+) (context.Context, *entrySpan) {
+	if tp == nil {
+		tp = noop.NewTracerProvider()
+	}
+	attrs := []attribute.KeyValue{attrOperationName.String(operation)}
+	if model != nil {
+		attrs = append(attrs,
+			attrProviderName.String(model.ProviderName()),
+			attrRequestModel.String(model.ModelID()),
+		)
+	}
+	ctx, span := tp.Tracer(instrumentationName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx, &entrySpan{span: span}
+}
+
+// recordResponse records the finish reason of the last step, the number of
+// steps and the usage summed across them.
+func (s *entrySpan) recordResponse(resp *api.Response) {
+	if resp == nil {
+		return
+	}
+	s.span.SetAttributes(attrSteps.Int(max(len(resp.Steps), 1)))
+	s.recordFinish(resp.FinishReason, resp.Usage)
+}
+
+// recordChunks records the number of chunks an EmbedMany call is split into.
+func (s *entrySpan) recordChunks(n int) {
+	s.span.SetAttributes(attrChunks.Int(n))
+}
+
+func (s *entrySpan) recordFinish(reason api.FinishReason, usage api.Usage) {
+	if reason != "" {
+		s.span.SetAttributes(attrResponseFinishReasons.StringSlice([]string{string(reason)}))
+	}
+	s.recordUsage(usage.InputTokens, usage.OutputTokens)
+}
+
+// recordUsage records token counts. Zero counts are treated as not reported
+// by the provider.
+func (s *entrySpan) recordUsage(inputTokens, outputTokens int) {
+	if inputTokens > 0 {
+		s.span.SetAttributes(attrUsageInputTokens.Int(inputTokens))
+	}
+	if outputTokens > 0 {
+		s.span.SetAttributes(attrUsageOutputTokens.Int(outputTokens))
+	}
+}
+
+// traceStream returns a response whose stream ends the span once it is
+// exhausted or the consumer stops reading.
+func (s *entrySpan) traceStream(resp *api.StreamResponse) *api.StreamResponse {
+	if resp.Stream == nil || !s.span.IsRecording() {
+		s.end(nil)
+		return resp
+	}
+	traced := &tracedStream{span: s, stream: resp.Stream}
+	// A caller that never iterates the stream would otherwise leave the span
+	// open forever.
+	runtime.AddCleanup(traced, func(s *entrySpan) { s.end(nil) }, s)
+
+	tracedResp := *resp
+	tracedResp.Stream = traced.all
+	return &tracedResp
+}
+
+// tracedStream records the events of a stream on the span of its entry point.
+type tracedStream struct {
+	span   *entrySpan
+	stream iter.Seq[api.StreamEvent]
+}
+
+func (t *tracedStream) all(yield func(api.StreamEvent) bool) {
+	s := t.span
+	var streamErr error
+	defer func() { s.end(streamErr) }()
+
+	for event := range t.stream {
+		switch event := event.(type) {
+		case *api.FinishEvent:
+			s.recordFinish(event.FinishReason, event.Usage)
+		case *api.ErrorEvent:
+			streamErr = streamError(event)
+		}
+		if !yield(event) {
+			return
+		}
+	}
+}
+
+// end records the outcome of the call and ends the span. Only the first call
+// has an effect.
+func (s *entrySpan) end(err error) {
+	s.endOnce.Do(func() {
+		if err != nil {
+			s.span.SetAttributes(attrErrorType.String(fmt.Sprintf("%T", err)))
+			s.span.RecordError(err)
+			s.span.SetStatus(codes.Error, err.Error())
+		}
+		s.span.End()
+	})
+}
@@ -2,8 +2,10 @@ package ai
 
 import (
 	"net/http"
+	"time"
 
 	"go.jetify.com/ai/api"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // TransportOption mutates per-call transport configuration.
@@ -50,6 +52,74 @@ func WithTransportUseRawBaseURL() TransportOption {
 	}
 }
 
+// WithTransportTimeout bounds the duration of each provider call, so that a
+// slow call can be cut off without cancelling the caller's context. When
+// EmbedMany splits its input into chunks, the timeout applies to each chunk.
+// Only applies to HTTP-backed providers that support it.
+func WithTransportTimeout(timeout time.Duration) TransportOption {
+	return func(o *api.TransportOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// WithTransportMaxParallelCalls limits how many chunks EmbedMany sends to the
+// provider at the same time. It only has an effect on models that report
+// SupportsParallelCalls. A value of zero (the default) means no limit.
+func WithTransportMaxParallelCalls(n int) TransportOption {
+	return func(o *api.TransportOptions) {
+		o.MaxParallelCalls = n
+	}
+}
+
+// WithTransportMaxRetries sets the number of times a provider call is retried
+// when it fails with a retryable [api.APICallError]. Retries use exponential
+// backoff as described by [DefaultRetryPolicy], unless a policy was set with
+// [WithTransportRetryPolicy].
+func WithTransportMaxRetries(maxRetries int) TransportOption {
+	return func(o *api.TransportOptions) {
+		if o.RetryPolicy == (RetryPolicy{}) {
+			o.RetryPolicy = DefaultRetryPolicy()
+		}
+		o.RetryPolicy.MaxRetries = maxRetries
+	}
+}
+
+// WithTransportRetryPolicy sets the policy used to retry failed provider calls.
+func WithTransportRetryPolicy(policy RetryPolicy) TransportOption {
+	return func(o *api.TransportOptions) {
+		o.RetryPolicy = policy
+	}
+}
+
+// WithTransportTracerProvider makes EmbedMany, RankMany and SegmentMany record
+// a span with tp that covers every retry and embedding chunk of the call, and
+// records the token usage summed across them. Models wrapped by the telemetry
+// package record the span of each provider call as a child of it.
+func WithTransportTracerProvider(tp trace.TracerProvider) TransportOption {
+	return func(o *api.TransportOptions) {
+		o.TracerProvider = tp
+	}
+}
+
+// WithTopN limits the results returned by RankMany to the n most relevant
+// texts. It is sent to providers that support it, and applied to the results
+// of the others. Only applies to RankMany; other calls ignore it.
+func WithTopN(n int) TransportOption {
+	return func(o *api.TransportOptions) {
+		o.TopN = n
+	}
+}
+
+// WithReturnDocuments makes RankMany include the text of each result, so that
+// callers do not need to look it up by index. Providers that can echo the
+// texts are asked to; for the others the texts are filled in from the input.
+// Only applies to RankMany; other calls ignore it.
+func WithReturnDocuments() TransportOption {
+	return func(o *api.TransportOptions) {
+		o.ReturnDocuments = true
+	}
+}
+
 // buildTransportConfig combines multiple options into a single api.TransportOptions struct.
 func buildTransportConfig(opts []TransportOption) api.TransportOptions {
 	config := api.TransportOptions{}